@@ -0,0 +1,20 @@
+package constants
+
+import "time"
+
+const (
+	API_KEY_HEADER             = "X-API-Key"
+	API_KEY_PREFIX             = "edk_"
+	API_KEY_PREFIX_LENGTH      = 8
+	API_KEY_MAX_TTL_DAYS       = 365
+	API_KEY_LAST_USED_INTERVAL = time.Minute
+)
+
+// MapApiKeyPermission is the platform permissions an api key can be granted, a key is only scoped
+// to its shop on the product and warehouse services so other permissions must not be added here
+var MapApiKeyPermission = map[string]bool{
+	PERMISSION_PRODUCT_READ:    true,
+	PERMISSION_PRODUCT_WRITE:   true,
+	PERMISSION_WAREHOUSE_READ:  true,
+	PERMISSION_WAREHOUSE_WRITE: true,
+}
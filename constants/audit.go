@@ -0,0 +1,39 @@
+package constants
+
+const (
+	AUDIT_ACTION_LOGIN_LOCKOUT = "auth.login_lockout"
+
+	AUDIT_ACTION_SHOP_CREATE   = "shop.create"
+	AUDIT_ACTION_SHOP_UPDATE   = "shop.update"
+	AUDIT_ACTION_SHOP_CLOSE    = "shop.close"
+	AUDIT_ACTION_SHOP_REOPEN   = "shop.reopen"
+	AUDIT_ACTION_MEMBER_ADD    = "shop.member_add"
+	AUDIT_ACTION_MEMBER_UPDATE = "shop.member_update"
+	AUDIT_ACTION_MEMBER_REMOVE = "shop.member_remove"
+
+	AUDIT_ACTION_PRODUCT_CREATE = "product.create"
+
+	AUDIT_ACTION_WAREHOUSE_CREATE     = "warehouse.create"
+	AUDIT_ACTION_WAREHOUSE_UPDATE     = "warehouse.update"
+	AUDIT_ACTION_WAREHOUSE_ACTIVATE   = "warehouse.activate"
+	AUDIT_ACTION_WAREHOUSE_DEACTIVATE = "warehouse.deactivate"
+	AUDIT_ACTION_WAREHOUSE_DELETE     = "warehouse.delete"
+	AUDIT_ACTION_REORDER_THRESHOLD    = "stock.reorder_threshold"
+	AUDIT_ACTION_STOCK_TRANSFER       = "stock.transfer"
+
+	AUDIT_ACTION_ORDER_CREATE = "order.create"
+	AUDIT_ACTION_ORDER_PAY    = "order.pay"
+
+	AUDIT_RESOURCE_ACCOUNT     = "account"
+	AUDIT_RESOURCE_IP          = "ip"
+	AUDIT_RESOURCE_SHOP        = "shop"
+	AUDIT_RESOURCE_SHOP_MEMBER = "shop_member"
+	AUDIT_RESOURCE_PRODUCT     = "product"
+	AUDIT_RESOURCE_WAREHOUSE   = "warehouse"
+	AUDIT_RESOURCE_STOCK       = "stock_level"
+	AUDIT_RESOURCE_TRANSFER    = "stock_transfer"
+	AUDIT_RESOURCE_ORDER       = "order"
+
+	AUDIT_DEFAULT_LIMIT = 50
+	AUDIT_MAX_LIMIT     = 200
+)
@@ -6,20 +6,58 @@ var (
 	ErrorPostAlreadyInserted = errors.New("post already inserted")
 	ErrorPostNotFound        = errors.New("post not found")
 	RolePayloadInvalid       = errors.New("role payload invalid")
-	RoleUserInvalid          = errors.New("role user invalid to access this data")
 	FormatEmailInvalid       = errors.New("email format invalid")
 	FormatPhoneInvalid       = errors.New("phone number format invalid, range 8-15 numeric")
 	UserAlreadyInserted      = errors.New("user already inserted")
 	UserNotFound             = errors.New("user not found")
+	UserDeactivated          = errors.New("user account is deactivated")
+	PhoneAlreadyUsed         = errors.New("phone already used by another user")
+	PasswordNotChanged       = errors.New("new password must be different from current password")
 	ShopAlreadyInserted      = errors.New("shop already inserted")
 	ShopNotFound             = errors.New("shop not found")
+	ShopPermissionDenied     = errors.New("shop role is not allowed to do this action")
+	ShopRoleNotAvailable     = errors.New("shop role not available")
+	ShopMemberAlreadyExisted = errors.New("user is already a member of the shop")
+	ShopMemberNotFound       = errors.New("shop member not found")
+	ShopMemberNoBackOffice   = errors.New("user role has no access to the shop back office")
+	ShopLastOwner            = errors.New("shop must keep at least one owner")
+	ShopClosed               = errors.New("shop is closed")
+	ShopAlreadyClosed        = errors.New("shop already closed")
+	ShopAlreadyOpen          = errors.New("shop already open")
+	ShopUpdateEmpty          = errors.New("no shop field to update")
 	OrderNotFound            = errors.New("order not found")
+	AddressNotFound          = errors.New("address not found")
+	AddressRequired          = errors.New("shipping address required, please add an address first")
 	InvalidPassword          = errors.New("password invalid")
+	InvalidCredential        = errors.New("email or password invalid")
+	LoginLocked              = errors.New("too many failed login attempts, please try again later")
 	BearerExpired            = errors.New("bearer expired")
+	BearerRevoked            = errors.New("bearer revoked")
+	RefreshTokenInvalid      = errors.New("refresh token invalid")
+	RefreshTokenReused       = errors.New("refresh token already used, please login again")
+	VerificationNotAvailable = errors.New("verification channel not available")
+	VerificationCodeInvalid  = errors.New("verification code invalid or expired")
+	VerificationMaxAttempts  = errors.New("too many wrong verification code, please request a new code")
+	VerificationTooFrequent  = errors.New("verification code already sent, please wait before request again")
+	UserAlreadyVerified      = errors.New("user already verified")
+	UserNotVerified          = errors.New("user email or phone is not verified")
+	TwoFactorNotAvailable    = errors.New("two factor login is not available for this role")
+	TwoFactorAlreadyEnabled  = errors.New("two factor login already enabled")
+	TwoFactorNotEnrolled     = errors.New("two factor login is not enrolled, please enroll first")
+	TwoFactorNotEnabled      = errors.New("two factor login is not enabled")
+	TwoFactorCodeInvalid     = errors.New("two factor code invalid")
+	LoginChallengeInvalid    = errors.New("login challenge invalid or expired, please login again")
+	ApiKeyInvalid            = errors.New("api key invalid")
+	ApiKeyExpired            = errors.New("api key expired")
+	ApiKeyNotFound           = errors.New("api key not found")
+	ApiKeyPermissionInvalid  = errors.New("api key permission not available")
+	ApiKeyExpiryInvalid      = errors.New("api key expiry must be between 1 and 365 days")
+	AuditDateInvalid         = errors.New("audit date filter must use format YYYY-MM-DD")
 	ProductAlreadyInserted   = errors.New("product already inserted")
 	ProductNotFound          = errors.New("product not found")
 	WarehouseAlreadyExisted  = errors.New("warehouse already existed")
 	WarehouseNotFound        = errors.New("warehouse not found")
+	WarehouseStatusInvalid   = errors.New("warehouse status must be true or false")
 	FromWarehouseNotFound    = errors.New("from warehouse not found")
 	ToWarehouseNotFound      = errors.New("to warehouse not found")
 	DuplicateProduct         = errors.New("duplicate product")
@@ -28,4 +66,26 @@ var (
 	StockProductEmpty        = errors.New("stock product is empty")
 	NotEnoughStockProduct    = errors.New("not enough stock product")
 	NotEnoughStockToTransfer = errors.New("not enough stock to transfer")
+	StockLevelNotFound       = errors.New("stock level not found")
+	SupplierNotFound         = errors.New("supplier not found")
+	SupplierAlreadyExisted   = errors.New("supplier already existed")
+	PurchaseOrderNotFound    = errors.New("purchase order not found")
+	PurchaseOrderItemEmpty   = errors.New("purchase order must have at least one item")
+	PurchaseOrderItemInvalid = errors.New("purchase order item not found")
+	PurchaseOrderStatusFlow  = errors.New("purchase order status can not be changed to requested status")
+	ReceiveQtyExceeded       = errors.New("received qty exceeds ordered qty")
+	QtyMustPositive          = errors.New("qty must be greater than zero")
+	TransferNotFound         = errors.New("stock transfer not found")
+	TransferItemInvalid      = errors.New("stock transfer item not found")
+	TransferStatusFlow       = errors.New("stock transfer status can not be changed to requested status")
+	TransferSameWarehouse    = errors.New("can not transfer to the same warehouse")
+	ReservationNotFound      = errors.New("stock reservation not found")
+	WarehouseMustEmpty       = errors.New("warehouse stock must be empty before delete")
+	WarehouseOpenTransfer    = errors.New("warehouse still has stock transfer in transit")
+	WarehouseUpdateEmpty     = errors.New("no warehouse field to update")
+	WarehouseCapacityExceed  = errors.New("warehouse capacity exceeded")
+	WarehouseCapacityTooLow  = errors.New("warehouse capacity is lower than the capacity used")
+	WarehouseShopMismatch    = errors.New("warehouses belong to different shops")
+	ProductShopMismatch      = errors.New("product does not belong to the warehouse shop")
+	RateLimitExceeded        = errors.New("too many requests, please retry later")
 )
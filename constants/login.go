@@ -0,0 +1,23 @@
+package constants
+
+import "time"
+
+const (
+	LOGIN_SCOPE_ACCOUNT = "account"
+	LOGIN_SCOPE_IP      = "ip"
+
+	// failures older than the window are forgotten
+	LOGIN_FAILURE_WINDOW = 15 * time.Minute
+	// from this failure on the next attempt waits 1s, 2s, 4s ... up to LOGIN_MAX_DELAY
+	LOGIN_DELAY_AFTER = 3
+	LOGIN_MAX_DELAY   = 30 * time.Second
+
+	LOGIN_ACCOUNT_MAX_FAILURES = 10
+	LOGIN_IP_MAX_FAILURES      = 50
+	LOGIN_LOCKOUT_DURATION     = 15 * time.Minute
+)
+
+var MapLoginMaxFailures = map[string]int{
+	LOGIN_SCOPE_ACCOUNT: LOGIN_ACCOUNT_MAX_FAILURES,
+	LOGIN_SCOPE_IP:      LOGIN_IP_MAX_FAILURES,
+}
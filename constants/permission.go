@@ -0,0 +1,40 @@
+package constants
+
+// platform permissions are granted to the role of the user and checked per route by the Authorize middleware,
+// they are also the scopes of an api key. The shop_role permissions in role.go are a separate set, checked by
+// the services against the role of the member in the shop once the route let the request through
+const (
+	PERMISSION_PROFILE_READ    = "profile:read"
+	PERMISSION_PROFILE_WRITE   = "profile:write"
+	PERMISSION_ORDER_CREATE    = "order:create"
+	PERMISSION_ORDER_PAY       = "order:pay"
+	PERMISSION_SHOP_READ       = "shop:read"
+	PERMISSION_SHOP_WRITE      = "shop:write"
+	PERMISSION_SUPPLIER_READ   = "supplier:read"
+	PERMISSION_SUPPLIER_WRITE  = "supplier:write"
+	PERMISSION_PRODUCT_READ    = "product:read"
+	PERMISSION_PRODUCT_WRITE   = "product:write"
+	PERMISSION_WAREHOUSE_READ  = "warehouse:read"
+	PERMISSION_WAREHOUSE_WRITE = "warehouse:write"
+	PERMISSION_PURCHASE_READ   = "purchase:read"
+	PERMISSION_PURCHASE_WRITE  = "purchase:write"
+	PERMISSION_TRANSFER_READ   = "transfer:read"
+	PERMISSION_TRANSFER_WRITE  = "transfer:write"
+)
+
+var shopBackOfficePermissions = []string{
+	PERMISSION_PROFILE_READ, PERMISSION_PROFILE_WRITE,
+	PERMISSION_SHOP_READ, PERMISSION_SHOP_WRITE,
+	PERMISSION_SUPPLIER_READ, PERMISSION_SUPPLIER_WRITE,
+	PERMISSION_PRODUCT_READ, PERMISSION_PRODUCT_WRITE,
+	PERMISSION_WAREHOUSE_READ, PERMISSION_WAREHOUSE_WRITE,
+	PERMISSION_PURCHASE_READ, PERMISSION_PURCHASE_WRITE,
+	PERMISSION_TRANSFER_READ, PERMISSION_TRANSFER_WRITE,
+}
+
+// MapRolePermission is the default policy, it is replaced by the role_permissions table when the table has rows
+var MapRolePermission = map[string][]string{
+	ROLE_USER:        {PERMISSION_PROFILE_READ, PERMISSION_PROFILE_WRITE, PERMISSION_ORDER_CREATE, PERMISSION_ORDER_PAY},
+	ROLE_ADMIN_SHOP:  shopBackOfficePermissions,
+	ROLE_SUPER_ADMIN: append([]string{PERMISSION_ORDER_CREATE, PERMISSION_ORDER_PAY}, shopBackOfficePermissions...),
+}
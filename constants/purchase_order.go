@@ -0,0 +1,17 @@
+package constants
+
+const (
+	PO_STATUS_DRAFT              = "draft"
+	PO_STATUS_SENT               = "sent"
+	PO_STATUS_PARTIALLY_RECEIVED = "partially_received"
+	PO_STATUS_RECEIVED           = "received"
+	PO_STATUS_CLOSED             = "closed"
+)
+
+// MapPoStatusNext lists the statuses a purchase order may move to from its current status
+var MapPoStatusNext = map[string]map[string]bool{
+	PO_STATUS_DRAFT:              {PO_STATUS_SENT: true, PO_STATUS_CLOSED: true},
+	PO_STATUS_SENT:               {PO_STATUS_PARTIALLY_RECEIVED: true, PO_STATUS_RECEIVED: true, PO_STATUS_CLOSED: true},
+	PO_STATUS_PARTIALLY_RECEIVED: {PO_STATUS_PARTIALLY_RECEIVED: true, PO_STATUS_RECEIVED: true, PO_STATUS_CLOSED: true},
+	PO_STATUS_RECEIVED:           {PO_STATUS_CLOSED: true},
+}
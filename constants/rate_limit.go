@@ -0,0 +1,31 @@
+package constants
+
+import "time"
+
+const (
+	RATE_LIMIT_GROUP_DEFAULT = "default"
+	RATE_LIMIT_GROUP_LOGIN   = "login"
+	RATE_LIMIT_GROUP_ORDER   = "order"
+
+	// the client is the api key of the request, else the user of the bearer token, else the ip
+	RATE_LIMIT_KEY_IP     = "ip"
+	RATE_LIMIT_KEY_CLIENT = "client"
+
+	RATE_LIMIT_HEADER_LIMIT     = "RateLimit-Limit"
+	RATE_LIMIT_HEADER_REMAINING = "RateLimit-Remaining"
+	RATE_LIMIT_HEADER_RESET     = "RateLimit-Reset"
+	RATE_LIMIT_HEADER_POLICY    = "RateLimit-Policy"
+	RETRY_AFTER_HEADER          = "Retry-After"
+)
+
+type RateLimitQuota struct {
+	Limit  int
+	Period time.Duration
+	Key    string
+}
+
+var MapRateLimitQuota = map[string]RateLimitQuota{
+	RATE_LIMIT_GROUP_DEFAULT: {Limit: 120, Period: time.Minute, Key: RATE_LIMIT_KEY_CLIENT},
+	RATE_LIMIT_GROUP_LOGIN:   {Limit: 10, Period: time.Minute, Key: RATE_LIMIT_KEY_IP},
+	RATE_LIMIT_GROUP_ORDER:   {Limit: 10, Period: time.Minute, Key: RATE_LIMIT_KEY_CLIENT},
+}
@@ -0,0 +1,10 @@
+package constants
+
+const (
+	REQUEST_ID_HEADER = "X-Request-Id"
+	// REQUEST_ID_KEY is the key of the request id in the gin context
+	REQUEST_ID_KEY = "requestId"
+	// a request id sent by the client longer than this is replaced
+	REQUEST_ID_MAX_LENGTH = 64
+	REQUEST_ID_BYTES      = 16
+)
@@ -0,0 +1,10 @@
+package constants
+
+const (
+	RESERVATION_OWNER_ORDER = "order"
+	RESERVATION_OWNER_CART  = "cart"
+
+	RESERVATION_STATE_HELD      = "held"
+	RESERVATION_STATE_COMMITTED = "committed"
+	RESERVATION_STATE_RELEASED  = "released"
+)
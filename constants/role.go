@@ -1,8 +1,47 @@
 package constants
 
 const (
-	ROLE_USER       = "user"
-	ROLE_ADMIN_SHOP = "admin_shop"
+	ROLE_USER        = "user"
+	ROLE_ADMIN_SHOP  = "admin_shop"
+	ROLE_SUPER_ADMIN = "super_admin"
 )
 
+// MapRoleAvail is the roles a user can register with, super_admin is only granted from the database
 var MapRoleAvail = map[string]bool{ROLE_USER: true, ROLE_ADMIN_SHOP: true}
+
+const (
+	SHOP_ROLE_OWNER           = "owner"
+	SHOP_ROLE_MANAGER         = "manager"
+	SHOP_ROLE_WAREHOUSE_STAFF = "warehouse_staff"
+)
+
+var MapShopRoleAvail = map[string]bool{SHOP_ROLE_OWNER: true, SHOP_ROLE_MANAGER: true, SHOP_ROLE_WAREHOUSE_STAFF: true}
+
+// shop permissions are granted to the role of the member in a shop, see MapPermissionShopRole, they are checked
+// by the services and never by the Authorize middleware which only knows the platform permissions
+const (
+	SHOP_PERMISSION_VIEW             = "shop_role:view"
+	SHOP_PERMISSION_MANAGE           = "shop_role:manage"
+	SHOP_PERMISSION_MEMBER_MANAGE    = "shop_role:member_manage"
+	SHOP_PERMISSION_PRODUCT_MANAGE   = "shop_role:product_manage"
+	SHOP_PERMISSION_WAREHOUSE_MANAGE = "shop_role:warehouse_manage"
+	SHOP_PERMISSION_STOCK_ADJUST     = "shop_role:stock_adjust"
+	SHOP_PERMISSION_PURCHASE_MANAGE  = "shop_role:purchase_manage"
+	SHOP_PERMISSION_SUPPLIER_MANAGE  = "shop_role:supplier_manage"
+	SHOP_PERMISSION_API_KEY_MANAGE   = "shop_role:api_key_manage"
+	SHOP_PERMISSION_AUDIT_VIEW       = "shop_role:audit_view"
+)
+
+// MapPermissionShopRole lists the shop roles granted each permission
+var MapPermissionShopRole = map[string][]string{
+	SHOP_PERMISSION_VIEW:             {SHOP_ROLE_OWNER, SHOP_ROLE_MANAGER, SHOP_ROLE_WAREHOUSE_STAFF},
+	SHOP_PERMISSION_MANAGE:           {SHOP_ROLE_OWNER},
+	SHOP_PERMISSION_MEMBER_MANAGE:    {SHOP_ROLE_OWNER},
+	SHOP_PERMISSION_PRODUCT_MANAGE:   {SHOP_ROLE_OWNER, SHOP_ROLE_MANAGER},
+	SHOP_PERMISSION_WAREHOUSE_MANAGE: {SHOP_ROLE_OWNER, SHOP_ROLE_MANAGER},
+	SHOP_PERMISSION_STOCK_ADJUST:     {SHOP_ROLE_OWNER, SHOP_ROLE_MANAGER, SHOP_ROLE_WAREHOUSE_STAFF},
+	SHOP_PERMISSION_PURCHASE_MANAGE:  {SHOP_ROLE_OWNER, SHOP_ROLE_MANAGER},
+	SHOP_PERMISSION_SUPPLIER_MANAGE:  {SHOP_ROLE_OWNER, SHOP_ROLE_MANAGER},
+	SHOP_PERMISSION_API_KEY_MANAGE:   {SHOP_ROLE_OWNER},
+	SHOP_PERMISSION_AUDIT_VIEW:       {SHOP_ROLE_OWNER},
+}
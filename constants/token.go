@@ -0,0 +1,8 @@
+package constants
+
+import "time"
+
+const (
+	ACCESS_TOKEN_TTL  = 15 * time.Minute
+	REFRESH_TOKEN_TTL = 30 * 24 * time.Hour
+)
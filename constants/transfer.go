@@ -0,0 +1,18 @@
+package constants
+
+const (
+	TRANSFER_STATUS_DISPATCHED = "dispatched"
+	TRANSFER_STATUS_IN_TRANSIT = "in_transit"
+	TRANSFER_STATUS_RECEIVED   = "received"
+	TRANSFER_STATUS_CANCELLED  = "cancelled"
+)
+
+// TRANSFER_STATUS_OPEN lists the statuses of a transfer whose stock is still on the way
+var TRANSFER_STATUS_OPEN = []string{TRANSFER_STATUS_DISPATCHED, TRANSFER_STATUS_IN_TRANSIT}
+
+// MapTransferStatusNext lists the statuses a stock transfer may move to from its current status,
+// a partially received transfer stays in transit until every item arrived
+var MapTransferStatusNext = map[string]map[string]bool{
+	TRANSFER_STATUS_DISPATCHED: {TRANSFER_STATUS_IN_TRANSIT: true, TRANSFER_STATUS_RECEIVED: true, TRANSFER_STATUS_CANCELLED: true},
+	TRANSFER_STATUS_IN_TRANSIT: {TRANSFER_STATUS_IN_TRANSIT: true, TRANSFER_STATUS_RECEIVED: true, TRANSFER_STATUS_CANCELLED: true},
+}
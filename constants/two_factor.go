@@ -0,0 +1,16 @@
+package constants
+
+import "time"
+
+const (
+	TOTP_ISSUER = "test-edot"
+
+	RECOVERY_CODE_COUNT = 10
+	RECOVERY_CODE_BYTES = 5
+
+	LOGIN_CHALLENGE_TTL          = 5 * time.Minute
+	LOGIN_CHALLENGE_MAX_ATTEMPTS = 5
+)
+
+// MapRoleTwoFactor is the roles that can turn on two factor login
+var MapRoleTwoFactor = map[string]bool{ROLE_ADMIN_SHOP: true, ROLE_SUPER_ADMIN: true}
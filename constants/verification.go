@@ -0,0 +1,19 @@
+package constants
+
+import "time"
+
+const (
+	VERIFICATION_EMAIL          = "email"
+	VERIFICATION_PHONE          = "phone"
+	VERIFICATION_PASSWORD_RESET = "password_reset"
+
+	OTP_LENGTH          = 6
+	OTP_TTL             = 10 * time.Minute
+	OTP_MAX_ATTEMPTS    = 5
+	OTP_RESEND_INTERVAL = time.Minute
+)
+
+var MapVerificationChannel = map[string]bool{
+	VERIFICATION_EMAIL: true,
+	VERIFICATION_PHONE: true,
+}
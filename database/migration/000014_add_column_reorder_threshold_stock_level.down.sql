@@ -0,0 +1 @@
+ALTER TABLE `stock_levels` DROP COLUMN `reorder_threshold`;
\ No newline at end of file
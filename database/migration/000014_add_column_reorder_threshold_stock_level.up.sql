@@ -0,0 +1,2 @@
+ALTER TABLE `stock_levels`
+    ADD COLUMN `reorder_threshold` INT NOT NULL DEFAULT 0 AFTER `reserved_stock`;
\ No newline at end of file
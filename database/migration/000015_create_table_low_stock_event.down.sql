@@ -0,0 +1 @@
+DROP TABLE IF EXISTS `low_stock_events`;
\ No newline at end of file
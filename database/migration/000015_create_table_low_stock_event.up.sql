@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS `low_stock_events`(
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `stock_id` BIGINT UNSIGNED NOT NULL,
+    `product_id` BIGINT UNSIGNED NOT NULL,
+    `warehouse_id` BIGINT UNSIGNED NOT NULL,
+    `stock` INT NOT NULL DEFAULT 0,
+    `reorder_threshold` INT NOT NULL DEFAULT 0,
+    `is_notified` TINYINT NOT NULL DEFAULT 0,
+    `is_resolved` TINYINT NOT NULL DEFAULT 0,
+    `created_at` DATETIME NOT NULL,
+    `updated_at` DATETIME NOT NULL,
+    CONSTRAINT fk_low_stock_event_stock_id FOREIGN KEY (stock_id) REFERENCES stock_levels(id) ON DELETE CASCADE,
+    CONSTRAINT fk_low_stock_event_product_id FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE,
+    CONSTRAINT fk_low_stock_event_warehouse_id FOREIGN KEY (warehouse_id) REFERENCES warehouses(id) ON DELETE CASCADE
+);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS `suppliers`;
\ No newline at end of file
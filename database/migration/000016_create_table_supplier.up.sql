@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS `suppliers`(
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `shop_id` BIGINT UNSIGNED NOT NULL,
+    `name` VARCHAR(100) NOT NULL,
+    `email` VARCHAR(100) NULL,
+    `phone` VARCHAR(15) NULL,
+    `address` VARCHAR(255) NULL,
+    `is_active` TINYINT NOT NULL DEFAULT 1,
+    `created_at` DATETIME NOT NULL,
+    `updated_at` DATETIME NOT NULL,
+    CONSTRAINT fk_supplier_shop_id FOREIGN KEY (shop_id) REFERENCES shops(id) ON DELETE CASCADE
+);
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS `purchase_order_receipts`;
+DROP TABLE IF EXISTS `purchase_order_items`;
+DROP TABLE IF EXISTS `purchase_orders`;
\ No newline at end of file
@@ -0,0 +1,43 @@
+CREATE TABLE IF NOT EXISTS `purchase_orders`(
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `po_no` VARCHAR(30) NOT NULL,
+    `shop_id` BIGINT UNSIGNED NOT NULL,
+    `supplier_id` BIGINT UNSIGNED NOT NULL,
+    `warehouse_id` BIGINT UNSIGNED NOT NULL,
+    `user_id` BIGINT UNSIGNED NOT NULL,
+    `status` VARCHAR(20) NOT NULL,
+    `note` VARCHAR(255) NULL,
+    `total` DECIMAL(19,2) NOT NULL DEFAULT 0,
+    `created_at` DATETIME NOT NULL,
+    `updated_at` DATETIME NOT NULL,
+    CONSTRAINT fk_purchase_order_shop_id FOREIGN KEY (shop_id) REFERENCES shops(id) ON DELETE CASCADE,
+    CONSTRAINT fk_purchase_order_supplier_id FOREIGN KEY (supplier_id) REFERENCES suppliers(id) ON DELETE CASCADE,
+    CONSTRAINT fk_purchase_order_warehouse_id FOREIGN KEY (warehouse_id) REFERENCES warehouses(id) ON DELETE CASCADE,
+    CONSTRAINT fk_purchase_order_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS `purchase_order_items`(
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `purchase_order_id` BIGINT UNSIGNED NOT NULL,
+    `product_id` BIGINT UNSIGNED NOT NULL,
+    `qty` INT NOT NULL DEFAULT 0,
+    `received_qty` INT NOT NULL DEFAULT 0,
+    `price` DECIMAL(19,2) NOT NULL DEFAULT 0,
+    `created_at` DATETIME NOT NULL,
+    `updated_at` DATETIME NOT NULL,
+    CONSTRAINT fk_purchase_order_item_purchase_order_id FOREIGN KEY (purchase_order_id) REFERENCES purchase_orders(id) ON DELETE CASCADE,
+    CONSTRAINT fk_purchase_order_item_product_id FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS `purchase_order_receipts`(
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `purchase_order_id` BIGINT UNSIGNED NOT NULL,
+    `purchase_order_item_id` BIGINT UNSIGNED NOT NULL,
+    `stock_id` BIGINT UNSIGNED NOT NULL,
+    `qty` INT NOT NULL DEFAULT 0,
+    `user_id` BIGINT UNSIGNED NOT NULL,
+    `created_at` DATETIME NOT NULL,
+    CONSTRAINT fk_purchase_order_receipt_purchase_order_id FOREIGN KEY (purchase_order_id) REFERENCES purchase_orders(id) ON DELETE CASCADE,
+    CONSTRAINT fk_purchase_order_receipt_item_id FOREIGN KEY (purchase_order_item_id) REFERENCES purchase_order_items(id) ON DELETE CASCADE,
+    CONSTRAINT fk_purchase_order_receipt_stock_id FOREIGN KEY (stock_id) REFERENCES stock_levels(id) ON DELETE CASCADE
+);
\ No newline at end of file
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS `stock_transfer_items`;
+DROP TABLE IF EXISTS `stock_transfers`;
\ No newline at end of file
@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS `stock_transfers`(
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `transfer_no` VARCHAR(30) NOT NULL,
+    `from_warehouse_id` BIGINT UNSIGNED NOT NULL,
+    `to_warehouse_id` BIGINT UNSIGNED NOT NULL,
+    `user_id` BIGINT UNSIGNED NOT NULL,
+    `status` VARCHAR(20) NOT NULL,
+    `note` VARCHAR(255) NULL,
+    `dispatched_at` DATETIME NULL,
+    `received_at` DATETIME NULL,
+    `created_at` DATETIME NOT NULL,
+    `updated_at` DATETIME NOT NULL,
+    CONSTRAINT fk_stock_transfer_from_warehouse_id FOREIGN KEY (from_warehouse_id) REFERENCES warehouses(id) ON DELETE CASCADE,
+    CONSTRAINT fk_stock_transfer_to_warehouse_id FOREIGN KEY (to_warehouse_id) REFERENCES warehouses(id) ON DELETE CASCADE,
+    CONSTRAINT fk_stock_transfer_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS `stock_transfer_items`(
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `stock_transfer_id` BIGINT UNSIGNED NOT NULL,
+    `product_id` BIGINT UNSIGNED NOT NULL,
+    `qty` INT NOT NULL DEFAULT 0,
+    `received_qty` INT NOT NULL DEFAULT 0,
+    `created_at` DATETIME NOT NULL,
+    `updated_at` DATETIME NOT NULL,
+    CONSTRAINT fk_stock_transfer_item_stock_transfer_id FOREIGN KEY (stock_transfer_id) REFERENCES stock_transfers(id) ON DELETE CASCADE,
+    CONSTRAINT fk_stock_transfer_item_product_id FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS `stock_reservations`;
+
+ALTER TABLE `stock_transfer_items` DROP COLUMN `reserved_qty`;
\ No newline at end of file
@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS `stock_reservations`(
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `stock_id` BIGINT UNSIGNED NOT NULL,
+    `product_id` BIGINT UNSIGNED NOT NULL,
+    `warehouse_id` BIGINT UNSIGNED NOT NULL,
+    `stock_transfer_id` BIGINT UNSIGNED NULL,
+    `qty` INT NOT NULL DEFAULT 0,
+    `owner_type` VARCHAR(20) NOT NULL,
+    `owner_id` BIGINT UNSIGNED NOT NULL,
+    `state` VARCHAR(20) NOT NULL,
+    `expired_at` DATETIME NOT NULL,
+    `created_at` DATETIME NOT NULL,
+    `updated_at` DATETIME NOT NULL,
+    INDEX idx_stock_reservation_owner (owner_type, owner_id),
+    INDEX idx_stock_reservation_stock_state (stock_id, state),
+    INDEX idx_stock_reservation_transfer_state (stock_transfer_id, state),
+    CONSTRAINT fk_stock_reservation_stock_id FOREIGN KEY (stock_id) REFERENCES stock_levels(id) ON DELETE CASCADE,
+    CONSTRAINT fk_stock_reservation_product_id FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE,
+    CONSTRAINT fk_stock_reservation_warehouse_id FOREIGN KEY (warehouse_id) REFERENCES warehouses(id) ON DELETE CASCADE
+);
+
+INSERT INTO `stock_reservations` (`stock_id`, `product_id`, `warehouse_id`, `qty`, `owner_type`, `owner_id`, `state`, `expired_at`, `created_at`, `updated_at`)
+SELECT od.stock_id, od.product_id, sl.warehouse_id, od.qty, 'order', od.order_id, 'held', o.expired_at, NOW(), NOW()
+FROM `order_details` od
+JOIN `orders` o ON o.id = od.order_id
+JOIN `stock_levels` sl ON sl.id = od.stock_id
+WHERE o.is_payment = 0 AND o.is_release = 0;
+
+ALTER TABLE `stock_transfer_items` ADD COLUMN `reserved_qty` INT NOT NULL DEFAULT 0 AFTER `received_qty`;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE `warehouses`
+    ADD COLUMN `deleted_at` DATETIME NULL AFTER `updated_at`;
\ No newline at end of file
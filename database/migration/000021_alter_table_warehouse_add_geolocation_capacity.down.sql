@@ -0,0 +1,9 @@
+ALTER TABLE `warehouses`
+    DROP COLUMN `capacity`,
+    DROP COLUMN `address_country`,
+    DROP COLUMN `address_postal_code`,
+    DROP COLUMN `address_province`,
+    DROP COLUMN `address_city`,
+    DROP COLUMN `address_line`,
+    DROP COLUMN `longitude`,
+    DROP COLUMN `latitude`;
\ No newline at end of file
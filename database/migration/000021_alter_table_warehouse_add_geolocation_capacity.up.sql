@@ -0,0 +1,9 @@
+ALTER TABLE `warehouses`
+    ADD COLUMN `latitude` DECIMAL(10,7) NULL AFTER `location`,
+    ADD COLUMN `longitude` DECIMAL(10,7) NULL AFTER `latitude`,
+    ADD COLUMN `address_line` VARCHAR(255) NOT NULL DEFAULT '' AFTER `longitude`,
+    ADD COLUMN `address_city` VARCHAR(100) NOT NULL DEFAULT '' AFTER `address_line`,
+    ADD COLUMN `address_province` VARCHAR(100) NOT NULL DEFAULT '' AFTER `address_city`,
+    ADD COLUMN `address_postal_code` VARCHAR(20) NOT NULL DEFAULT '' AFTER `address_province`,
+    ADD COLUMN `address_country` VARCHAR(100) NOT NULL DEFAULT '' AFTER `address_postal_code`,
+    ADD COLUMN `capacity` INT NOT NULL DEFAULT 0 AFTER `address_country`;
\ No newline at end of file
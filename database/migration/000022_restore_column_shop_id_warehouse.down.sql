@@ -0,0 +1,5 @@
+ALTER TABLE `warehouses`
+    DROP FOREIGN KEY fk_warehouse_shop_id;
+
+ALTER TABLE `warehouses`
+    DROP COLUMN `shop_id`;
\ No newline at end of file
@@ -0,0 +1,26 @@
+-- every user gets a shop to own their warehouses when they have none yet
+INSERT INTO `shops` (`name`, `user_id`, `created_at`, `updated_at`)
+SELECT CONCAT(u.full_name, ' shop'), u.id, NOW(), NOW()
+FROM `users` u
+WHERE EXISTS (SELECT 1 FROM `warehouses` w WHERE w.user_id = u.id)
+AND NOT EXISTS (SELECT 1 FROM `shops` s WHERE s.user_id = u.id);
+
+ALTER TABLE `warehouses`
+    ADD COLUMN `shop_id` BIGINT UNSIGNED NULL AFTER `location`;
+
+-- a warehouse goes to the shop whose products it holds, the first shop of the user otherwise
+UPDATE `warehouses` w
+SET w.shop_id = (
+    SELECT MIN(p.shop_id) FROM `stock_levels` sl
+    JOIN `products` p ON p.id = sl.product_id
+    JOIN `shops` s ON s.id = p.shop_id
+    WHERE sl.warehouse_id = w.id AND s.user_id = w.user_id
+);
+
+UPDATE `warehouses` w
+SET w.shop_id = (SELECT MIN(s.id) FROM `shops` s WHERE s.user_id = w.user_id)
+WHERE w.shop_id IS NULL;
+
+ALTER TABLE `warehouses`
+    MODIFY COLUMN `shop_id` BIGINT UNSIGNED NOT NULL,
+    ADD CONSTRAINT fk_warehouse_shop_id FOREIGN KEY (shop_id) REFERENCES shops(id) ON DELETE CASCADE;
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS `shop_members`;
\ No newline at end of file
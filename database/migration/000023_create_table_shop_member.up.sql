@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS `shop_members`(
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `shop_id` BIGINT UNSIGNED NOT NULL,
+    `user_id` BIGINT UNSIGNED NOT NULL,
+    `role` VARCHAR(20) NOT NULL,
+    `created_at` DATETIME NOT NULL,
+    `updated_at` DATETIME NOT NULL,
+    UNIQUE INDEX idx_shop_member_shop_user (shop_id, user_id),
+    INDEX idx_shop_member_user (user_id),
+    CONSTRAINT fk_shop_member_shop_id FOREIGN KEY (shop_id) REFERENCES shops(id) ON DELETE CASCADE,
+    CONSTRAINT fk_shop_member_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+INSERT INTO `shop_members` (`shop_id`, `user_id`, `role`, `created_at`, `updated_at`)
+SELECT s.id, s.user_id, 'owner', NOW(), NOW()
+FROM `shops` s;
\ No newline at end of file
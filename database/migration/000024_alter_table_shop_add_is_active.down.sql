@@ -0,0 +1,3 @@
+ALTER TABLE `shops`
+    DROP COLUMN `closed_at`,
+    DROP COLUMN `is_active`;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE `shops`
+    ADD COLUMN `is_active` TINYINT NOT NULL DEFAULT 1 AFTER `location`,
+    ADD COLUMN `closed_at` DATETIME NULL AFTER `is_active`;
\ No newline at end of file
@@ -0,0 +1,41 @@
+CREATE TABLE IF NOT EXISTS `role_permissions`(
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `role` VARCHAR(20) NOT NULL,
+    `permission` VARCHAR(50) NOT NULL,
+    `created_at` DATETIME NOT NULL,
+    `updated_at` DATETIME NOT NULL,
+    UNIQUE INDEX idx_role_permission_role_permission (role, permission)
+);
+
+INSERT INTO `role_permissions` (`role`, `permission`, `created_at`, `updated_at`) VALUES
+    ('user', 'profile:read', NOW(), NOW()),
+    ('user', 'order:create', NOW(), NOW()),
+    ('user', 'order:pay', NOW(), NOW()),
+    ('admin_shop', 'profile:read', NOW(), NOW()),
+    ('admin_shop', 'shop:read', NOW(), NOW()),
+    ('admin_shop', 'shop:write', NOW(), NOW()),
+    ('admin_shop', 'supplier:read', NOW(), NOW()),
+    ('admin_shop', 'supplier:write', NOW(), NOW()),
+    ('admin_shop', 'product:read', NOW(), NOW()),
+    ('admin_shop', 'product:write', NOW(), NOW()),
+    ('admin_shop', 'warehouse:read', NOW(), NOW()),
+    ('admin_shop', 'warehouse:write', NOW(), NOW()),
+    ('admin_shop', 'purchase:read', NOW(), NOW()),
+    ('admin_shop', 'purchase:write', NOW(), NOW()),
+    ('admin_shop', 'transfer:read', NOW(), NOW()),
+    ('admin_shop', 'transfer:write', NOW(), NOW()),
+    ('super_admin', 'order:create', NOW(), NOW()),
+    ('super_admin', 'order:pay', NOW(), NOW()),
+    ('super_admin', 'profile:read', NOW(), NOW()),
+    ('super_admin', 'shop:read', NOW(), NOW()),
+    ('super_admin', 'shop:write', NOW(), NOW()),
+    ('super_admin', 'supplier:read', NOW(), NOW()),
+    ('super_admin', 'supplier:write', NOW(), NOW()),
+    ('super_admin', 'product:read', NOW(), NOW()),
+    ('super_admin', 'product:write', NOW(), NOW()),
+    ('super_admin', 'warehouse:read', NOW(), NOW()),
+    ('super_admin', 'warehouse:write', NOW(), NOW()),
+    ('super_admin', 'purchase:read', NOW(), NOW()),
+    ('super_admin', 'purchase:write', NOW(), NOW()),
+    ('super_admin', 'transfer:read', NOW(), NOW()),
+    ('super_admin', 'transfer:write', NOW(), NOW());
\ No newline at end of file
@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS `refresh_tokens`(
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `user_id` BIGINT UNSIGNED NOT NULL,
+    `family_id` VARCHAR(64) NOT NULL,
+    `token_hash` CHAR(64) NOT NULL,
+    `access_jti` VARCHAR(64) NOT NULL,
+    `expired_at` DATETIME NOT NULL,
+    `rotated_at` DATETIME NULL,
+    `revoked_at` DATETIME NULL,
+    `created_at` DATETIME NOT NULL,
+    `updated_at` DATETIME NOT NULL,
+    UNIQUE INDEX idx_refresh_token_token_hash (token_hash),
+    INDEX idx_refresh_token_family_id (family_id),
+    CONSTRAINT fk_refresh_token_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS `revoked_tokens`(
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `jti` VARCHAR(64) NOT NULL,
+    `user_id` BIGINT UNSIGNED NOT NULL,
+    `expired_at` DATETIME NOT NULL,
+    `created_at` DATETIME NOT NULL,
+    UNIQUE INDEX idx_revoked_token_jti (jti),
+    INDEX idx_revoked_token_expired_at (expired_at)
+);
\ No newline at end of file
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS `user_verifications`;
+ALTER TABLE `users` DROP COLUMN `phone_verified_at`, DROP COLUMN `email_verified_at`;
\ No newline at end of file
@@ -0,0 +1,18 @@
+ALTER TABLE `users` ADD COLUMN `email_verified_at` DATETIME NULL AFTER `phone`,
+    ADD COLUMN `phone_verified_at` DATETIME NULL AFTER `email_verified_at`;
+
+CREATE TABLE IF NOT EXISTS `user_verifications`(
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `user_id` BIGINT UNSIGNED NOT NULL,
+    `purpose` VARCHAR(20) NOT NULL,
+    `target` VARCHAR(100) NOT NULL,
+    `code_hash` CHAR(64) NOT NULL,
+    `attempts` INT NOT NULL DEFAULT 0,
+    `expired_at` DATETIME NOT NULL,
+    `used_at` DATETIME NULL,
+    `created_at` DATETIME NOT NULL,
+    `updated_at` DATETIME NOT NULL,
+    INDEX idx_user_verification_user_purpose (user_id, purpose),
+    INDEX idx_user_verification_expired_at (expired_at),
+    CONSTRAINT fk_user_verification_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
\ No newline at end of file
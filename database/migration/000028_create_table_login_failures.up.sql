@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS `login_failures`(
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `scope` VARCHAR(10) NOT NULL,
+    `identifier` VARCHAR(100) NOT NULL,
+    `failed_count` INT NOT NULL DEFAULT 0,
+    `last_failed_at` DATETIME NULL,
+    `locked_until` DATETIME NULL,
+    `created_at` DATETIME NOT NULL,
+    `updated_at` DATETIME NOT NULL,
+    UNIQUE INDEX idx_login_failure_scope_identifier (scope, identifier)
+);
+
+CREATE TABLE IF NOT EXISTS `audit_events`(
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `actor_id` BIGINT UNSIGNED NULL,
+    `actor_role` VARCHAR(20) NOT NULL DEFAULT '',
+    `action` VARCHAR(50) NOT NULL,
+    `resource_type` VARCHAR(30) NOT NULL,
+    `resource_id` VARCHAR(100) NOT NULL,
+    `before_value` JSON NULL,
+    `after_value` JSON NULL,
+    `ip` VARCHAR(45) NOT NULL DEFAULT '',
+    `request_id` VARCHAR(64) NOT NULL DEFAULT '',
+    `created_at` DATETIME NOT NULL,
+    INDEX idx_audit_event_resource (resource_type, resource_id),
+    INDEX idx_audit_event_actor_id (actor_id),
+    INDEX idx_audit_event_created_at (created_at)
+);
\ No newline at end of file
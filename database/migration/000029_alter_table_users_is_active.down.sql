@@ -0,0 +1,5 @@
+DELETE FROM `role_permissions` WHERE `permission` = 'profile:write';
+
+ALTER TABLE `users`
+    DROP COLUMN `deactivated_at`,
+    DROP COLUMN `is_active`;
\ No newline at end of file
@@ -0,0 +1,8 @@
+ALTER TABLE `users`
+    ADD COLUMN `is_active` TINYINT NOT NULL DEFAULT 1 AFTER `role`,
+    ADD COLUMN `deactivated_at` DATETIME NULL AFTER `is_active`;
+
+INSERT INTO `role_permissions` (`role`, `permission`, `created_at`, `updated_at`) VALUES
+    ('user', 'profile:write', NOW(), NOW()),
+    ('admin_shop', 'profile:write', NOW(), NOW()),
+    ('super_admin', 'profile:write', NOW(), NOW());
\ No newline at end of file
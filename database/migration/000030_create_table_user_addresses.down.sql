@@ -0,0 +1,13 @@
+ALTER TABLE `orders`
+    DROP COLUMN `shipping_longitude`,
+    DROP COLUMN `shipping_latitude`,
+    DROP COLUMN `shipping_country`,
+    DROP COLUMN `shipping_postal_code`,
+    DROP COLUMN `shipping_province`,
+    DROP COLUMN `shipping_city`,
+    DROP COLUMN `shipping_line`,
+    DROP COLUMN `shipping_phone`,
+    DROP COLUMN `shipping_recipient_name`,
+    DROP COLUMN `address_id`;
+
+DROP TABLE IF EXISTS `user_addresses`;
\ No newline at end of file
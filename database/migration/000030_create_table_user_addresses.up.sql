@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS `user_addresses`(
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `user_id` BIGINT UNSIGNED NOT NULL,
+    `label` VARCHAR(50) NOT NULL DEFAULT '',
+    `recipient_name` VARCHAR(100) NOT NULL,
+    `phone` VARCHAR(15) NOT NULL,
+    `line` VARCHAR(255) NOT NULL,
+    `city` VARCHAR(100) NOT NULL,
+    `province` VARCHAR(100) NOT NULL,
+    `postal_code` VARCHAR(20) NOT NULL,
+    `country` VARCHAR(100) NOT NULL,
+    `latitude` DECIMAL(10,7) NULL,
+    `longitude` DECIMAL(10,7) NULL,
+    `is_default` TINYINT NOT NULL DEFAULT 0,
+    `created_at` DATETIME NOT NULL,
+    `updated_at` DATETIME NOT NULL,
+    INDEX idx_user_address_user_id (user_id),
+    CONSTRAINT fk_user_address_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- the address is copied on the order, editing or deleting it later does not change the order
+ALTER TABLE `orders`
+    ADD COLUMN `address_id` BIGINT UNSIGNED NULL AFTER `user_id`,
+    ADD COLUMN `shipping_recipient_name` VARCHAR(100) NOT NULL DEFAULT '' AFTER `total`,
+    ADD COLUMN `shipping_phone` VARCHAR(15) NOT NULL DEFAULT '' AFTER `shipping_recipient_name`,
+    ADD COLUMN `shipping_line` VARCHAR(255) NOT NULL DEFAULT '' AFTER `shipping_phone`,
+    ADD COLUMN `shipping_city` VARCHAR(100) NOT NULL DEFAULT '' AFTER `shipping_line`,
+    ADD COLUMN `shipping_province` VARCHAR(100) NOT NULL DEFAULT '' AFTER `shipping_city`,
+    ADD COLUMN `shipping_postal_code` VARCHAR(20) NOT NULL DEFAULT '' AFTER `shipping_province`,
+    ADD COLUMN `shipping_country` VARCHAR(100) NOT NULL DEFAULT '' AFTER `shipping_postal_code`,
+    ADD COLUMN `shipping_latitude` DECIMAL(10,7) NULL AFTER `shipping_country`,
+    ADD COLUMN `shipping_longitude` DECIMAL(10,7) NULL AFTER `shipping_latitude`;
\ No newline at end of file
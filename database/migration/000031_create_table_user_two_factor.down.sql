@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS `login_challenges`;
+DROP TABLE IF EXISTS `user_recovery_codes`;
+ALTER TABLE `users` DROP COLUMN `totp_last_step`, DROP COLUMN `totp_enabled_at`, DROP COLUMN `totp_secret`;
\ No newline at end of file
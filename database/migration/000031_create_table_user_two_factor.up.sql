@@ -0,0 +1,26 @@
+ALTER TABLE `users` ADD COLUMN `totp_secret` VARCHAR(64) NULL AFTER `phone_verified_at`,
+    ADD COLUMN `totp_enabled_at` DATETIME NULL AFTER `totp_secret`,
+    ADD COLUMN `totp_last_step` BIGINT NOT NULL DEFAULT 0 AFTER `totp_enabled_at`;
+
+CREATE TABLE IF NOT EXISTS `user_recovery_codes`(
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `user_id` BIGINT UNSIGNED NOT NULL,
+    `code_hash` CHAR(64) NOT NULL,
+    `used_at` DATETIME NULL,
+    `created_at` DATETIME NOT NULL,
+    UNIQUE INDEX idx_user_recovery_code_hash (user_id, code_hash),
+    CONSTRAINT fk_user_recovery_code_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS `login_challenges`(
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `user_id` BIGINT UNSIGNED NOT NULL,
+    `token_hash` CHAR(64) NOT NULL,
+    `attempts` INT NOT NULL DEFAULT 0,
+    `expired_at` DATETIME NOT NULL,
+    `used_at` DATETIME NULL,
+    `created_at` DATETIME NOT NULL,
+    UNIQUE INDEX idx_login_challenge_token_hash (token_hash),
+    INDEX idx_login_challenge_expired_at (expired_at),
+    CONSTRAINT fk_login_challenge_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
\ No newline at end of file
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS `api_keys`(
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `shop_id` BIGINT UNSIGNED NOT NULL,
+    `created_by` BIGINT UNSIGNED NOT NULL,
+    `name` VARCHAR(100) NOT NULL,
+    `prefix` VARCHAR(20) NOT NULL,
+    `key_hash` CHAR(64) NOT NULL,
+    `permissions` VARCHAR(255) NOT NULL,
+    `expired_at` DATETIME NOT NULL,
+    `last_used_at` DATETIME NULL,
+    `revoked_at` DATETIME NULL,
+    `created_at` DATETIME NOT NULL,
+    `updated_at` DATETIME NOT NULL,
+    UNIQUE INDEX idx_api_key_key_hash (key_hash),
+    INDEX idx_api_key_shop_id (shop_id),
+    CONSTRAINT fk_api_key_shop_id FOREIGN KEY (shop_id) REFERENCES shops(id) ON DELETE CASCADE,
+    CONSTRAINT fk_api_key_created_by FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE CASCADE
+);
\ No newline at end of file
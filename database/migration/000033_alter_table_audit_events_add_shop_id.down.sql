@@ -0,0 +1,3 @@
+ALTER TABLE `audit_events` DROP INDEX idx_audit_event_shop_created_at,
+    DROP COLUMN `api_key_id`,
+    DROP COLUMN `shop_id`;
\ No newline at end of file
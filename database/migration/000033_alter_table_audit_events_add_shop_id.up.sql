@@ -0,0 +1,3 @@
+ALTER TABLE `audit_events` ADD COLUMN `shop_id` BIGINT UNSIGNED NULL AFTER `id`,
+    ADD COLUMN `api_key_id` BIGINT UNSIGNED NULL AFTER `actor_role`,
+    ADD INDEX idx_audit_event_shop_created_at (shop_id, created_at);
\ No newline at end of file
@@ -5,6 +5,8 @@ import (
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 	"sync"
+	"test-edot/metrics"
+	"test-edot/src/tracing"
 	"test-edot/util"
 )
 
@@ -66,6 +68,16 @@ func ConnMYSQL(conf dbConfig) {
 		panic(err)
 	}
 
+	if err := db.Use(tracing.GormPlugin{}); err != nil {
+		panic(err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		panic(err)
+	}
+	metrics.RegisterDBStats(sqlDB)
+
 	dbConn = db
 	fmt.Println("success connect to MYSQL")
 }
@@ -1,14 +1,17 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
 	"log"
 	"test-edot/database"
 	"test-edot/metrics"
 	"test-edot/src/factory"
 	"test-edot/src/http"
 	"test-edot/src/scheduler"
+	"test-edot/src/tracing"
 	"test-edot/util"
 )
 
@@ -26,6 +29,13 @@ func main() {
 	flag.Parse()
 
 	f := factory.NewFactory()
+
+	shutdownTracing, err := tracing.Init(context.Background())
+	if err != nil {
+		f.Log.Fatal("error init tracing", zap.Error(err))
+	}
+	defer shutdownTracing(context.Background())
+
 	if m == "scheduler" {
 		scheduler.RunScheduler(f)
 		return
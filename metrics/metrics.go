@@ -1,21 +1,59 @@
 package metrics
 
 import (
+	"database/sql"
 	"github.com/gin-gonic/gin"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 var (
-	ActivityMonitor = prometheus.NewGaugeVec(prometheus.GaugeOpts{
-		Name: "gin_monitoring",
-		Help: "Counting monitoring of gin apps",
-	}, []string{"code", "path", "method"})
-
-	EventTransactionMonitMonitor = prometheus.NewGaugeVec(prometheus.GaugeOpts{
-		Name: "event_transaction_monitoring",
-		Help: "Counting monitoring of monit transaction event",
+	HttpRequestTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "Number of http requests by method, route template and status",
+	}, []string{"method", "route", "status"})
+
+	HttpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Latency of http requests by method, route template and status",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	OrderTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "orders_total",
+		Help: "Number of orders by event, created, paid or expired",
+	}, []string{"event"})
+
+	StockReservationQtyTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "stock_reservation_qty_total",
+		Help: "Qty of stock reserved by orders or released back by expired orders",
 	}, []string{"event"})
+
+	StockTransferTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "stock_transfers_total",
+		Help: "Number of stock transfers by status, dispatched, received or cancelled",
+	}, []string{"status"})
+
+	StockReconciliationMismatch = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "stock_reconciliation_mismatch",
+		Help: "Difference between recorded and expected reserved stock per stock level",
+	}, []string{"stock_id", "warehouse_id", "product_id"})
+
+	StockReconciliationMismatchTotal = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "stock_reconciliation_mismatch_total",
+		Help: "Number of stock levels drifting from order data on the last reconciliation",
+	})
+
+	LoginFailureTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "login_failure_total",
+		Help: "Number of rejected login attempts by reason",
+	}, []string{"reason"})
+
+	LoginLockoutTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "login_lockout_total",
+		Help: "Number of login lockouts by scope, account or ip",
+	}, []string{"scope"})
 )
 
 func PrometheusHandler() gin.HandlerFunc {
@@ -27,11 +65,27 @@ func PrometheusHandler() gin.HandlerFunc {
 }
 
 func RegisterPrometheus() {
-	if err := prometheus.Register(ActivityMonitor); err != nil {
-		return
+	for _, collector := range []prometheus.Collector{
+		HttpRequestTotal,
+		HttpRequestDuration,
+		OrderTotal,
+		StockReservationQtyTotal,
+		StockTransferTotal,
+		StockReconciliationMismatch,
+		StockReconciliationMismatchTotal,
+		LoginFailureTotal,
+		LoginLockoutTotal,
+	} {
+		if err := prometheus.Register(collector); err != nil {
+			return
+		}
 	}
+}
 
-	if err := prometheus.Register(EventTransactionMonitMonitor); err != nil {
+// RegisterDBStats exports the connection pool stats of db, open, in use and idle connections and the time spent
+// waiting for one, under the go_sql_* metrics
+func RegisterDBStats(db *sql.DB) {
+	if err := prometheus.Register(collectors.NewDBStatsCollector(db, "test-edot")); err != nil {
 		return
 	}
 }
@@ -0,0 +1,96 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often the memory store drops the buckets that are full again
+const sweepInterval = time.Minute
+
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+	period    time.Duration
+	limit     int
+}
+
+// MemoryStore keeps the buckets in the process, every instance of the app has its own buckets
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	now     func() time.Time
+	sweptAt time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: map[string]*bucket{},
+		now:     time.Now,
+	}
+}
+
+func (s *MemoryStore) Take(ctx context.Context, key string, limit int, period time.Duration) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit), updatedAt: now}
+		s.buckets[key] = b
+	}
+	b.limit, b.period = limit, period
+	b.refill(now)
+
+	res := Result{Limit: limit}
+	if b.tokens >= 1 {
+		b.tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = b.timeFor(1 - b.tokens)
+	}
+
+	res.Remaining = int(math.Floor(b.tokens))
+	res.Reset = b.timeFor(float64(limit) - b.tokens)
+
+	return res, nil
+}
+
+func (b *bucket) refill(now time.Time) {
+	elapsed := now.Sub(b.updatedAt)
+	if elapsed <= 0 {
+		return
+	}
+
+	b.tokens = math.Min(float64(b.limit), b.tokens+elapsed.Seconds()*float64(b.limit)/b.period.Seconds())
+	b.updatedAt = now
+}
+
+// timeFor is the time the bucket needs to get the tokens back
+func (b *bucket) timeFor(tokens float64) time.Duration {
+	if tokens <= 0 {
+		return 0
+	}
+
+	return time.Duration(math.Ceil(tokens * float64(b.period) / float64(b.limit)))
+}
+
+// sweep drops the full buckets, a missing bucket starts full so forgetting them changes nothing
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.sweptAt) < sweepInterval {
+		return
+	}
+
+	for key, b := range s.buckets {
+		b.refill(now)
+		if b.tokens >= float64(b.limit) {
+			delete(s.buckets, key)
+		}
+	}
+	s.sweptAt = now
+}
@@ -0,0 +1,62 @@
+package ratelimit
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+type (
+	TestMemoryStoreTakeData struct {
+		name            string
+		after           time.Duration
+		expectAllowed   bool
+		expectRemaining int
+		expectRetry     time.Duration
+	}
+)
+
+func TestMemoryStoreTake(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+
+	// a bucket of 3 tokens getting a token back every 20s
+	tableTests := []TestMemoryStoreTakeData{
+		{name: "test first request", expectAllowed: true, expectRemaining: 2},
+		{name: "test second request", expectAllowed: true, expectRemaining: 1},
+		{name: "test third request", expectAllowed: true, expectRemaining: 0},
+		{name: "test empty bucket", expectAllowed: false, expectRemaining: 0, expectRetry: 20 * time.Second},
+		{name: "test half refilled", after: 10 * time.Second, expectAllowed: false, expectRemaining: 0, expectRetry: 10 * time.Second},
+		{name: "test one token refilled", after: 10 * time.Second, expectAllowed: true, expectRemaining: 0},
+		{name: "test refill stops at the limit", after: time.Hour, expectAllowed: true, expectRemaining: 2},
+	}
+
+	for _, test := range tableTests {
+		t.Run(test.name, func(t *testing.T) {
+			now = now.Add(test.after)
+
+			res, err := store.Take(context.Background(), "ip:127.0.0.1", 3, time.Minute)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectAllowed, res.Allowed)
+			assert.Equal(t, test.expectRemaining, res.Remaining)
+			assert.Equal(t, test.expectRetry, res.RetryAfter)
+			assert.Equal(t, 3, res.Limit)
+		})
+	}
+}
+
+func TestMemoryStoreSweep(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+
+	_, _ = store.Take(context.Background(), "ip:127.0.0.1", 3, time.Minute)
+	_, _ = store.Take(context.Background(), "ip:127.0.0.2", 3, time.Minute)
+	assert.Len(t, store.buckets, 2)
+
+	now = now.Add(2 * time.Minute)
+	_, _ = store.Take(context.Background(), "ip:127.0.0.3", 3, time.Minute)
+	assert.Len(t, store.buckets, 1)
+}
@@ -0,0 +1,21 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Store keeps a token bucket per key, a bucket holds up to limit tokens and gets limit tokens back every period
+type Store interface {
+	Take(ctx context.Context, key string, limit int, period time.Duration) (Result, error)
+}
+
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is the time until the bucket is full again
+	Reset time.Duration
+	// RetryAfter is the time until the next token when the request is not allowed
+	RetryAfter time.Duration
+}
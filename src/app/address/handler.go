@@ -0,0 +1,171 @@
+package address
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"test-edot/src/dto"
+	"test-edot/src/factory"
+)
+
+type handler struct {
+	service Service
+}
+
+func NewHandler(f *factory.Factory) *handler {
+	return &handler{
+		service: NewService(f),
+	}
+}
+
+func (h *handler) AddAddress(g *gin.Context) {
+	userClaim := g.Value("userClaim").(dto.UserClaimJwt)
+
+	var payload dto.PayloadAddress
+	if err := g.ShouldBindJSON(&payload); err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	res, err := h.service.AddAddress(g, userClaim, payload)
+	if err != nil {
+		g.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	g.JSON(http.StatusCreated, dto.Response{
+		Message: "success create address",
+		Data:    res,
+	})
+	return
+}
+
+func (h *handler) GetAddresses(g *gin.Context) {
+	userClaim := g.Value("userClaim").(dto.UserClaimJwt)
+
+	res, err := h.service.GetAddresses(g, userClaim)
+	if err != nil {
+		g.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	g.JSON(http.StatusOK, dto.Response{
+		Message: "success get addresses",
+		Data:    res,
+	})
+	return
+}
+
+func (h *handler) GetAddress(g *gin.Context) {
+	userClaim := g.Value("userClaim").(dto.UserClaimJwt)
+
+	addressId, err := strconv.Atoi(g.Param("address_id"))
+	if err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: "address_id is not valid",
+		})
+		return
+	}
+
+	res, err := h.service.GetAddress(g, userClaim, addressId)
+	if err != nil {
+		g.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	g.JSON(http.StatusOK, dto.Response{
+		Message: "success get address",
+		Data:    res,
+	})
+	return
+}
+
+func (h *handler) UpdateAddress(g *gin.Context) {
+	userClaim := g.Value("userClaim").(dto.UserClaimJwt)
+
+	addressId, err := strconv.Atoi(g.Param("address_id"))
+	if err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: "address_id is not valid",
+		})
+		return
+	}
+
+	var payload dto.PayloadAddress
+	if err := g.ShouldBindJSON(&payload); err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	res, err := h.service.UpdateAddress(g, userClaim, addressId, payload)
+	if err != nil {
+		g.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	g.JSON(http.StatusOK, dto.Response{
+		Message: "success update address",
+		Data:    res,
+	})
+	return
+}
+
+func (h *handler) SetDefaultAddress(g *gin.Context) {
+	userClaim := g.Value("userClaim").(dto.UserClaimJwt)
+
+	addressId, err := strconv.Atoi(g.Param("address_id"))
+	if err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: "address_id is not valid",
+		})
+		return
+	}
+
+	if err := h.service.SetDefaultAddress(g, userClaim, addressId); err != nil {
+		g.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	g.JSON(http.StatusOK, dto.Response{
+		Message: "success set default address",
+	})
+	return
+}
+
+func (h *handler) DeleteAddress(g *gin.Context) {
+	userClaim := g.Value("userClaim").(dto.UserClaimJwt)
+
+	addressId, err := strconv.Atoi(g.Param("address_id"))
+	if err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: "address_id is not valid",
+		})
+		return
+	}
+
+	if err := h.service.DeleteAddress(g, userClaim, addressId); err != nil {
+		g.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	g.JSON(http.StatusOK, dto.Response{
+		Message: "success delete address",
+	})
+	return
+}
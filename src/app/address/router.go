@@ -0,0 +1,16 @@
+package address
+
+import (
+	"github.com/gin-gonic/gin"
+	"test-edot/constants"
+	"test-edot/src/middleware"
+)
+
+func (h *handler) AddressRouter(g *gin.RouterGroup) {
+	g.POST("", middleware.Authorize(constants.PERMISSION_PROFILE_WRITE), h.AddAddress)
+	g.GET("", middleware.Authorize(constants.PERMISSION_PROFILE_READ), h.GetAddresses)
+	g.GET("/:address_id", middleware.Authorize(constants.PERMISSION_PROFILE_READ), h.GetAddress)
+	g.PUT("/:address_id", middleware.Authorize(constants.PERMISSION_PROFILE_WRITE), h.UpdateAddress)
+	g.PUT("/:address_id/default", middleware.Authorize(constants.PERMISSION_PROFILE_WRITE), h.SetDefaultAddress)
+	g.DELETE("/:address_id", middleware.Authorize(constants.PERMISSION_PROFILE_WRITE), h.DeleteAddress)
+}
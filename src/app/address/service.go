@@ -0,0 +1,342 @@
+package address
+
+import (
+	"context"
+	"errors"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"test-edot/constants"
+	"test-edot/src/dto"
+	"test-edot/src/factory"
+	"test-edot/src/models"
+	"test-edot/src/repository"
+	"test-edot/src/tracing"
+	"test-edot/util"
+	"time"
+)
+
+const addressFields = "recipient_name,phone,line,city,province,postal_code,country,latitude,longitude"
+
+type Service interface {
+	AddAddress(ctx context.Context, userClaim dto.UserClaimJwt, payload dto.PayloadAddress) (models.UserAddress, error)
+	GetAddresses(ctx context.Context, userClaim dto.UserClaimJwt) ([]models.UserAddress, error)
+	GetAddress(ctx context.Context, userClaim dto.UserClaimJwt, addressId int) (models.UserAddress, error)
+	UpdateAddress(ctx context.Context, userClaim dto.UserClaimJwt, addressId int, payload dto.PayloadAddress) (models.UserAddress, error)
+	SetDefaultAddress(ctx context.Context, userClaim dto.UserClaimJwt, addressId int) error
+	DeleteAddress(ctx context.Context, userClaim dto.UserClaimJwt, addressId int) error
+	FindShippingAddress(ctx context.Context, userClaim dto.UserClaimJwt, addressId int) (models.UserAddress, error)
+}
+
+type service struct {
+	Log                   *zap.Logger
+	UserAddressRepository repository.UserAddressRepositoryInterface
+}
+
+func NewService(f *factory.Factory) Service {
+	return &service{
+		Log:                   f.Log,
+		UserAddressRepository: f.UserAddressRepository,
+	}
+}
+
+// log is the logger of the request that made the call, see middleware.RequestId
+func (s *service) log(ctx context.Context) *zap.Logger {
+	return util.Logger(ctx, s.Log)
+}
+
+func toShippingAddress(payload dto.PayloadAddress) models.ShippingAddress {
+	return models.ShippingAddress{
+		RecipientName: payload.RecipientName,
+		Phone:         payload.Phone,
+		Line:          payload.Line,
+		City:          payload.City,
+		Province:      payload.Province,
+		PostalCode:    payload.PostalCode,
+		Country:       payload.Country,
+		Latitude:      payload.Latitude,
+		Longitude:     payload.Longitude,
+	}
+}
+
+// AddAddress the first address of the user is always the default one
+func (s *service) AddAddress(ctx context.Context, userClaim dto.UserClaimJwt, payload dto.PayloadAddress) (models.UserAddress, error) {
+	ctx, span := tracing.Start(ctx, "address.AddAddress")
+	defer span.End()
+
+	tx := s.UserAddressRepository.Begin().WithContext(ctx)
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		s.log(ctx).Error("error begin transaction", zap.Error(err))
+		return models.UserAddress{}, err
+	}
+
+	addresses, err := s.UserAddressRepository.FindTx(tx, "id,is_default", "user_id = ?", userClaim.UserId)
+	if err != nil {
+		tx.Rollback()
+		s.log(ctx).Error("error get user address", zap.Error(err))
+		return models.UserAddress{}, err
+	}
+
+	now := time.Now().In(util.LocationTime)
+	address := models.UserAddress{
+		UserId:    userClaim.UserId,
+		Label:     payload.Label,
+		Address:   toShippingAddress(payload),
+		IsDefault: payload.IsDefault || len(addresses) == 0,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if address.IsDefault {
+		if err := s.ClearDefaultTx(tx, userClaim.UserId); err != nil {
+			tx.Rollback()
+			return models.UserAddress{}, err
+		}
+	}
+
+	if err := s.UserAddressRepository.Create(tx, &address); err != nil {
+		tx.Rollback()
+		s.log(ctx).Error("error creating user address", zap.Error(err))
+		return models.UserAddress{}, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		s.log(ctx).Error("error commit transaction", zap.Error(err))
+		return models.UserAddress{}, err
+	}
+
+	return address, nil
+}
+
+func (s *service) GetAddresses(ctx context.Context, userClaim dto.UserClaimJwt) ([]models.UserAddress, error) {
+	ctx, span := tracing.Start(ctx, "address.GetAddresses")
+	defer span.End()
+
+	addresses, err := s.UserAddressRepository.Find(ctx, "*", "user_id = ?", userClaim.UserId)
+	if err != nil {
+		s.log(ctx).Error("error get user address", zap.Error(err))
+		return nil, err
+	}
+
+	return addresses, nil
+}
+
+func (s *service) GetAddress(ctx context.Context, userClaim dto.UserClaimJwt, addressId int) (models.UserAddress, error) {
+	ctx, span := tracing.Start(ctx, "address.GetAddress")
+	defer span.End()
+
+	address, err := s.UserAddressRepository.FindOne(ctx, "*", "id = ? and user_id = ?", addressId, userClaim.UserId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.UserAddress{}, constants.AddressNotFound
+		}
+
+		s.log(ctx).Error("error get user address", zap.Error(err))
+		return models.UserAddress{}, err
+	}
+
+	return address, nil
+}
+
+// UpdateAddress is_default can only move the default to this address, to remove it another address is set as default
+func (s *service) UpdateAddress(ctx context.Context, userClaim dto.UserClaimJwt, addressId int, payload dto.PayloadAddress) (models.UserAddress, error) {
+	ctx, span := tracing.Start(ctx, "address.UpdateAddress")
+	defer span.End()
+
+	address, err := s.GetAddress(ctx, userClaim, addressId)
+	if err != nil {
+		return models.UserAddress{}, err
+	}
+
+	tx := s.UserAddressRepository.Begin().WithContext(ctx)
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		s.log(ctx).Error("error begin transaction", zap.Error(err))
+		return models.UserAddress{}, err
+	}
+
+	address.Label = payload.Label
+	address.Address = toShippingAddress(payload)
+	address.UpdatedAt = time.Now().In(util.LocationTime)
+	selectFields := "label," + addressFields + ",updated_at"
+
+	if payload.IsDefault && !address.IsDefault {
+		if err := s.ClearDefaultTx(tx, userClaim.UserId); err != nil {
+			tx.Rollback()
+			return models.UserAddress{}, err
+		}
+
+		address.IsDefault = true
+		selectFields += ",is_default"
+	}
+
+	if err := s.UserAddressRepository.UpdateTx(tx, &address, selectFields, "id = ?", address.ID); err != nil {
+		tx.Rollback()
+		s.log(ctx).Error("error update user address", zap.Error(err))
+		return models.UserAddress{}, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		s.log(ctx).Error("error commit transaction", zap.Error(err))
+		return models.UserAddress{}, err
+	}
+
+	return address, nil
+}
+
+func (s *service) SetDefaultAddress(ctx context.Context, userClaim dto.UserClaimJwt, addressId int) error {
+	ctx, span := tracing.Start(ctx, "address.SetDefaultAddress")
+	defer span.End()
+
+	address, err := s.GetAddress(ctx, userClaim, addressId)
+	if err != nil {
+		return err
+	}
+
+	if address.IsDefault {
+		return nil
+	}
+
+	tx := s.UserAddressRepository.Begin().WithContext(ctx)
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		s.log(ctx).Error("error begin transaction", zap.Error(err))
+		return err
+	}
+
+	if err := s.ClearDefaultTx(tx, userClaim.UserId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	updated := models.UserAddress{IsDefault: true, UpdatedAt: time.Now().In(util.LocationTime)}
+	if err := s.UserAddressRepository.UpdateTx(tx, &updated, "is_default,updated_at", "id = ?", address.ID); err != nil {
+		tx.Rollback()
+		s.log(ctx).Error("error update user address", zap.Error(err))
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		s.log(ctx).Error("error commit transaction", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// DeleteAddress the newest remaining address becomes the default when the default one is deleted,
+// orders keep their own copy of the address
+func (s *service) DeleteAddress(ctx context.Context, userClaim dto.UserClaimJwt, addressId int) error {
+	ctx, span := tracing.Start(ctx, "address.DeleteAddress")
+	defer span.End()
+
+	tx := s.UserAddressRepository.Begin().WithContext(ctx)
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		s.log(ctx).Error("error begin transaction", zap.Error(err))
+		return err
+	}
+
+	if err := s.DeleteAddressTx(tx, userClaim.UserId, addressId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		s.log(ctx).Error("error commit transaction", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) DeleteAddressTx(tx *gorm.DB, userId, addressId int) error {
+	addresses, err := s.UserAddressRepository.FindTx(tx, "id,is_default", "user_id = ?", userId)
+	if err != nil {
+		s.log(util.TxContext(tx)).Error("error get user address", zap.Error(err))
+		return err
+	}
+
+	var (
+		deleted   models.UserAddress
+		remaining []models.UserAddress
+	)
+	for _, address := range addresses {
+		if address.ID == addressId {
+			deleted = address
+			continue
+		}
+		remaining = append(remaining, address)
+	}
+
+	if deleted.ID == 0 {
+		return constants.AddressNotFound
+	}
+
+	if err := s.UserAddressRepository.DeleteTx(tx, "id = ?", deleted.ID); err != nil {
+		s.log(util.TxContext(tx)).Error("error delete user address", zap.Error(err))
+		return err
+	}
+
+	if deleted.IsDefault && len(remaining) > 0 {
+		promoted := models.UserAddress{IsDefault: true, UpdatedAt: time.Now().In(util.LocationTime)}
+		if err := s.UserAddressRepository.UpdateTx(tx, &promoted, "is_default,updated_at", "id = ?", remaining[0].ID); err != nil {
+			s.log(util.TxContext(tx)).Error("error update user address", zap.Error(err))
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *service) ClearDefaultTx(tx *gorm.DB, userId int) error {
+	cleared := models.UserAddress{IsDefault: false, UpdatedAt: time.Now().In(util.LocationTime)}
+	if err := s.UserAddressRepository.UpdateTx(tx, &cleared, "is_default,updated_at", "user_id = ? and is_default = 1", userId); err != nil {
+		s.log(util.TxContext(tx)).Error("error update user address", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// FindShippingAddress returns the address for checkout, the default address when addressId is empty
+func (s *service) FindShippingAddress(ctx context.Context, userClaim dto.UserClaimJwt, addressId int) (models.UserAddress, error) {
+	ctx, span := tracing.Start(ctx, "address.FindShippingAddress")
+	defer span.End()
+
+	if addressId != 0 {
+		return s.GetAddress(ctx, userClaim, addressId)
+	}
+
+	address, err := s.UserAddressRepository.FindOne(ctx, "*", "user_id = ? and is_default = 1", userClaim.UserId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.UserAddress{}, constants.AddressRequired
+		}
+
+		s.log(ctx).Error("error get user address", zap.Error(err))
+		return models.UserAddress{}, err
+	}
+
+	return address, nil
+}
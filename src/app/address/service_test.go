@@ -0,0 +1,77 @@
+package address
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"test-edot/constants"
+	"test-edot/src/models"
+	"test-edot/src/repository/mocks"
+	"testing"
+)
+
+type (
+	TestDeleteAddressData struct {
+		name          string
+		addresses     []models.UserAddress
+		addressId     int
+		expectErr     error
+		expectPromote int
+	}
+)
+
+func TestDeleteAddressTx(t *testing.T) {
+
+	tableTests := []TestDeleteAddressData{
+		{
+			name:          "test delete default promote newest address",
+			addresses:     []models.UserAddress{{ID: 3}, {ID: 2, IsDefault: true}, {ID: 1}},
+			addressId:     2,
+			expectPromote: 3,
+		},
+		{
+			name:      "test delete non default address",
+			addresses: []models.UserAddress{{ID: 2, IsDefault: true}, {ID: 1}},
+			addressId: 1,
+		},
+		{
+			name:      "test delete last address",
+			addresses: []models.UserAddress{{ID: 1, IsDefault: true}},
+			addressId: 1,
+		},
+		{
+			name:      "test delete address of other user",
+			addresses: []models.UserAddress{{ID: 1, IsDefault: true}},
+			addressId: 9,
+			expectErr: constants.AddressNotFound,
+		},
+	}
+
+	for _, test := range tableTests {
+		t.Run(test.name, func(t *testing.T) {
+			tx := gorm.DB{}
+			mockAddressRepo := new(mocks.UserAddressRepositoryInterface)
+
+			mockAddressRepo.On("FindTx", &tx, "id,is_default", "user_id = ?", 1).Return(test.addresses, nil)
+			mockAddressRepo.On("DeleteTx", &tx, "id = ?", test.addressId).Return(nil)
+			mockAddressRepo.On("UpdateTx", &tx, mock.AnythingOfType("*models.UserAddress"), "is_default,updated_at", "id = ?", mock.Anything).Return(nil)
+
+			s := service{Log: zap.NewNop(), UserAddressRepository: mockAddressRepo}
+
+			err := s.DeleteAddressTx(&tx, 1, test.addressId)
+			if test.expectErr != nil {
+				assert.ErrorIs(t, err, test.expectErr)
+				mockAddressRepo.AssertNotCalled(t, "DeleteTx", &tx, mock.Anything, mock.Anything)
+				return
+			}
+
+			assert.NoError(t, err)
+			if test.expectPromote != 0 {
+				mockAddressRepo.AssertCalled(t, "UpdateTx", &tx, mock.Anything, "is_default,updated_at", "id = ?", test.expectPromote)
+			} else {
+				mockAddressRepo.AssertNotCalled(t, "UpdateTx", &tx, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package apikey
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"test-edot/src/dto"
+	"test-edot/src/factory"
+)
+
+type handler struct {
+	service Service
+}
+
+func NewHandler(f *factory.Factory) *handler {
+	return &handler{
+		service: NewService(f),
+	}
+}
+
+func (h *handler) CreateApiKey(g *gin.Context) {
+	userClaim := g.Value("userClaim").(dto.UserClaimJwt)
+
+	shopId, err := strconv.Atoi(g.Param("shop_id"))
+	if err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: "shop_id is not valid",
+		})
+		return
+	}
+
+	var payload dto.PayloadCreateApiKey
+	if err := g.ShouldBindJSON(&payload); err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	res, err := h.service.CreateApiKey(g, userClaim, shopId, payload)
+	if err != nil {
+		g.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	g.JSON(http.StatusCreated, dto.Response{
+		Message: "success create api key, the key is only shown once",
+		Data:    res,
+	})
+	return
+}
+
+func (h *handler) GetApiKeys(g *gin.Context) {
+	userClaim := g.Value("userClaim").(dto.UserClaimJwt)
+
+	shopId, err := strconv.Atoi(g.Param("shop_id"))
+	if err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: "shop_id is not valid",
+		})
+		return
+	}
+
+	res, err := h.service.GetApiKeys(g, userClaim, shopId)
+	if err != nil {
+		g.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	g.JSON(http.StatusOK, dto.Response{
+		Message: "success get api keys",
+		Data:    res,
+	})
+	return
+}
+
+func (h *handler) RevokeApiKey(g *gin.Context) {
+	userClaim := g.Value("userClaim").(dto.UserClaimJwt)
+
+	shopId, err := strconv.Atoi(g.Param("shop_id"))
+	if err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: "shop_id is not valid",
+		})
+		return
+	}
+
+	apiKeyId, err := strconv.Atoi(g.Param("api_key_id"))
+	if err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: "api_key_id is not valid",
+		})
+		return
+	}
+
+	if err := h.service.RevokeApiKey(g, userClaim, shopId, apiKeyId); err != nil {
+		g.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	g.JSON(http.StatusOK, dto.Response{
+		Message: "success revoke api key",
+	})
+	return
+}
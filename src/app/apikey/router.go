@@ -0,0 +1,13 @@
+package apikey
+
+import (
+	"github.com/gin-gonic/gin"
+	"test-edot/constants"
+	"test-edot/src/middleware"
+)
+
+func (h *handler) ApiKeyRouter(g *gin.RouterGroup) {
+	g.POST("", middleware.Authorize(constants.PERMISSION_SHOP_WRITE), h.CreateApiKey)
+	g.GET("", middleware.Authorize(constants.PERMISSION_SHOP_READ), h.GetApiKeys)
+	g.DELETE("/:api_key_id", middleware.Authorize(constants.PERMISSION_SHOP_WRITE), h.RevokeApiKey)
+}
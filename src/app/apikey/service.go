@@ -0,0 +1,179 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"strings"
+	"test-edot/constants"
+	"test-edot/src/app/shop"
+	"test-edot/src/dto"
+	"test-edot/src/factory"
+	"test-edot/src/models"
+	"test-edot/src/repository"
+	"test-edot/src/tracing"
+	"test-edot/util"
+	"time"
+)
+
+type Service interface {
+	CreateApiKey(ctx context.Context, userClaim dto.UserClaimJwt, shopId int, payload dto.PayloadCreateApiKey) (dto.ResponseApiKey, error)
+	GetApiKeys(ctx context.Context, userClaim dto.UserClaimJwt, shopId int) ([]dto.ResponseApiKey, error)
+	RevokeApiKey(ctx context.Context, userClaim dto.UserClaimJwt, shopId, apiKeyId int) error
+}
+
+type service struct {
+	Log              *zap.Logger
+	ShopService      shop.Service
+	ApiKeyRepository repository.ApiKeyRepositoryInterface
+}
+
+func NewService(f *factory.Factory) Service {
+	return &service{
+		Log:              f.Log,
+		ShopService:      shop.NewService(f),
+		ApiKeyRepository: f.ApiKeyRepository,
+	}
+}
+
+// log is the logger of the request that made the call, see middleware.RequestId
+func (s *service) log(ctx context.Context) *zap.Logger {
+	return util.Logger(ctx, s.Log)
+}
+
+// validatePermissions returns the unique permissions of the payload, only the permissions in MapApiKeyPermission can be granted
+func validatePermissions(permissions []string) ([]string, error) {
+	seen := make(map[string]bool, len(permissions))
+	var res []string
+	for _, permission := range permissions {
+		if !constants.MapApiKeyPermission[permission] {
+			return nil, constants.ApiKeyPermissionInvalid
+		}
+
+		if seen[permission] {
+			continue
+		}
+		seen[permission] = true
+		res = append(res, permission)
+	}
+
+	if len(res) == 0 {
+		return nil, constants.ApiKeyPermissionInvalid
+	}
+
+	return res, nil
+}
+
+func toResponse(apiKey models.ApiKey) dto.ResponseApiKey {
+	return dto.ResponseApiKey{
+		Id:          apiKey.ID,
+		ShopId:      apiKey.ShopId,
+		Name:        apiKey.Name,
+		Prefix:      apiKey.Prefix,
+		Permissions: strings.Split(apiKey.Permissions, ","),
+		ExpiredAt:   apiKey.ExpiredAt,
+		LastUsedAt:  apiKey.LastUsedAt,
+		RevokedAt:   apiKey.RevokedAt,
+		CreatedAt:   apiKey.CreatedAt,
+	}
+}
+
+// CreateApiKey only the hash of the key is stored, the key is returned once and can not be shown again
+func (s *service) CreateApiKey(ctx context.Context, userClaim dto.UserClaimJwt, shopId int, payload dto.PayloadCreateApiKey) (dto.ResponseApiKey, error) {
+	ctx, span := tracing.Start(ctx, "apikey.CreateApiKey")
+	defer span.End()
+
+	if _, err := s.ShopService.Authorize(ctx, userClaim, shopId, constants.SHOP_PERMISSION_API_KEY_MANAGE); err != nil {
+		return dto.ResponseApiKey{}, err
+	}
+
+	permissions, err := validatePermissions(payload.Permissions)
+	if err != nil {
+		return dto.ResponseApiKey{}, err
+	}
+
+	if payload.ExpiresInDays < 1 || payload.ExpiresInDays > constants.API_KEY_MAX_TTL_DAYS {
+		return dto.ResponseApiKey{}, constants.ApiKeyExpiryInvalid
+	}
+
+	secret, err := util.GenerateRandomToken(32)
+	if err != nil {
+		return dto.ResponseApiKey{}, err
+	}
+
+	rawKey := constants.API_KEY_PREFIX + secret
+	now := time.Now().In(util.LocationTime)
+	apiKey := models.ApiKey{
+		ShopId:      shopId,
+		CreatedBy:   userClaim.UserId,
+		Name:        payload.Name,
+		Prefix:      rawKey[:len(constants.API_KEY_PREFIX)+constants.API_KEY_PREFIX_LENGTH],
+		KeyHash:     util.HashToken(rawKey),
+		Permissions: strings.Join(permissions, ","),
+		ExpiredAt:   now.AddDate(0, 0, payload.ExpiresInDays),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.ApiKeyRepository.Create(ctx, &apiKey); err != nil {
+		s.log(ctx).Error("error creating api key", zap.Error(err))
+		return dto.ResponseApiKey{}, err
+	}
+
+	s.log(ctx).Info("api key created", zap.Int("apiKeyId", apiKey.ID), zap.Int("shopId", shopId), zap.Int("userId", userClaim.UserId))
+
+	res := toResponse(apiKey)
+	res.Key = rawKey
+	return res, nil
+}
+
+func (s *service) GetApiKeys(ctx context.Context, userClaim dto.UserClaimJwt, shopId int) ([]dto.ResponseApiKey, error) {
+	ctx, span := tracing.Start(ctx, "apikey.GetApiKeys")
+	defer span.End()
+
+	if _, err := s.ShopService.Authorize(ctx, userClaim, shopId, constants.SHOP_PERMISSION_API_KEY_MANAGE); err != nil {
+		return nil, err
+	}
+
+	apiKeys, err := s.ApiKeyRepository.Find(ctx, "*", "shop_id = ?", shopId)
+	if err != nil {
+		s.log(ctx).Error("error fetch api keys", zap.Error(err), zap.Int("shopId", shopId))
+		return nil, err
+	}
+
+	res := []dto.ResponseApiKey{}
+	for _, apiKey := range apiKeys {
+		res = append(res, toResponse(apiKey))
+	}
+
+	return res, nil
+}
+
+func (s *service) RevokeApiKey(ctx context.Context, userClaim dto.UserClaimJwt, shopId, apiKeyId int) error {
+	ctx, span := tracing.Start(ctx, "apikey.RevokeApiKey")
+	defer span.End()
+
+	if _, err := s.ShopService.Authorize(ctx, userClaim, shopId, constants.SHOP_PERMISSION_API_KEY_MANAGE); err != nil {
+		return err
+	}
+
+	apiKey, err := s.ApiKeyRepository.FindOne(ctx, "id", "id = ? and shop_id = ? and revoked_at is null", apiKeyId, shopId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return constants.ApiKeyNotFound
+		}
+
+		s.log(ctx).Error("error fetch api key", zap.Error(err), zap.Int("apiKeyId", apiKeyId))
+		return err
+	}
+
+	now := time.Now().In(util.LocationTime)
+	revoked := models.ApiKey{RevokedAt: &now, UpdatedAt: now}
+	if err := s.ApiKeyRepository.Update(ctx, &revoked, "revoked_at,updated_at", "id = ?", apiKey.ID); err != nil {
+		s.log(ctx).Error("error revoke api key", zap.Error(err), zap.Int("apiKeyId", apiKey.ID))
+		return err
+	}
+
+	s.log(ctx).Info("api key revoked", zap.Int("apiKeyId", apiKey.ID), zap.Int("shopId", shopId), zap.Int("userId", userClaim.UserId))
+	return nil
+}
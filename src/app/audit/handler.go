@@ -0,0 +1,44 @@
+package audit
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"test-edot/src/dto"
+	"test-edot/src/factory"
+)
+
+type handler struct {
+	service Service
+}
+
+func NewHandler(f *factory.Factory) *handler {
+	return &handler{
+		service: NewService(f),
+	}
+}
+
+func (h *handler) GetAuditEvents(g *gin.Context) {
+	userClaim := g.Value("userClaim").(dto.UserClaimJwt)
+
+	var payload dto.ParameterQueryAudit
+	if err := g.ShouldBind(&payload); err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	res, err := h.service.GetAuditEvents(g, userClaim, payload)
+	if err != nil {
+		g.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	g.JSON(http.StatusOK, dto.Response{
+		Message: "success get audit events",
+		Data:    res,
+	})
+	return
+}
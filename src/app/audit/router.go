@@ -0,0 +1,11 @@
+package audit
+
+import (
+	"github.com/gin-gonic/gin"
+	"test-edot/constants"
+	"test-edot/src/middleware"
+)
+
+func (h *handler) AuditRouter(g *gin.RouterGroup) {
+	g.GET("", middleware.Authorize(constants.PERMISSION_SHOP_READ), h.GetAuditEvents)
+}
@@ -0,0 +1,216 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"strconv"
+	"test-edot/constants"
+	"test-edot/src/dto"
+	"test-edot/src/factory"
+	"test-edot/src/models"
+	"test-edot/src/repository"
+	"test-edot/src/tracing"
+	"test-edot/util"
+	"time"
+)
+
+type Service interface {
+	Record(ctx context.Context, userClaim dto.UserClaimJwt, entry dto.AuditEntry)
+	GetAuditEvents(ctx context.Context, userClaim dto.UserClaimJwt, payload dto.ParameterQueryAudit) ([]dto.ResponseAuditEvent, error)
+}
+
+type service struct {
+	Log                  *zap.Logger
+	AuditEventRepository repository.AuditEventRepositoryInterface
+}
+
+const queryShopAuditEvents = "shop_id in (select shop_id from shop_members where user_id = ? and role in ?)"
+
+func NewService(f *factory.Factory) Service {
+	return &service{
+		Log:                  f.Log,
+		AuditEventRepository: f.AuditEventRepository,
+	}
+}
+
+// log is the logger of the request that made the call, see middleware.RequestId
+func (s *service) log(ctx context.Context) *zap.Logger {
+	return util.Logger(ctx, s.Log)
+}
+
+// Record is called after the change is committed, the change already happened
+// so an error writing the event is only logged
+func (s *service) Record(ctx context.Context, userClaim dto.UserClaimJwt, entry dto.AuditEntry) {
+	ctx, span := tracing.Start(ctx, "audit.Record")
+	defer span.End()
+
+	before, err := auditValue(entry.Before)
+	if err != nil {
+		s.log(ctx).Error("error marshal audit event", zap.Error(err), zap.String("action", entry.Action))
+		return
+	}
+
+	after, err := auditValue(entry.After)
+	if err != nil {
+		s.log(ctx).Error("error marshal audit event", zap.Error(err), zap.String("action", entry.Action))
+		return
+	}
+
+	ip, requestId := requestInfo(ctx)
+	event := models.AuditEvent{
+		ShopId:       optionalId(entry.ShopId),
+		ActorId:      optionalId(userClaim.UserId),
+		ActorRole:    userClaim.Role,
+		ApiKeyId:     optionalId(userClaim.ApiKeyId),
+		Action:       entry.Action,
+		ResourceType: entry.ResourceType,
+		ResourceId:   strconv.Itoa(entry.ResourceId),
+		BeforeValue:  before,
+		AfterValue:   after,
+		Ip:           ip,
+		RequestId:    requestId,
+		CreatedAt:    time.Now().In(util.LocationTime),
+	}
+	if err := s.AuditEventRepository.Create(ctx, &event); err != nil {
+		s.log(ctx).Error("error creating audit event", zap.Error(err), zap.String("action", entry.Action), zap.Int("resourceId", entry.ResourceId))
+	}
+}
+
+func auditValue(value any) (*string, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	b, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	res := string(b)
+	return &res, nil
+}
+
+func optionalId(id int) *int {
+	if id == 0 {
+		return nil
+	}
+
+	return &id
+}
+
+// requestInfo reads the client ip and the request id set by middleware.RequestId from the gin context
+// the handlers pass down as ctx
+func requestInfo(ctx context.Context) (string, string) {
+	g, ok := ctx.Value(gin.ContextKey).(*gin.Context)
+	if !ok || g.Request == nil {
+		return "", ""
+	}
+
+	return g.ClientIP(), g.GetString(constants.REQUEST_ID_KEY)
+}
+
+// GetAuditEvents lists the events of the shops the user owns, newest first
+func (s *service) GetAuditEvents(ctx context.Context, userClaim dto.UserClaimJwt, payload dto.ParameterQueryAudit) ([]dto.ResponseAuditEvent, error) {
+	ctx, span := tracing.Start(ctx, "audit.GetAuditEvents")
+	defer span.End()
+
+	q := queryShopAuditEvents
+	args := []any{userClaim.UserId, constants.MapPermissionShopRole[constants.SHOP_PERMISSION_AUDIT_VIEW]}
+
+	if payload.ShopId != 0 {
+		q += " and shop_id = ?"
+		args = append(args, payload.ShopId)
+	}
+
+	if payload.ActorId != 0 {
+		q += " and actor_id = ?"
+		args = append(args, payload.ActorId)
+	}
+
+	if payload.Action != "" {
+		q += " and action = ?"
+		args = append(args, payload.Action)
+	}
+
+	if payload.ResourceType != "" {
+		q += " and resource_type = ?"
+		args = append(args, payload.ResourceType)
+	}
+
+	if payload.ResourceId != "" {
+		q += " and resource_id = ?"
+		args = append(args, payload.ResourceId)
+	}
+
+	if payload.From != "" {
+		from, err := time.ParseInLocation("2006-01-02", payload.From, util.LocationTime)
+		if err != nil {
+			return nil, constants.AuditDateInvalid
+		}
+
+		q += " and created_at >= ?"
+		args = append(args, from.Format("2006-01-02 15:04:05"))
+	}
+
+	if payload.To != "" {
+		to, err := time.ParseInLocation("2006-01-02", payload.To, util.LocationTime)
+		if err != nil {
+			return nil, constants.AuditDateInvalid
+		}
+
+		// the to date is inclusive
+		q += " and created_at < ?"
+		args = append(args, to.AddDate(0, 0, 1).Format("2006-01-02 15:04:05"))
+	}
+
+	limit := payload.Limit
+	if limit <= 0 {
+		limit = constants.AUDIT_DEFAULT_LIMIT
+	}
+
+	if limit > constants.AUDIT_MAX_LIMIT {
+		limit = constants.AUDIT_MAX_LIMIT
+	}
+
+	offset := payload.Offset
+	if offset < 0 {
+		offset = 0
+	}
+
+	events, err := s.AuditEventRepository.Find(ctx, offset, limit, "*", q, args...)
+	if err != nil {
+		s.log(ctx).Error("error fetch audit events", zap.Error(err), zap.Int("user_id", userClaim.UserId))
+		return nil, err
+	}
+
+	res := []dto.ResponseAuditEvent{}
+	for _, event := range events {
+		res = append(res, dto.ResponseAuditEvent{
+			Id:           event.ID,
+			ShopId:       event.ShopId,
+			ActorId:      event.ActorId,
+			ActorRole:    event.ActorRole,
+			ApiKeyId:     event.ApiKeyId,
+			Action:       event.Action,
+			ResourceType: event.ResourceType,
+			ResourceId:   event.ResourceId,
+			Before:       rawValue(event.BeforeValue),
+			After:        rawValue(event.AfterValue),
+			Ip:           event.Ip,
+			RequestId:    event.RequestId,
+			CreatedAt:    event.CreatedAt,
+		})
+	}
+
+	return res, nil
+}
+
+func rawValue(value *string) json.RawMessage {
+	if value == nil {
+		return nil
+	}
+
+	return json.RawMessage(*value)
+}
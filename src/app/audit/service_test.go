@@ -0,0 +1,143 @@
+package audit
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"test-edot/constants"
+	"test-edot/src/dto"
+	"test-edot/src/models"
+	"test-edot/src/repository/mocks"
+	"testing"
+)
+
+type (
+	TestGetAuditEventsData struct {
+		name        string
+		payload     dto.ParameterQueryAudit
+		expectQuery string
+		expectArgs  []any
+		expectLimit int
+		expectErr   error
+	}
+
+	TestRecordData struct {
+		name         string
+		userClaim    dto.UserClaimJwt
+		entry        dto.AuditEntry
+		expectBefore *string
+		expectAfter  *string
+	}
+)
+
+func TestGetAuditEvents(t *testing.T) {
+	roles := constants.MapPermissionShopRole[constants.SHOP_PERMISSION_AUDIT_VIEW]
+
+	tableTests := []TestGetAuditEventsData{
+		{
+			name:        "test without filter",
+			expectQuery: queryShopAuditEvents,
+			expectArgs:  []any{1, roles},
+			expectLimit: constants.AUDIT_DEFAULT_LIMIT,
+		},
+		{
+			name:        "test filter shop and action with max limit",
+			payload:     dto.ParameterQueryAudit{ShopId: 2, Action: constants.AUDIT_ACTION_SHOP_UPDATE, Limit: 1000},
+			expectQuery: queryShopAuditEvents + " and shop_id = ? and action = ?",
+			expectArgs:  []any{1, roles, 2, constants.AUDIT_ACTION_SHOP_UPDATE},
+			expectLimit: constants.AUDIT_MAX_LIMIT,
+		},
+		{
+			name:        "test filter date range includes the to date",
+			payload:     dto.ParameterQueryAudit{From: "2024-01-01", To: "2024-01-31", Limit: 10},
+			expectQuery: queryShopAuditEvents + " and created_at >= ? and created_at < ?",
+			expectArgs:  []any{1, roles, "2024-01-01 00:00:00", "2024-02-01 00:00:00"},
+			expectLimit: 10,
+		},
+		{
+			name:      "test invalid date",
+			payload:   dto.ParameterQueryAudit{From: "01-01-2024"},
+			expectErr: constants.AuditDateInvalid,
+		},
+	}
+
+	for _, test := range tableTests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			mockRepo := new(mocks.AuditEventRepositoryInterface)
+			mockRepo.On("Find", append([]any{ctx, 0, test.expectLimit, "*", test.expectQuery}, test.expectArgs...)...).
+				Return([]models.AuditEvent{{ID: 1, Action: constants.AUDIT_ACTION_SHOP_UPDATE}}, nil)
+
+			s := service{Log: zap.NewNop(), AuditEventRepository: mockRepo}
+
+			res, err := s.GetAuditEvents(ctx, dto.UserClaimJwt{UserId: 1}, test.payload)
+			if test.expectErr != nil {
+				assert.ErrorIs(t, err, test.expectErr)
+				mockRepo.AssertNumberOfCalls(t, "Find", 0)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Len(t, res, 1)
+		})
+	}
+}
+
+func TestRecord(t *testing.T) {
+	before, after := `{"is_active":true}`, `{"is_active":false}`
+
+	tableTests := []TestRecordData{
+		{
+			name:      "test record user change",
+			userClaim: dto.UserClaimJwt{UserId: 1, Role: constants.ROLE_ADMIN_SHOP},
+			entry: dto.AuditEntry{
+				ShopId:       2,
+				Action:       constants.AUDIT_ACTION_SHOP_CLOSE,
+				ResourceType: constants.AUDIT_RESOURCE_SHOP,
+				ResourceId:   2,
+				Before:       map[string]any{"is_active": true},
+				After:        map[string]any{"is_active": false},
+			},
+			expectBefore: &before,
+			expectAfter:  &after,
+		},
+		{
+			name:      "test record api key change without before",
+			userClaim: dto.UserClaimJwt{UserId: 1, Role: constants.ROLE_ADMIN_SHOP, ApiKeyId: 3, ShopId: 2},
+			entry: dto.AuditEntry{
+				ShopId:       2,
+				Action:       constants.AUDIT_ACTION_SHOP_CLOSE,
+				ResourceType: constants.AUDIT_RESOURCE_SHOP,
+				ResourceId:   2,
+				After:        map[string]any{"is_active": false},
+			},
+			expectAfter: &after,
+		},
+	}
+
+	for _, test := range tableTests {
+		t.Run(test.name, func(t *testing.T) {
+			var event models.AuditEvent
+			ctx := context.Background()
+			mockRepo := new(mocks.AuditEventRepositoryInterface)
+			mockRepo.On("Create", ctx, mock.AnythingOfType("*models.AuditEvent")).
+				Run(func(args mock.Arguments) { event = *args.Get(1).(*models.AuditEvent) }).Return(nil)
+
+			s := service{Log: zap.NewNop(), AuditEventRepository: mockRepo}
+			s.Record(ctx, test.userClaim, test.entry)
+
+			assert.Equal(t, test.entry.Action, event.Action)
+			assert.Equal(t, "2", event.ResourceId)
+			assert.Equal(t, test.userClaim.UserId, *event.ActorId)
+			assert.Equal(t, test.entry.ShopId, *event.ShopId)
+			assert.Equal(t, test.expectBefore, event.BeforeValue)
+			assert.Equal(t, test.expectAfter, event.AfterValue)
+			if test.userClaim.ApiKeyId != 0 {
+				assert.Equal(t, test.userClaim.ApiKeyId, *event.ApiKeyId)
+			} else {
+				assert.Nil(t, event.ApiKeyId)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package inventory
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"test-edot/src/models"
+)
+
+type LowStockNotifier interface {
+	Notify(ctx context.Context, event models.LowStockEvent) error
+}
+
+type logNotifier struct {
+	Log *zap.Logger
+}
+
+// NewLogNotifier only writes low stock events to the log, swap it with another LowStockNotifier to send them elsewhere
+func NewLogNotifier(log *zap.Logger) LowStockNotifier {
+	return &logNotifier{
+		Log: log,
+	}
+}
+
+func (n *logNotifier) Notify(ctx context.Context, event models.LowStockEvent) error {
+	n.Log.Warn("stock below reorder threshold",
+		zap.Int("stockId", event.StockId),
+		zap.Int("productId", event.ProductId),
+		zap.Int("warehouseId", event.WarehouseId),
+		zap.Int("stock", event.Stock),
+		zap.Int("reorderThreshold", event.ReorderThreshold),
+	)
+
+	return nil
+}
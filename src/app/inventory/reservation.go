@@ -0,0 +1,255 @@
+package inventory
+
+import (
+	"errors"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"test-edot/constants"
+	"test-edot/src/models"
+	"test-edot/util"
+	"time"
+)
+
+// ReserveTx holds qty of a stock level for its owner, the qty leaves the available stock and is kept
+// in reserved_stock until the reservation is committed or released
+func (s *service) ReserveTx(tx *gorm.DB, reservation *models.StockReservation) error {
+	if reservation.Qty <= 0 {
+		return constants.QtyMustPositive
+	}
+
+	stock, err := s.StockLevelRepository.FindOneTx(tx, "id asc", "id = ?", reservation.StockId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return constants.StockLevelNotFound
+		}
+
+		s.log(util.TxContext(tx)).Error("error get stock", zap.Error(err))
+		return err
+	}
+
+	if stock.Stock < reservation.Qty {
+		return constants.NotEnoughStockProduct
+	}
+
+	now := time.Now().In(util.LocationTime)
+	updatedStockLevel := models.StockLevel{
+		Stock:         stock.Stock - reservation.Qty,
+		ReservedStock: stock.ReservedStock + reservation.Qty,
+		UpdatedAt:     now,
+	}
+	if err := s.StockLevelRepository.UpdateOneTx(tx, &updatedStockLevel, "stock,reserved_stock,updated_at", "id = ?", stock.ID); err != nil {
+		s.log(util.TxContext(tx)).Error("error update stock level", zap.Error(err))
+		return err
+	}
+
+	reservation.ProductId = stock.ProductId
+	reservation.WarehouseId = stock.WarehouseId
+	reservation.State = constants.RESERVATION_STATE_HELD
+	reservation.CreatedAt = now
+	reservation.UpdatedAt = now
+	if err := s.StockReservationRepository.Create(tx, reservation); err != nil {
+		s.log(util.TxContext(tx)).Error("error creating stock reservation", zap.Error(err))
+		return err
+	}
+
+	if err := s.RecordLowStockTx(tx, stock.ID); err != nil {
+		s.log(util.TxContext(tx)).Error("error record low stock", zap.Error(err))
+		return err
+	}
+
+	s.log(util.TxContext(tx)).Info("stock reserved", zap.Int("stockId", stock.ID), zap.Int("qty", reservation.Qty), zap.String("ownerType", reservation.OwnerType), zap.Int("ownerId", reservation.OwnerId))
+	return nil
+}
+
+// CommitReservationTx consumes every held reservation of the owner, the reserved qty is gone for good
+func (s *service) CommitReservationTx(tx *gorm.DB, ownerType string, ownerId int) error {
+	total, err := s.settleReservationTx(tx, ownerType, ownerId, constants.RESERVATION_STATE_COMMITTED)
+	if err != nil {
+		return err
+	}
+
+	if total == 0 {
+		return constants.ReservationNotFound
+	}
+
+	return nil
+}
+
+// ReleaseReservationTx returns every held reservation of the owner to the available stock and returns the
+// released qty, releasing an owner without held reservations does nothing
+func (s *service) ReleaseReservationTx(tx *gorm.DB, ownerType string, ownerId int) (int, error) {
+	return s.settleReservationTx(tx, ownerType, ownerId, constants.RESERVATION_STATE_RELEASED)
+}
+
+// settleReservationTx moves every held reservation of the owner to state and returns the settled qty
+func (s *service) settleReservationTx(tx *gorm.DB, ownerType string, ownerId int, state string) (int, error) {
+	query := "owner_type = ? and owner_id = ? and state = ?"
+	reservations, err := s.StockReservationRepository.FindTx(tx, "*", query, ownerType, ownerId, constants.RESERVATION_STATE_HELD)
+	if err != nil {
+		s.log(util.TxContext(tx)).Error("error get stock reservation", zap.Error(err))
+		return 0, err
+	}
+
+	qty := 0
+	for _, reservation := range reservations {
+		qty += reservation.Qty
+
+		stock, err := s.StockLevelRepository.FindOneTx(tx, "id asc", "id = ?", reservation.StockId)
+		if err != nil {
+			s.log(util.TxContext(tx)).Error("error get stock", zap.Error(err))
+			return 0, err
+		}
+
+		fields := "reserved_stock,updated_at"
+		updatedStockLevel := models.StockLevel{ReservedStock: stock.ReservedStock - reservation.Qty, UpdatedAt: time.Now().In(util.LocationTime)}
+		if state == constants.RESERVATION_STATE_RELEASED {
+			updatedStockLevel.Stock = stock.Stock + reservation.Qty
+			fields += ",stock"
+		}
+
+		if err := s.StockLevelRepository.UpdateOneTx(tx, &updatedStockLevel, fields, "id = ?", stock.ID); err != nil {
+			s.log(util.TxContext(tx)).Error("error update stock level", zap.Error(err))
+			return 0, err
+		}
+	}
+
+	if len(reservations) == 0 {
+		return 0, nil
+	}
+
+	updatedReservation := models.StockReservation{State: state, UpdatedAt: time.Now().In(util.LocationTime)}
+	if err := s.StockReservationRepository.UpdateOneTx(tx, &updatedReservation, "state,updated_at", query, ownerType, ownerId, constants.RESERVATION_STATE_HELD); err != nil {
+		s.log(util.TxContext(tx)).Error("error update stock reservation", zap.Error(err))
+		return 0, err
+	}
+
+	s.log(util.TxContext(tx)).Info("stock reservation settled", zap.String("ownerType", ownerType), zap.Int("ownerId", ownerId), zap.String("state", state))
+	return qty, nil
+}
+
+// AttachReservationTx puts the held reservations of a product in a warehouse on the transfer that ships their
+// units and returns the qty, the reservations stay on the source stock level until the transfer is received
+func (s *service) AttachReservationTx(tx *gorm.DB, warehouseId, productId, transferId int) (int, error) {
+	stock, err := s.StockLevelRepository.FindOneTx(tx, "id asc", "warehouse_id = ? and product_id = ?", warehouseId, productId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, constants.StockLevelNotFound
+		}
+
+		s.log(util.TxContext(tx)).Error("error get stock", zap.Error(err))
+		return 0, err
+	}
+
+	query := "stock_id = ? and state = ? and stock_transfer_id is null"
+	reservations, err := s.StockReservationRepository.FindTx(tx, "*", query, stock.ID, constants.RESERVATION_STATE_HELD)
+	if err != nil {
+		s.log(util.TxContext(tx)).Error("error get stock reservation", zap.Error(err))
+		return 0, err
+	}
+
+	var qty int
+	for _, reservation := range reservations {
+		qty += reservation.Qty
+	}
+
+	if qty == 0 {
+		return 0, nil
+	}
+
+	updatedReservation := models.StockReservation{StockTransferId: &transferId, UpdatedAt: time.Now().In(util.LocationTime)}
+	if err := s.StockReservationRepository.UpdateOneTx(tx, &updatedReservation, "stock_transfer_id,updated_at", query, stock.ID, constants.RESERVATION_STATE_HELD); err != nil {
+		s.Log.Error("error update stock reservation", zap.Error(err))
+		return 0, err
+	}
+
+	s.Log.Info("stock reservation attached to transfer", zap.Int("stockId", stock.ID), zap.Int("transferId", transferId), zap.Int("qty", qty))
+	return qty, nil
+}
+
+// DetachReservationTx takes the reservations that were not moved yet off a cancelled transfer, they never left
+// the source stock level so nothing else has to be undone
+func (s *service) DetachReservationTx(tx *gorm.DB, transferId int) error {
+	updatedReservation := models.StockReservation{UpdatedAt: time.Now().In(util.LocationTime)}
+	if err := s.StockReservationRepository.UpdateOneTx(tx, &updatedReservation, "stock_transfer_id,updated_at", "stock_transfer_id = ? and state = ?", transferId, constants.RESERVATION_STATE_HELD); err != nil {
+		s.Log.Error("error update stock reservation", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// MoveReservationTx moves the held reservations of a product attached to the transfer to the stock level of the
+// same product in the destination warehouse, creating it when needed, and returns the moved qty. Reservations
+// settled while the transfer was on the way stay with the source stock level
+func (s *service) MoveReservationTx(tx *gorm.DB, transferId, productId, warehouseIdDest int) (int, error) {
+	query := "stock_transfer_id = ? and product_id = ? and state = ?"
+	reservations, err := s.StockReservationRepository.FindTx(tx, "*", query, transferId, productId, constants.RESERVATION_STATE_HELD)
+	if err != nil {
+		s.Log.Error("error get stock reservation", zap.Error(err))
+		return 0, err
+	}
+
+	var qty int
+	for _, reservation := range reservations {
+		qty += reservation.Qty
+	}
+
+	if qty == 0 {
+		return 0, nil
+	}
+
+	stockFrom, err := s.StockLevelRepository.FindOneTx(tx, "id asc", "id = ?", reservations[0].StockId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, constants.StockLevelNotFound
+		}
+
+		s.Log.Error("error get stock", zap.Error(err))
+		return 0, err
+	}
+
+	now := time.Now().In(util.LocationTime)
+	stockDest, err := s.StockLevelRepository.FindOneTx(tx, "id asc", "warehouse_id = ? and product_id = ?", warehouseIdDest, stockFrom.ProductId)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		s.log(util.TxContext(tx)).Error("error get stock", zap.Error(err))
+		return 0, err
+	}
+
+	stockIdDest := stockDest.ID
+	if stockDest == (models.StockLevelProduct{}) {
+		stockLevel := models.StockLevel{
+			ProductId:     stockFrom.ProductId,
+			WarehouseId:   warehouseIdDest,
+			ReservedStock: qty,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		}
+		if err := s.StockLevelRepository.Create(tx, &stockLevel); err != nil {
+			s.log(util.TxContext(tx)).Error("error creating stock level", zap.Error(err))
+			return 0, err
+		}
+
+		stockIdDest = stockLevel.ID
+	} else {
+		updatedStockLevel := models.StockLevel{ReservedStock: stockDest.ReservedStock + qty, UpdatedAt: now}
+		if err := s.StockLevelRepository.UpdateOneTx(tx, &updatedStockLevel, "reserved_stock,updated_at", "id = ?", stockDest.ID); err != nil {
+			s.log(util.TxContext(tx)).Error("error update stock level", zap.Error(err))
+			return 0, err
+		}
+	}
+
+	updatedStockLevel := models.StockLevel{ReservedStock: stockFrom.ReservedStock - qty, UpdatedAt: now}
+	if err := s.StockLevelRepository.UpdateOneTx(tx, &updatedStockLevel, "reserved_stock,updated_at", "id = ?", stockFrom.ID); err != nil {
+		s.log(util.TxContext(tx)).Error("error update stock level", zap.Error(err))
+		return 0, err
+	}
+
+	updatedReservation := models.StockReservation{StockId: stockIdDest, WarehouseId: warehouseIdDest, UpdatedAt: now}
+	if err := s.StockReservationRepository.UpdateOneTx(tx, &updatedReservation, "stock_id,warehouse_id,stock_transfer_id,updated_at", query, transferId, productId, constants.RESERVATION_STATE_HELD); err != nil {
+		s.log(util.TxContext(tx)).Error("error update stock reservation", zap.Error(err))
+		return 0, err
+	}
+
+	s.log(util.TxContext(tx)).Info("stock reservation moved", zap.Int("transferId", transferId), zap.Int("stockIdFrom", stockFrom.ID), zap.Int("stockIdDest", stockIdDest), zap.Int("qty", qty))
+	return qty, nil
+}
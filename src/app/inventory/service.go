@@ -0,0 +1,491 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"strconv"
+	"test-edot/constants"
+	"test-edot/metrics"
+	"test-edot/src/factory"
+	"test-edot/src/models"
+	"test-edot/src/repository"
+	"test-edot/util"
+	"time"
+)
+
+type Service interface {
+	ReconcileStock()
+	CheckLowStock()
+	RecordLowStockTx(tx *gorm.DB, stockIds ...int) error
+	AddStockTx(tx *gorm.DB, warehouseId, productId, qty int) (int, error)
+	DeductStockTx(tx *gorm.DB, warehouseId, productId, qty int) (int, error)
+	ReserveTx(tx *gorm.DB, reservation *models.StockReservation) error
+	CommitReservationTx(tx *gorm.DB, ownerType string, ownerId int) error
+	ReleaseReservationTx(tx *gorm.DB, ownerType string, ownerId int) (int, error)
+	AttachReservationTx(tx *gorm.DB, warehouseId, productId, transferId int) (int, error)
+	DetachReservationTx(tx *gorm.DB, transferId int) error
+	MoveReservationTx(tx *gorm.DB, transferId, productId, warehouseIdDest int) (int, error)
+	CheckCapacityTx(tx *gorm.DB, warehouseId int) error
+}
+
+type service struct {
+	Log                         *zap.Logger
+	StockLevelRepository        repository.StockLevelRepositoryInterface
+	StockReservationRepository  repository.StockReservationRepositoryInterface
+	LowStockEventRepository     repository.LowStockEventRepositoryInterface
+	WarehouseRepository         repository.WarehouseRepositoryInterface
+	StockTransferItemRepository repository.StockTransferItemRepositoryInterface
+	Notifier                    LowStockNotifier
+	AutoCorrect                 bool
+}
+
+func NewService(f *factory.Factory) Service {
+	autoCorrect, _ := strconv.ParseBool(util.GetEnv("STOCK_RECONCILE_AUTO_CORRECT", "false"))
+
+	return &service{
+		Log:                         f.Log,
+		StockLevelRepository:        f.StockLevelRepository,
+		StockReservationRepository:  f.StockReservationRepository,
+		LowStockEventRepository:     f.LowStockEventRepository,
+		WarehouseRepository:         f.WarehouseRepository,
+		StockTransferItemRepository: f.StockTransferItemRepository,
+		Notifier:                    NewLogNotifier(f.Log),
+		AutoCorrect:                 autoCorrect,
+	}
+}
+
+// log is the logger of the request that made the call, see middleware.RequestId
+func (s *service) log(ctx context.Context) *zap.Logger {
+	return util.Logger(ctx, s.Log)
+}
+
+// ReconcileStock compares reserved_stock of every stock level with the qty of its held
+// reservations, reports the drift and optionally corrects it
+func (s *service) ReconcileStock() {
+	ctx := context.Background()
+
+	s.Log.Info("running stock reconciliation", zap.Bool("autoCorrect", s.AutoCorrect))
+
+	drifts, err := s.FindStockDrift(ctx)
+	if err != nil {
+		s.Log.Error("error find stock drift", zap.Error(err))
+		return
+	}
+
+	metrics.StockReconciliationMismatch.Reset()
+	metrics.StockReconciliationMismatchTotal.Set(float64(len(drifts)))
+
+	for _, drift := range drifts {
+		metrics.StockReconciliationMismatch.WithLabelValues(
+			strconv.Itoa(drift.StockId),
+			strconv.Itoa(drift.WarehouseId),
+			strconv.Itoa(drift.ProductId),
+		).Set(float64(drift.ReservedStock - drift.ExpectedReservedStock))
+
+		s.Log.Warn("stock level drift detected", zap.Any("drift", drift))
+	}
+
+	if !s.AutoCorrect {
+		return
+	}
+
+	for _, drift := range drifts {
+		if err := s.CorrectStockDrift(drift.StockId); err != nil {
+			s.Log.Error("error correct stock drift", zap.Error(err), zap.Int("stockId", drift.StockId))
+			continue
+		}
+	}
+}
+
+func (s *service) FindStockDrift(ctx context.Context) ([]models.StockDrift, error) {
+	stockLevels, err := s.StockLevelRepository.Find(ctx, "id,product_id,warehouse_id,stock,reserved_stock", "1 = 1")
+	if err != nil {
+		return nil, err
+	}
+
+	reserved, err := s.StockReservationRepository.SumQty(ctx, "state = ?", constants.RESERVATION_STATE_HELD)
+	if err != nil {
+		return nil, err
+	}
+
+	return CompareStockLevel(stockLevels, reserved), nil
+}
+
+// CorrectStockDrift recomputes the drift of a single stock level under row lock, so orders placed
+// after FindStockDrift are taken into account before anything is written
+func (s *service) CorrectStockDrift(stockId int) error {
+	tx := s.StockLevelRepository.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		s.Log.Error("error begin transaction", zap.Error(err))
+		return err
+	}
+
+	stock, err := s.StockLevelRepository.FindOneTx(tx, "id asc", "id = ?", stockId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	reserved, err := s.StockReservationRepository.SumQtyTx(tx, "stock_id = ? and state = ?", stockId, constants.RESERVATION_STATE_HELD)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	stockLevel := models.StockLevel{
+		ID:            stock.ID,
+		ProductId:     stock.ProductId,
+		WarehouseId:   stock.WarehouseId,
+		Stock:         stock.Stock,
+		ReservedStock: stock.ReservedStock,
+	}
+
+	drifts := CompareStockLevel([]models.StockLevel{stockLevel}, reserved)
+	if len(drifts) == 0 {
+		tx.Rollback()
+		return nil
+	}
+
+	updatedData := models.StockLevel{
+		Stock:         drifts[0].ExpectedStock,
+		ReservedStock: drifts[0].ExpectedReservedStock,
+		UpdatedAt:     time.Now().In(util.LocationTime),
+	}
+	if err := s.StockLevelRepository.UpdateOneTx(tx, &updatedData, "stock,reserved_stock,updated_at", "id = ?", stockId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		s.Log.Error("error commit transaction", zap.Error(err))
+		return err
+	}
+
+	s.Log.Info("stock level drift corrected", zap.Any("drift", drifts[0]))
+	return nil
+}
+
+// CompareStockLevel returns the stock levels whose reserved stock differs from the qty of their
+// held reservations. The physical on-hand qty (stock + reserved_stock) is kept, so the expected
+// available stock is whatever is left of it after the expected reservation
+func CompareStockLevel(stockLevels []models.StockLevel, reserved []models.StockReserved) []models.StockDrift {
+	var drifts []models.StockDrift
+
+	mapReserved := make(map[int]int)
+	for _, r := range reserved {
+		mapReserved[r.StockId] = r.ReservedQty
+	}
+
+	for _, stockLevel := range stockLevels {
+		expectedReserved := mapReserved[stockLevel.ID]
+		if stockLevel.ReservedStock == expectedReserved && stockLevel.Stock >= 0 {
+			continue
+		}
+
+		expectedStock := stockLevel.Stock + stockLevel.ReservedStock - expectedReserved
+		if expectedStock < 0 {
+			expectedStock = 0
+		}
+
+		drifts = append(drifts, models.StockDrift{
+			StockId:               stockLevel.ID,
+			ProductId:             stockLevel.ProductId,
+			WarehouseId:           stockLevel.WarehouseId,
+			Stock:                 stockLevel.Stock,
+			ReservedStock:         stockLevel.ReservedStock,
+			ExpectedStock:         expectedStock,
+			ExpectedReservedStock: expectedReserved,
+		})
+	}
+
+	return drifts
+}
+
+// CheckLowStock records low stock events that were missed by the write paths, resolves the events
+// whose stock is back above the threshold and notifies every event not notified yet
+func (s *service) CheckLowStock() {
+	ctx := context.Background()
+
+	s.Log.Info("running low stock check")
+
+	tx := s.LowStockEventRepository.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		s.Log.Error("error begin transaction", zap.Error(err))
+		return
+	}
+
+	if err := s.CheckLowStockTx(tx); err != nil {
+		tx.Rollback()
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		s.Log.Error("error commit transaction", zap.Error(err))
+		return
+	}
+
+	events, err := s.LowStockEventRepository.Find(ctx, "*", "is_notified = 0 and is_resolved = 0")
+	if err != nil {
+		s.Log.Error("error get low stock event", zap.Error(err))
+		return
+	}
+
+	for _, event := range events {
+		if err := s.Notifier.Notify(ctx, event); err != nil {
+			s.Log.Error("error notify low stock event", zap.Error(err), zap.Int("eventId", event.ID))
+			continue
+		}
+
+		notifiedEvent := models.LowStockEvent{IsNotified: true, UpdatedAt: time.Now().In(util.LocationTime)}
+		if err := s.LowStockEventRepository.Update(ctx, notifiedEvent, "is_notified,updated_at", "id = ?", event.ID); err != nil {
+			s.Log.Error("error update low stock event", zap.Error(err), zap.Int("eventId", event.ID))
+		}
+	}
+}
+
+// RecordLowStockTx records a low stock event for every given stock level that dropped below its
+// reorder threshold and has no open event yet, it is meant to be called right after stock is deducted
+// CheckLowStockTx records an event for every stock below its threshold without an open event and
+// resolves the open events whose stock is back at the threshold or has no threshold anymore
+func (s *service) CheckLowStockTx(tx *gorm.DB) error {
+	if err := s.recordLowStock(tx, "reorder_threshold > 0 and stock < reorder_threshold"); err != nil {
+		s.Log.Error("error record low stock", zap.Error(err))
+		return err
+	}
+
+	openEvents, err := s.LowStockEventRepository.FindTx(tx, "id,stock_id", "is_resolved = 0")
+	if err != nil {
+		s.Log.Error("error get low stock event", zap.Error(err))
+		return err
+	}
+
+	if len(openEvents) == 0 {
+		return nil
+	}
+
+	var stockIds []int
+	for _, event := range openEvents {
+		stockIds = append(stockIds, event.StockId)
+	}
+
+	stocks, err := s.StockLevelRepository.FindTx(tx, "id asc", "id in ?", stockIds)
+	if err != nil {
+		s.Log.Error("error get stock level", zap.Error(err))
+		return err
+	}
+
+	mapRestocked := make(map[int]bool)
+	for _, stock := range stocks {
+		mapRestocked[stock.ID] = stock.Stock >= stock.ReorderThreshold
+	}
+
+	var eventIds []int
+	for _, event := range openEvents {
+		if mapRestocked[event.StockId] {
+			eventIds = append(eventIds, event.ID)
+		}
+	}
+
+	if len(eventIds) == 0 {
+		return nil
+	}
+
+	resolvedEvent := models.LowStockEvent{IsResolved: true, UpdatedAt: time.Now().In(util.LocationTime)}
+	if err := s.LowStockEventRepository.UpdateOneTx(tx, &resolvedEvent, "is_resolved,updated_at", "id in ?", eventIds); err != nil {
+		s.Log.Error("error resolve low stock event", zap.Error(err))
+		return err
+	}
+
+	s.Log.Info("low stock event resolved", zap.Ints("eventIds", eventIds))
+	return nil
+}
+
+func (s *service) RecordLowStockTx(tx *gorm.DB, stockIds ...int) error {
+	if len(stockIds) == 0 {
+		return nil
+	}
+
+	return s.recordLowStock(tx, "id in ? and reorder_threshold > 0 and stock < reorder_threshold", stockIds)
+}
+
+func (s *service) recordLowStock(tx *gorm.DB, query string, args ...interface{}) error {
+	stocks, err := s.StockLevelRepository.FindTx(tx, "id asc", query, args...)
+	if err != nil {
+		return err
+	}
+
+	if len(stocks) == 0 {
+		return nil
+	}
+
+	var stockIds []int
+	for _, stock := range stocks {
+		stockIds = append(stockIds, stock.ID)
+	}
+
+	openEvents, err := s.LowStockEventRepository.FindTx(tx, "id,stock_id", "stock_id in ? and is_resolved = 0", stockIds)
+	if err != nil {
+		return err
+	}
+
+	mapOpenEvent := make(map[int]bool)
+	for _, event := range openEvents {
+		mapOpenEvent[event.StockId] = true
+	}
+
+	for _, stock := range stocks {
+		if mapOpenEvent[stock.ID] {
+			continue
+		}
+
+		event := models.LowStockEvent{
+			StockId:          stock.ID,
+			ProductId:        stock.ProductId,
+			WarehouseId:      stock.WarehouseId,
+			Stock:            stock.Stock,
+			ReorderThreshold: stock.ReorderThreshold,
+			CreatedAt:        time.Now().In(util.LocationTime),
+			UpdatedAt:        time.Now().In(util.LocationTime),
+		}
+		if err := s.LowStockEventRepository.Create(tx, &event); err != nil {
+			s.log(util.TxContext(tx)).Error("error creating low stock event", zap.Error(err))
+			return err
+		}
+
+		s.log(util.TxContext(tx)).Info("low stock event recorded", zap.Int("stockId", stock.ID), zap.Int("stock", stock.Stock))
+	}
+
+	return nil
+}
+
+// AddStockTx adds qty to the available stock of a product in a warehouse, creating the stock level
+// when the warehouse never had the product, and returns the stock level id
+func (s *service) AddStockTx(tx *gorm.DB, warehouseId, productId, qty int) (int, error) {
+	stock, err := s.StockLevelRepository.FindOneTx(tx, "id asc", "warehouse_id = ? and product_id = ?", warehouseId, productId)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		s.log(util.TxContext(tx)).Error("error get stock", zap.Error(err))
+		return 0, err
+	}
+
+	if stock == (models.StockLevelProduct{}) {
+		stockLevel := models.StockLevel{
+			ProductId:   productId,
+			WarehouseId: warehouseId,
+			Stock:       qty,
+			CreatedAt:   time.Now().In(util.LocationTime),
+			UpdatedAt:   time.Now().In(util.LocationTime),
+		}
+		if err := s.StockLevelRepository.Create(tx, &stockLevel); err != nil {
+			s.log(util.TxContext(tx)).Error("error creating stock level", zap.Error(err))
+			return 0, err
+		}
+
+		s.log(util.TxContext(tx)).Info("stock added", zap.Int("stockId", stockLevel.ID), zap.Int("qty", qty))
+		return stockLevel.ID, nil
+	}
+
+	updatedStockLevel := models.StockLevel{Stock: stock.Stock + qty, UpdatedAt: time.Now().In(util.LocationTime)}
+	if err := s.StockLevelRepository.UpdateOneTx(tx, &updatedStockLevel, "stock,updated_at", "id = ?", stock.ID); err != nil {
+		s.log(util.TxContext(tx)).Error("error update stock level", zap.Error(err))
+		return 0, err
+	}
+
+	s.log(util.TxContext(tx)).Info("stock added", zap.Int("stockId", stock.ID), zap.Int("qty", qty))
+	return stock.ID, nil
+}
+
+// DeductStockTx takes qty out of the available stock of a product in a warehouse and returns the
+// stock level id, a low stock event is recorded when the stock drops below its threshold
+func (s *service) DeductStockTx(tx *gorm.DB, warehouseId, productId, qty int) (int, error) {
+	stock, err := s.StockLevelRepository.FindOneTx(tx, "id asc", "warehouse_id = ? and product_id = ?", warehouseId, productId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, constants.StockLevelNotFound
+		}
+
+		s.log(util.TxContext(tx)).Error("error get stock", zap.Error(err))
+		return 0, err
+	}
+
+	if stock.Stock < qty {
+		return 0, constants.NotEnoughStockToTransfer
+	}
+
+	updatedStockLevel := models.StockLevel{Stock: stock.Stock - qty, UpdatedAt: time.Now().In(util.LocationTime)}
+	if err := s.StockLevelRepository.UpdateOneTx(tx, &updatedStockLevel, "stock,updated_at", "id = ?", stock.ID); err != nil {
+		s.log(util.TxContext(tx)).Error("error update stock level", zap.Error(err))
+		return 0, err
+	}
+
+	if err := s.RecordLowStockTx(tx, stock.ID); err != nil {
+		s.log(util.TxContext(tx)).Error("error record low stock", zap.Error(err))
+		return 0, err
+	}
+
+	s.log(util.TxContext(tx)).Info("stock deducted", zap.Int("stockId", stock.ID), zap.Int("qty", qty))
+	return stock.ID, nil
+}
+
+// CheckCapacityTx makes sure the stock on hand plus the stock still in transit to the warehouse fits its
+// capacity, it is meant to be called after stock was added so the whole transaction can be rolled back
+func (s *service) CheckCapacityTx(tx *gorm.DB, warehouseId int) error {
+	warehouse, err := s.WarehouseRepository.FindOneTx(tx, "id,capacity", "id = ?", warehouseId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return constants.WarehouseNotFound
+		}
+
+		s.log(util.TxContext(tx)).Error("error get warehouse", zap.Error(err))
+		return err
+	}
+
+	// zero capacity means the warehouse is not limited
+	if warehouse.Capacity == 0 {
+		return nil
+	}
+
+	stock, err := s.StockLevelRepository.SumStockWarehouseTx(tx, "warehouse_id = ?", warehouseId)
+	if err != nil {
+		s.log(util.TxContext(tx)).Error("error sum stock warehouse", zap.Error(err))
+		return err
+	}
+
+	incoming, err := s.StockTransferItemRepository.SumIncomingTx(tx, "stock_transfers.to_warehouse_id = ? and stock_transfers.status in ?", warehouseId, constants.TRANSFER_STATUS_OPEN)
+	if err != nil {
+		s.log(util.TxContext(tx)).Error("error sum incoming stock", zap.Error(err))
+		return err
+	}
+
+	used := CapacityUsed(stock, incoming)
+	if used > warehouse.Capacity {
+		s.log(util.TxContext(tx)).Warn("warehouse capacity exceeded", zap.Int("warehouseId", warehouseId), zap.Int("capacity", warehouse.Capacity), zap.Int("used", used))
+		return constants.WarehouseCapacityExceed
+	}
+
+	return nil
+}
+
+// CapacityUsed counts every unit that takes space in the warehouse, reserved stock is still on the shelf
+// and stock in transit already has its space booked
+func CapacityUsed(stock models.StockWarehouse, incoming []models.StockIncoming) int {
+	used := int(stock.StockCount + stock.ReservedStockCount)
+	for _, in := range incoming {
+		used += in.IncomingQty
+	}
+
+	return used
+}
@@ -0,0 +1,355 @@
+package inventory
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"test-edot/constants"
+	"test-edot/src/models"
+	"test-edot/src/repository/mocks"
+	"testing"
+)
+
+type (
+	TestCompareStockLevelData struct {
+		name          string
+		stockLevels   []models.StockLevel
+		reserved      []models.StockReserved
+		expectDrifts  int
+		expectStock   int
+		expectReserve int
+	}
+)
+
+func TestCompareStockLevel(t *testing.T) {
+
+	tableTests := []TestCompareStockLevelData{
+		{
+			name:         "test stock level in sync",
+			stockLevels:  []models.StockLevel{{ID: 1, Stock: 5, ReservedStock: 2}},
+			reserved:     []models.StockReserved{{StockId: 1, ReservedQty: 2}},
+			expectDrifts: 0,
+		},
+		{
+			name:          "test reserved stock higher than orders",
+			stockLevels:   []models.StockLevel{{ID: 1, Stock: 5, ReservedStock: 3}},
+			reserved:      []models.StockReserved{{StockId: 1, ReservedQty: 1}},
+			expectDrifts:  1,
+			expectStock:   7,
+			expectReserve: 1,
+		},
+		{
+			name:          "test reserved stock without any order",
+			stockLevels:   []models.StockLevel{{ID: 1, Stock: 0, ReservedStock: 4}},
+			reserved:      []models.StockReserved{},
+			expectDrifts:  1,
+			expectStock:   4,
+			expectReserve: 0,
+		},
+		{
+			name:          "test reserved stock lower than orders",
+			stockLevels:   []models.StockLevel{{ID: 1, Stock: 1, ReservedStock: 0}},
+			reserved:      []models.StockReserved{{StockId: 1, ReservedQty: 3}},
+			expectDrifts:  1,
+			expectStock:   0,
+			expectReserve: 3,
+		},
+	}
+
+	for _, test := range tableTests {
+		t.Run(test.name, func(t *testing.T) {
+			drifts := CompareStockLevel(test.stockLevels, test.reserved)
+
+			assert.Equal(t, test.expectDrifts, len(drifts))
+			if test.expectDrifts > 0 {
+				assert.Equal(t, test.expectStock, drifts[0].ExpectedStock)
+				assert.Equal(t, test.expectReserve, drifts[0].ExpectedReservedStock)
+			}
+		})
+	}
+}
+
+type (
+	TestReserveData struct {
+		name          string
+		stock         models.StockLevelProduct
+		qty           int
+		expectStock   int
+		expectReserve int
+		expectErr     error
+	}
+
+	TestCapacityUsedData struct {
+		name       string
+		stock      models.StockWarehouse
+		incoming   []models.StockIncoming
+		expectUsed int
+	}
+
+	TestSettleReservationData struct {
+		name          string
+		state         string
+		reservations  []models.StockReservation
+		stock         models.StockLevelProduct
+		expectStock   int
+		expectReserve int
+		expectQty     int
+		expectErr     error
+	}
+)
+
+func TestReserveTx(t *testing.T) {
+
+	tableTests := []TestReserveData{
+		{
+			name:          "test reserve available stock",
+			stock:         models.StockLevelProduct{ID: 1, ProductId: 1, WarehouseId: 1, Stock: 5, ReservedStock: 1},
+			qty:           3,
+			expectStock:   2,
+			expectReserve: 4,
+		},
+		{
+			name:      "test reserve more than available stock",
+			stock:     models.StockLevelProduct{ID: 1, ProductId: 1, WarehouseId: 1, Stock: 2},
+			qty:       3,
+			expectErr: constants.NotEnoughStockProduct,
+		},
+		{
+			name:      "test reserve zero qty",
+			stock:     models.StockLevelProduct{ID: 1, ProductId: 1, WarehouseId: 1, Stock: 2},
+			qty:       0,
+			expectErr: constants.QtyMustPositive,
+		},
+	}
+
+	for _, test := range tableTests {
+		t.Run(test.name, func(t *testing.T) {
+
+			var updated models.StockLevel
+			tx := gorm.DB{}
+			mockStockRepo := new(mocks.StockLevelRepositoryInterface)
+			mockReservationRepo := new(mocks.StockReservationRepositoryInterface)
+
+			mockStockRepo.On("FindOneTx", &tx, "id asc", "id = ?", test.stock.ID).Return(test.stock, nil)
+			mockStockRepo.On("UpdateOneTx", &tx, mock.AnythingOfType("*models.StockLevel"), mock.Anything, mock.Anything, mock.Anything).
+				Run(func(args mock.Arguments) { updated = *args.Get(1).(*models.StockLevel) }).Return(nil)
+			mockStockRepo.On("FindTx", &tx, "id asc", mock.Anything, mock.Anything).Return([]models.StockLevelProduct{}, nil)
+			mockReservationRepo.On("Create", &tx, mock.AnythingOfType("*models.StockReservation")).Return(nil)
+
+			s := service{Log: zap.NewNop(), StockLevelRepository: mockStockRepo, StockReservationRepository: mockReservationRepo}
+
+			reservation := models.StockReservation{StockId: test.stock.ID, Qty: test.qty, OwnerType: constants.RESERVATION_OWNER_ORDER, OwnerId: 1}
+			err := s.ReserveTx(&tx, &reservation)
+			if test.expectErr != nil {
+				assert.ErrorIs(t, err, test.expectErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expectStock, updated.Stock)
+				assert.Equal(t, test.expectReserve, updated.ReservedStock)
+				assert.Equal(t, constants.RESERVATION_STATE_HELD, reservation.State)
+				assert.Equal(t, test.stock.WarehouseId, reservation.WarehouseId)
+			}
+		})
+	}
+}
+
+func TestSettleReservationTx(t *testing.T) {
+
+	tableTests := []TestSettleReservationData{
+		{
+			name:          "test commit reservation",
+			state:         constants.RESERVATION_STATE_COMMITTED,
+			reservations:  []models.StockReservation{{ID: 1, StockId: 1, Qty: 2}},
+			stock:         models.StockLevelProduct{ID: 1, Stock: 3, ReservedStock: 2},
+			expectReserve: 0,
+		},
+		{
+			name:          "test release reservation",
+			state:         constants.RESERVATION_STATE_RELEASED,
+			reservations:  []models.StockReservation{{ID: 1, StockId: 1, Qty: 2}},
+			stock:         models.StockLevelProduct{ID: 1, Stock: 3, ReservedStock: 2},
+			expectStock:   5,
+			expectReserve: 0,
+			expectQty:     2,
+		},
+		{
+			name:         "test commit without held reservation",
+			state:        constants.RESERVATION_STATE_COMMITTED,
+			reservations: []models.StockReservation{},
+			expectErr:    constants.ReservationNotFound,
+		},
+	}
+
+	for _, test := range tableTests {
+		t.Run(test.name, func(t *testing.T) {
+
+			var updated models.StockLevel
+			tx := gorm.DB{}
+			mockStockRepo := new(mocks.StockLevelRepositoryInterface)
+			mockReservationRepo := new(mocks.StockReservationRepositoryInterface)
+
+			mockReservationRepo.On("FindTx", &tx, "*", mock.Anything, constants.RESERVATION_OWNER_ORDER, 1, constants.RESERVATION_STATE_HELD).Return(test.reservations, nil)
+			mockReservationRepo.On("UpdateOneTx", &tx, mock.AnythingOfType("*models.StockReservation"), "state,updated_at", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			mockStockRepo.On("FindOneTx", &tx, "id asc", "id = ?", test.stock.ID).Return(test.stock, nil)
+			mockStockRepo.On("UpdateOneTx", &tx, mock.AnythingOfType("*models.StockLevel"), mock.Anything, mock.Anything, mock.Anything).
+				Run(func(args mock.Arguments) { updated = *args.Get(1).(*models.StockLevel) }).Return(nil)
+
+			s := service{Log: zap.NewNop(), StockLevelRepository: mockStockRepo, StockReservationRepository: mockReservationRepo}
+
+			var (
+				qty int
+				err error
+			)
+			if test.state == constants.RESERVATION_STATE_COMMITTED {
+				err = s.CommitReservationTx(&tx, constants.RESERVATION_OWNER_ORDER, 1)
+			} else {
+				qty, err = s.ReleaseReservationTx(&tx, constants.RESERVATION_OWNER_ORDER, 1)
+			}
+
+			if test.expectErr != nil {
+				assert.ErrorIs(t, err, test.expectErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expectReserve, updated.ReservedStock)
+				if test.state == constants.RESERVATION_STATE_RELEASED {
+					assert.Equal(t, test.expectStock, updated.Stock)
+					assert.Equal(t, test.expectQty, qty)
+				}
+			}
+		})
+	}
+}
+
+func TestCapacityUsed(t *testing.T) {
+
+	tableTests := []TestCapacityUsedData{
+		{
+			name:       "test empty warehouse",
+			expectUsed: 0,
+		},
+		{
+			name:       "test stock and reserved stock",
+			stock:      models.StockWarehouse{StockCount: 10, ReservedStockCount: 5},
+			expectUsed: 15,
+		},
+		{
+			name:       "test stock with incoming transfer",
+			stock:      models.StockWarehouse{StockCount: 10, ReservedStockCount: 5},
+			incoming:   []models.StockIncoming{{WarehouseId: 1, IncomingQty: 7}},
+			expectUsed: 22,
+		},
+	}
+
+	for _, test := range tableTests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expectUsed, CapacityUsed(test.stock, test.incoming))
+		})
+	}
+}
+
+type (
+	TestRecordLowStockData struct {
+		name        string
+		stocks      []models.StockLevelProduct
+		openEvents  []models.LowStockEvent
+		expectEvent bool
+	}
+
+	TestCheckLowStockData struct {
+		name          string
+		stock         models.StockLevelProduct
+		expectResolve bool
+	}
+)
+
+func TestRecordLowStockTx(t *testing.T) {
+
+	tableTests := []TestRecordLowStockData{
+		{
+			name:        "test stock crossing the threshold",
+			stocks:      []models.StockLevelProduct{{ID: 1, ProductId: 1, WarehouseId: 1, Stock: 3, ReorderThreshold: 5}},
+			expectEvent: true,
+		},
+		{
+			name:       "test stock still low does not fire twice",
+			stocks:     []models.StockLevelProduct{{ID: 1, ProductId: 1, WarehouseId: 1, Stock: 2, ReorderThreshold: 5}},
+			openEvents: []models.LowStockEvent{{ID: 7, StockId: 1}},
+		},
+		{
+			name:   "test stock above the threshold",
+			stocks: []models.StockLevelProduct{},
+		},
+	}
+
+	for _, test := range tableTests {
+		t.Run(test.name, func(t *testing.T) {
+			var created models.LowStockEvent
+			tx := gorm.DB{}
+			mockStockRepo := new(mocks.StockLevelRepositoryInterface)
+			mockEventRepo := new(mocks.LowStockEventRepositoryInterface)
+
+			mockStockRepo.On("FindTx", &tx, "id asc", "id in ? and reorder_threshold > 0 and stock < reorder_threshold", []int{1}).Return(test.stocks, nil)
+			mockEventRepo.On("FindTx", &tx, "id,stock_id", "stock_id in ? and is_resolved = 0", []int{1}).Return(test.openEvents, nil)
+			mockEventRepo.On("Create", &tx, mock.AnythingOfType("*models.LowStockEvent")).
+				Run(func(args mock.Arguments) { created = *args.Get(1).(*models.LowStockEvent) }).Return(nil)
+
+			s := service{Log: zap.NewNop(), StockLevelRepository: mockStockRepo, LowStockEventRepository: mockEventRepo}
+
+			err := s.RecordLowStockTx(&tx, 1)
+			assert.NoError(t, err)
+			if test.expectEvent {
+				mockEventRepo.AssertNumberOfCalls(t, "Create", 1)
+				assert.Equal(t, test.stocks[0].Stock, created.Stock)
+				assert.Equal(t, test.stocks[0].ReorderThreshold, created.ReorderThreshold)
+				assert.False(t, created.IsResolved)
+			} else {
+				mockEventRepo.AssertNotCalled(t, "Create", &tx, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestCheckLowStockTx(t *testing.T) {
+
+	tableTests := []TestCheckLowStockData{
+		{
+			name:          "test restock resolves the event",
+			stock:         models.StockLevelProduct{ID: 1, Stock: 6, ReorderThreshold: 5},
+			expectResolve: true,
+		},
+		{
+			name:  "test stock still below the threshold keeps the event",
+			stock: models.StockLevelProduct{ID: 1, Stock: 4, ReorderThreshold: 5},
+		},
+		{
+			name:          "test removed threshold resolves the event",
+			stock:         models.StockLevelProduct{ID: 1},
+			expectResolve: true,
+		},
+	}
+
+	for _, test := range tableTests {
+		t.Run(test.name, func(t *testing.T) {
+			tx := gorm.DB{}
+			mockStockRepo := new(mocks.StockLevelRepositoryInterface)
+			mockEventRepo := new(mocks.LowStockEventRepositoryInterface)
+
+			// every low stock already has its event, only the resolving is left
+			mockStockRepo.On("FindTx", &tx, "id asc", "reorder_threshold > 0 and stock < reorder_threshold").Return([]models.StockLevelProduct{}, nil)
+			mockStockRepo.On("FindTx", &tx, "id asc", "id in ?", []int{1}).Return([]models.StockLevelProduct{test.stock}, nil)
+			mockEventRepo.On("FindTx", &tx, "id,stock_id", "is_resolved = 0").Return([]models.LowStockEvent{{ID: 7, StockId: 1}}, nil)
+			mockEventRepo.On("UpdateOneTx", &tx, mock.AnythingOfType("*models.LowStockEvent"), "is_resolved,updated_at", "id in ?", []int{7}).Return(nil)
+
+			s := service{Log: zap.NewNop(), StockLevelRepository: mockStockRepo, LowStockEventRepository: mockEventRepo}
+
+			err := s.CheckLowStockTx(&tx)
+			assert.NoError(t, err)
+			if test.expectResolve {
+				mockEventRepo.AssertNumberOfCalls(t, "UpdateOneTx", 1)
+			} else {
+				mockEventRepo.AssertNotCalled(t, "UpdateOneTx", &tx, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
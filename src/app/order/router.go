@@ -2,11 +2,11 @@ package order
 
 import (
 	"github.com/gin-gonic/gin"
+	"test-edot/constants"
 	"test-edot/src/middleware"
 )
 
-func (h *handler) OrderBearerRouter(g *gin.RouterGroup) {
-	g.Use(middleware.BearerUser())
-	g.POST("", h.CreateOrder)
-	g.PUT(":order_id/payment", h.PaymentOrder)
+func (h *handler) OrderRouter(g *gin.RouterGroup) {
+	g.POST("", middleware.RateLimit(constants.RATE_LIMIT_GROUP_ORDER), middleware.Authorize(constants.PERMISSION_ORDER_CREATE), h.CreateOrder)
+	g.PUT(":order_id/payment", middleware.Authorize(constants.PERMISSION_ORDER_PAY), h.PaymentOrder)
 }
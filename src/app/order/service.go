@@ -5,12 +5,20 @@ import (
 	"go.uber.org/zap"
 	"golang.org/x/net/context"
 	"gorm.io/gorm"
+	"math"
+	"sort"
 	"strconv"
+	"strings"
 	"test-edot/constants"
+	"test-edot/metrics"
+	"test-edot/src/app/address"
+	"test-edot/src/app/audit"
+	"test-edot/src/app/inventory"
 	"test-edot/src/dto"
 	"test-edot/src/factory"
 	"test-edot/src/models"
 	"test-edot/src/repository"
+	"test-edot/src/tracing"
 	"test-edot/util"
 	"time"
 )
@@ -29,6 +37,10 @@ type service struct {
 	OrderRepository        repository.OrderRepositoryInterface
 	StockLevelRepository   repository.StockLevelRepositoryInterface
 	OrderDetailsRepository repository.OrderDetailRepositoryInterface
+	WarehouseRepository    repository.WarehouseRepositoryInterface
+	InventoryService       inventory.Service
+	AddressService         address.Service
+	AuditService           audit.Service
 }
 
 func NewService(f *factory.Factory) Service {
@@ -40,12 +52,24 @@ func NewService(f *factory.Factory) Service {
 		OrderRepository:        f.OrderRepository,
 		StockLevelRepository:   f.StockLevelRepository,
 		OrderDetailsRepository: f.OrderDetailRepository,
+		WarehouseRepository:    f.WarehouseRepository,
+		InventoryService:       inventory.NewService(f),
+		AddressService:         address.NewService(f),
+		AuditService:           audit.NewService(f),
 	}
 }
 
+// log is the logger of the request that made the call, see middleware.RequestId
+func (s *service) log(ctx context.Context) *zap.Logger {
+	return util.Logger(ctx, s.Log)
+}
+
 func (s *service) PaymentOrder(ctx context.Context, userClaim dto.UserClaimJwt, orderId int) error {
+	ctx, span := tracing.Start(ctx, "order.PaymentOrder")
+	defer span.End()
+
 	now := time.Now().In(util.LocationTime)
-	tx := s.OrderRepository.Begin()
+	tx := s.OrderRepository.Begin().WithContext(ctx)
 	defer func() {
 		if r := recover(); r != nil {
 			tx.Rollback()
@@ -60,7 +84,7 @@ func (s *service) PaymentOrder(ctx context.Context, userClaim dto.UserClaimJwt,
 			return constants.OrderNotFound
 		}
 
-		s.Log.Error("error get order", zap.Error(err))
+		s.log(ctx).Error("error get order", zap.Error(err))
 		return err
 	}
 
@@ -70,20 +94,64 @@ func (s *service) PaymentOrder(ctx context.Context, userClaim dto.UserClaimJwt,
 	}
 
 	if err := tx.Error; err != nil {
-		s.Log.Error("error begin transaction", zap.Error(err))
+		s.log(ctx).Error("error begin transaction", zap.Error(err))
 		return err
 	}
 
 	if err := tx.Commit().Error; err != nil {
-		s.Log.Error("error commit transaction", zap.Error(err))
+		s.log(ctx).Error("error commit transaction", zap.Error(err))
 		return err
 	}
 
+	metrics.OrderTotal.WithLabelValues("paid").Inc()
+
+	s.RecordOrder(ctx, userClaim, order.Id, constants.AUDIT_ACTION_ORDER_PAY,
+		map[string]any{"order_no": order.OrderNo, "is_payment": false},
+		map[string]any{"order_no": order.OrderNo, "is_payment": true})
+
 	return nil
 }
 
+// RecordOrder writes the audit event once for every shop that sells a product of the order,
+// so each owner sees the order in the events of its own shop
+func (s *service) RecordOrder(ctx context.Context, userClaim dto.UserClaimJwt, orderId int, action string, before, after any) {
+	q := "id in (select shop_id from products where id in (select product_id from order_details where order_id = ?))"
+	shops, err := s.ShopRepository.Find(ctx, "id", q, orderId)
+	if err != nil {
+		s.log(ctx).Error("error fetch order shops", zap.Error(err), zap.Int("orderId", orderId))
+		return
+	}
+
+	for _, shop := range shops {
+		s.AuditService.Record(ctx, userClaim, dto.AuditEntry{
+			ShopId:       shop.ID,
+			Action:       action,
+			ResourceType: constants.AUDIT_RESOURCE_ORDER,
+			ResourceId:   orderId,
+			Before:       before,
+			After:        after,
+		})
+	}
+}
+
 func (s *service) CreateOrder(ctx context.Context, userClaim dto.UserClaimJwt, payload dto.PayloadCreateOrder) (models.Order, error) {
-	tx := s.ProductRepository.Begin()
+	ctx, span := tracing.Start(ctx, "order.CreateOrder")
+	defer span.End()
+
+	if err := s.ValidateUserVerified(ctx, userClaim); err != nil {
+		return models.Order{}, err
+	}
+
+	if err := s.ValidateShopOrder(ctx, payload); err != nil {
+		return models.Order{}, err
+	}
+
+	shippingAddress, err := s.AddressService.FindShippingAddress(ctx, userClaim, payload.AddressId)
+	if err != nil {
+		return models.Order{}, err
+	}
+
+	tx := s.ProductRepository.Begin().WithContext(ctx)
 	defer func() {
 		if r := recover(); r != nil {
 			tx.Rollback()
@@ -91,30 +159,80 @@ func (s *service) CreateOrder(ctx context.Context, userClaim dto.UserClaimJwt, p
 	}()
 
 	if err := tx.Error; err != nil {
-		s.Log.Error("error begin transaction", zap.Error(err))
+		s.log(ctx).Error("error begin transaction", zap.Error(err))
 		return models.Order{}, err
 	}
 
-	orders, grandTotal, err := s.ProcessOrder(tx, payload)
+	orders, grandTotal, err := s.ProcessOrder(tx, payload, shippingAddress.Address)
 	if err != nil {
 		tx.Rollback()
 		return models.Order{}, err
 	}
 
-	order, err := s.InsertOrder(tx, userClaim, orders, grandTotal)
+	order, err := s.InsertOrder(tx, userClaim, shippingAddress, orders, grandTotal)
 	if err != nil {
 		tx.Rollback()
 		return models.Order{}, err
 	}
 
 	if err := tx.Commit().Error; err != nil {
-		s.Log.Error("error commit transaction", zap.Error(err))
+		s.log(ctx).Error("error commit transaction", zap.Error(err))
 		return models.Order{}, err
 	}
 
+	reservedQty := 0
+	for _, item := range orders {
+		reservedQty += item.Qty
+	}
+	metrics.OrderTotal.WithLabelValues("created").Inc()
+	metrics.StockReservationQtyTotal.WithLabelValues("reserved").Add(float64(reservedQty))
+
+	s.RecordOrder(ctx, userClaim, order.Id, constants.AUDIT_ACTION_ORDER_CREATE, nil,
+		map[string]any{"order_no": order.OrderNo, "total": order.Total})
+
 	return order, nil
 }
 
+// ValidateUserVerified only lets a user with a verified email or phone place an order
+func (s *service) ValidateUserVerified(ctx context.Context, userClaim dto.UserClaimJwt) error {
+	user, err := s.UserRepository.FindOne(ctx, "id,email_verified_at,phone_verified_at", "id = ?", userClaim.UserId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return constants.UserNotFound
+		}
+
+		s.log(ctx).Error("error get user", zap.Error(err))
+		return err
+	}
+
+	if user.EmailVerifiedAt == nil && user.PhoneVerifiedAt == nil {
+		return constants.UserNotVerified
+	}
+
+	return nil
+}
+
+// ValidateShopOrder rejects the order when one of its products belongs to a closed shop
+func (s *service) ValidateShopOrder(ctx context.Context, payload dto.PayloadCreateOrder) error {
+	for _, item := range payload.Items {
+		shop, err := s.ShopRepository.FindOne(ctx, "id,is_active", "id = (select shop_id from products where id = ?)", item.ProductId)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return constants.ProductNotFound
+			}
+
+			s.log(ctx).Error("error get shop", zap.Error(err), zap.Int("productId", item.ProductId))
+			return err
+		}
+
+		if !shop.IsActive {
+			return constants.ShopClosed
+		}
+	}
+
+	return nil
+}
+
 func (s *service) ReleaseStockOrder() {
 	ctx := context.Background()
 
@@ -138,29 +256,15 @@ func (s *service) ReleaseStockOrder() {
 		return
 	}
 
+	releasedQty := 0
 	for _, order := range orders {
-		detailOrders, err := s.OrderDetailsRepository.FindTx(tx, "id,stock_id,product_id,qty", "order_id = ? and expired_at < ?", order.Id, now.Format("2006-01-02 15:04:05"))
+		qty, err := s.InventoryService.ReleaseReservationTx(tx, constants.RESERVATION_OWNER_ORDER, order.Id)
 		if err != nil {
 			tx.Rollback()
-			s.Log.Error("error get order", zap.Error(err))
+			s.Log.Error("error release stock reservation", zap.Error(err))
 			return
 		}
-
-		for _, detailOrder := range detailOrders {
-			stock, err := s.StockLevelRepository.FindOneTx(tx, "updated_at asc", "id = ? and product_id = ?", detailOrder.StockId, detailOrder.ProductId)
-			if err != nil {
-				tx.Rollback()
-				s.Log.Error("error get order", zap.Error(err))
-				return
-			}
-
-			stockLevel := models.StockLevel{ReservedStock: stock.ReservedStock - detailOrder.Qty, Stock: stock.Stock + detailOrder.Qty}
-			if err := s.StockLevelRepository.UpdateOneTx(tx, &stockLevel, "reserved_stock,stock", "id = ?", stock.ID); err != nil {
-				tx.Rollback()
-				s.Log.Error("error update stock", zap.Error(err))
-				return
-			}
-		}
+		releasedQty += qty
 
 		updatedOrder := models.Order{IsRelease: true, UpdatedAt: time.Now().In(util.LocationTime)}
 		if err := s.OrderRepository.UpdateOneTx(tx, &updatedOrder, "is_release,updated_at", "id = ?", order.Id); err != nil {
@@ -176,9 +280,12 @@ func (s *service) ReleaseStockOrder() {
 		s.Log.Error("error commit transaction", zap.Error(err))
 		return
 	}
+
+	metrics.OrderTotal.WithLabelValues("expired").Add(float64(len(orders)))
+	metrics.StockReservationQtyTotal.WithLabelValues("released").Add(float64(releasedQty))
 }
 
-func (s *service) ProcessOrder(tx *gorm.DB, payload dto.PayloadCreateOrder) ([]models.OrderDetail, float64, error) {
+func (s *service) ProcessOrder(tx *gorm.DB, payload dto.PayloadCreateOrder, destination models.ShippingAddress) ([]models.OrderDetail, float64, error) {
 	var (
 		orderDetails []models.OrderDetail
 		grandTotal   float64
@@ -203,6 +310,11 @@ func (s *service) ProcessOrder(tx *gorm.DB, payload dto.PayloadCreateOrder) ([]m
 			return []models.OrderDetail{}, 0, constants.StockProductEmpty
 		}
 
+		stocks, err = s.SortStockByDestination(tx, stocks, destination)
+		if err != nil {
+			return []models.OrderDetail{}, 0, err
+		}
+
 		for _, stock := range stocks {
 			if qty == 0 {
 				break
@@ -217,7 +329,6 @@ func (s *service) ProcessOrder(tx *gorm.DB, payload dto.PayloadCreateOrder) ([]m
 			}
 
 			totalPrice := stock.Product.Price * float64(qtyStock)
-			// update stock level
 			orderDetails = append(orderDetails, models.OrderDetail{
 				ProductId: item.ProductId,
 				StockId:   stock.ID,
@@ -226,11 +337,6 @@ func (s *service) ProcessOrder(tx *gorm.DB, payload dto.PayloadCreateOrder) ([]m
 				CreatedAt: time.Now().In(util.LocationTime),
 				UpdatedAt: time.Now().In(util.LocationTime),
 			})
-			updatedData := models.StockLevel{Stock: stock.Stock - qtyStock, ReservedStock: stock.ReservedStock + qtyStock}
-			if err := s.StockLevelRepository.UpdateOneTx(tx, &updatedData, "stock,reserved_stock", "id = ?", stock.ID); err != nil {
-				return []models.OrderDetail{}, 0, err
-			}
-
 			grandTotal += totalPrice
 		}
 
@@ -244,7 +350,68 @@ func (s *service) ProcessOrder(tx *gorm.DB, payload dto.PayloadCreateOrder) ([]m
 	return orderDetails, grandTotal, nil
 }
 
-func (s *service) InsertOrder(tx *gorm.DB, userClaim dto.UserClaimJwt, items []models.OrderDetail, grandTotal float64) (models.Order, error) {
+// SortStockByDestination the stock of the warehouse closest to the shipping address is taken first
+func (s *service) SortStockByDestination(tx *gorm.DB, stocks []models.StockLevelProduct, destination models.ShippingAddress) ([]models.StockLevelProduct, error) {
+	if len(stocks) < 2 {
+		return stocks, nil
+	}
+
+	warehouseIds := make([]int, 0, len(stocks))
+	for _, stock := range stocks {
+		warehouseIds = append(warehouseIds, stock.WarehouseId)
+	}
+
+	warehouses, err := s.WarehouseRepository.FindTx(tx, "id,latitude,longitude,address_city,address_province", "id in ?", warehouseIds)
+	if err != nil {
+		s.log(util.TxContext(tx)).Error("error get warehouse", zap.Error(err))
+		return nil, err
+	}
+
+	return SortStockByWarehouse(stocks, warehouses, destination), nil
+}
+
+// SortStockByWarehouse ranks the warehouse in the same city first, then the same province, then the rest.
+// Inside a rank the nearest warehouse by coordinate goes first, stocks at the same rank keep their order
+func SortStockByWarehouse(stocks []models.StockLevelProduct, warehouses []models.Warehouse, destination models.ShippingAddress) []models.StockLevelProduct {
+	type rank struct {
+		tier     int
+		distance float64
+	}
+
+	ranks := make(map[int]rank, len(warehouses))
+	for _, warehouse := range warehouses {
+		r := rank{tier: 2, distance: math.MaxFloat64}
+		if destination.City != "" && strings.EqualFold(warehouse.Address.City, destination.City) {
+			r.tier = 0
+		} else if destination.Province != "" && strings.EqualFold(warehouse.Address.Province, destination.Province) {
+			r.tier = 1
+		}
+
+		if warehouse.Latitude != nil && warehouse.Longitude != nil && destination.Latitude != nil && destination.Longitude != nil {
+			r.distance = util.DistanceKm(*warehouse.Latitude, *warehouse.Longitude, *destination.Latitude, *destination.Longitude)
+		}
+		ranks[warehouse.ID] = r
+	}
+
+	sorted := make([]models.StockLevelProduct, len(stocks))
+	copy(sorted, stocks)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, okA := ranks[sorted[i].WarehouseId]
+		b, okB := ranks[sorted[j].WarehouseId]
+		if !okA || !okB {
+			return okA && !okB
+		}
+
+		if a.tier != b.tier {
+			return a.tier < b.tier
+		}
+		return a.distance < b.distance
+	})
+
+	return sorted
+}
+
+func (s *service) InsertOrder(tx *gorm.DB, userClaim dto.UserClaimJwt, shippingAddress models.UserAddress, items []models.OrderDetail, grandTotal float64) (models.Order, error) {
 	expireOrderMinutes, err := strconv.Atoi(util.GetEnv("ORDER_EXPIRE_MINUTE", ""))
 	if err != nil {
 		return models.Order{}, err
@@ -254,13 +421,15 @@ func (s *service) InsertOrder(tx *gorm.DB, userClaim dto.UserClaimJwt, items []m
 	expiredAt := now.Add(time.Minute * time.Duration(expireOrderMinutes))
 
 	dataOrder := models.Order{
-		OrderNo:   util.CreateOrderNo(),
-		UserId:    userClaim.UserId,
-		IsPayment: false,
-		Total:     grandTotal,
-		ExpiredAt: expiredAt,
-		CreatedAt: now,
-		UpdatedAt: now,
+		OrderNo:         util.CreateOrderNo(),
+		UserId:          userClaim.UserId,
+		AddressId:       &shippingAddress.ID,
+		IsPayment:       false,
+		Total:           grandTotal,
+		ShippingAddress: shippingAddress.Address,
+		ExpiredAt:       expiredAt,
+		CreatedAt:       now,
+		UpdatedAt:       now,
 	}
 
 	if err := s.OrderRepository.Create(tx, &dataOrder); err != nil {
@@ -282,37 +451,31 @@ func (s *service) InsertOrder(tx *gorm.DB, userClaim dto.UserClaimJwt, items []m
 		if err := s.OrderDetailsRepository.Create(tx, &orderDetail); err != nil {
 			return models.Order{}, err
 		}
+
+		reservation := models.StockReservation{
+			StockId:   item.StockId,
+			Qty:       item.Qty,
+			OwnerType: constants.RESERVATION_OWNER_ORDER,
+			OwnerId:   dataOrder.Id,
+			ExpiredAt: expiredAt,
+		}
+		if err := s.InventoryService.ReserveTx(tx, &reservation); err != nil {
+			return models.Order{}, err
+		}
 	}
 
 	return dataOrder, nil
 }
 
 func (s *service) ProcessPaymentOrder(tx *gorm.DB, order models.Order) error {
-	fields := "id,product_id,stock_id,qty"
-	orderDetails, err := s.OrderDetailsRepository.FindTx(tx, fields, "order_id = ? ", order.Id)
-	if err != nil {
-		s.Log.Error("error get order details", zap.Error(err))
+	if err := s.InventoryService.CommitReservationTx(tx, constants.RESERVATION_OWNER_ORDER, order.Id); err != nil {
+		s.log(util.TxContext(tx)).Error("error commit stock reservation", zap.Error(err), zap.Int("orderId", order.Id))
 		return err
 	}
 
-	for _, detail := range orderDetails {
-		stock, err := s.StockLevelRepository.FindOneTx(tx, "id asc", "id = ?", detail.StockId)
-		if err != nil {
-			return err
-		}
-
-		updatedData := models.StockLevel{ReservedStock: stock.ReservedStock - detail.Qty, UpdatedAt: time.Now().In(util.LocationTime)}
-		if err := s.StockLevelRepository.UpdateOneTx(tx, &updatedData, "reserved_stock,updated_at", "id = ?", detail.StockId); err != nil {
-			s.Log.Error("error update stock", zap.Error(err))
-			return err
-		}
-
-		s.Log.Info("successfully deduct stock", zap.Int("stockId", detail.StockId))
-	}
-
 	updateOrder := models.Order{IsPayment: true, UpdatedAt: time.Now().In(util.LocationTime)}
 	if err := s.OrderRepository.UpdateOneTx(tx, &updateOrder, "is_payment,updated_at", "id = ?", order.Id); err != nil {
-		s.Log.Error("error update order", zap.Error(err))
+		s.log(util.TxContext(tx)).Error("error update order", zap.Error(err))
 		return err
 	}
 
@@ -90,10 +90,12 @@ func TestProcessOrder(t *testing.T) {
 			mockRepo.On("FindTx", &tx, "updated_at asc", "product_id = ? and stock > 0", test.payload.Items[0].ProductId).Return(test.mockResponse, nil)
 
 			mockRepo.On("UpdateOneTx", &tx, mock.AnythingOfType("*models.StockLevel"), mock.Anything, mock.Anything, 0).Return(nil)
+			mockWarehouseRepo := new(mocks.WarehouseRepositoryInterface)
+			mockWarehouseRepo.On("FindTx", &tx, mock.Anything, "id in ?", mock.Anything).Return([]models.Warehouse{{ID: 1}, {ID: 2}}, nil)
 
-			s := service{StockLevelRepository: mockRepo}
+			s := service{StockLevelRepository: mockRepo, WarehouseRepository: mockWarehouseRepo}
 
-			order, totalPrice, err := s.ProcessOrder(&tx, test.payload)
+			order, totalPrice, err := s.ProcessOrder(&tx, test.payload, models.ShippingAddress{})
 			if test.isErr {
 				assert.Error(t, err)
 			} else {
@@ -103,3 +105,57 @@ func TestProcessOrder(t *testing.T) {
 		})
 	}
 }
+
+type (
+	TestSortStockByWarehouseData struct {
+		name             string
+		destination      models.ShippingAddress
+		warehouses       []models.Warehouse
+		expectWarehouses []int
+	}
+)
+
+func TestSortStockByWarehouse(t *testing.T) {
+	coordinate := func(v float64) *float64 { return &v }
+	stocks := []models.StockLevelProduct{{ID: 1, WarehouseId: 1}, {ID: 2, WarehouseId: 2}, {ID: 3, WarehouseId: 3}}
+
+	tableTests := []TestSortStockByWarehouseData{
+		{
+			name:        "test same city first then same province",
+			destination: models.ShippingAddress{City: "Bandung", Province: "Jawa Barat"},
+			warehouses: []models.Warehouse{
+				{ID: 1, Address: models.WarehouseAddress{City: "Jakarta", Province: "DKI Jakarta"}},
+				{ID: 2, Address: models.WarehouseAddress{City: "Bekasi", Province: "Jawa Barat"}},
+				{ID: 3, Address: models.WarehouseAddress{City: "bandung", Province: "Jawa Barat"}},
+			},
+			expectWarehouses: []int{3, 2, 1},
+		},
+		{
+			name:        "test nearest coordinate inside same rank",
+			destination: models.ShippingAddress{Latitude: coordinate(-6.9175), Longitude: coordinate(107.6191)},
+			warehouses: []models.Warehouse{
+				{ID: 1, Latitude: coordinate(-6.2088), Longitude: coordinate(106.8456)},
+				{ID: 2},
+				{ID: 3, Latitude: coordinate(-6.9147), Longitude: coordinate(107.6098)},
+			},
+			expectWarehouses: []int{3, 1, 2},
+		},
+		{
+			name:             "test unknown destination keep order",
+			warehouses:       []models.Warehouse{{ID: 1}, {ID: 2}, {ID: 3}},
+			expectWarehouses: []int{1, 2, 3},
+		},
+	}
+
+	for _, test := range tableTests {
+		t.Run(test.name, func(t *testing.T) {
+			sorted := SortStockByWarehouse(stocks, test.warehouses, test.destination)
+
+			var warehouseIds []int
+			for _, stock := range sorted {
+				warehouseIds = append(warehouseIds, stock.WarehouseId)
+			}
+			assert.Equal(t, test.expectWarehouses, warehouseIds)
+		})
+	}
+}
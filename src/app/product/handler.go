@@ -86,7 +86,8 @@ func (h *handler) TransferProduct(g *gin.Context) {
 		return
 	}
 
-	if err := h.service.TransferProductWarehouse(g, payload, userClaim, productId); err != nil {
+	res, err := h.service.TransferProductWarehouse(g, payload, userClaim, productId)
+	if err != nil {
 		g.JSON(http.StatusBadRequest, dto.ErrorResponse{
 			Error: err.Error(),
 		})
@@ -94,7 +95,8 @@ func (h *handler) TransferProduct(g *gin.Context) {
 	}
 
 	g.JSON(http.StatusOK, dto.Response{
-		Message: "success transfer product",
+		Message: "success dispatch product transfer",
+		Data:    res,
 	})
 }
 
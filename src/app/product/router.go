@@ -2,6 +2,7 @@ package product
 
 import (
 	"github.com/gin-gonic/gin"
+	"test-edot/constants"
 	"test-edot/src/middleware"
 )
 
@@ -9,9 +10,8 @@ func (h *handler) ProductRouter(g *gin.RouterGroup) {
 	g.GET("", h.ProductList)
 }
 
-func (h *handler) ProductBearerShopRouter(g *gin.RouterGroup) {
-	g.Use(middleware.BearerShop())
-	g.POST("", h.AddProduct)
-	g.POST("/:product_id/transfer", h.TransferProduct)
-	g.GET("/:product_id/detail", h.DetailProduct)
+func (h *handler) ProductShopRouter(g *gin.RouterGroup) {
+	g.POST("", middleware.Authorize(constants.PERMISSION_PRODUCT_WRITE), h.AddProduct)
+	g.POST("/:product_id/transfer", middleware.Authorize(constants.PERMISSION_TRANSFER_WRITE), h.TransferProduct)
+	g.GET("/:product_id/detail", middleware.Authorize(constants.PERMISSION_PRODUCT_READ), h.DetailProduct)
 }
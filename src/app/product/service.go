@@ -3,53 +3,74 @@ package product
 import (
 	"context"
 	"errors"
-	"fmt"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 	"sync"
 	"test-edot/constants"
+	"test-edot/metrics"
+	"test-edot/src/app/audit"
+	"test-edot/src/app/inventory"
+	"test-edot/src/app/shop"
+	"test-edot/src/app/transfer"
 	"test-edot/src/dto"
 	"test-edot/src/factory"
 	"test-edot/src/models"
 	"test-edot/src/repository"
+	"test-edot/src/tracing"
 	"test-edot/util"
 	"time"
 )
 
+const queryWarehouseOwner = "id = ? and shop_id in (select shop_id from shop_members where user_id = ? and role in ?) and is_active = 1"
+
 type Service interface {
 	AddProduct(ctx context.Context, payload dto.PayloadAddProduct, userClaim dto.UserClaimJwt) error
 	ProductList(ctx context.Context, payload dto.ParameterQuery) (any, error)
-	TransferProductWarehouse(ctx context.Context, payload dto.TransferProductWarehouse, userClaim dto.UserClaimJwt, productId int) error
+	TransferProductWarehouse(ctx context.Context, payload dto.TransferProductWarehouse, userClaim dto.UserClaimJwt, productId int) (models.StockTransfer, error)
 	GetProductDetail(ctx context.Context, userClaim dto.UserClaimJwt, productId int) (any, error)
 }
 
 type service struct {
 	Log                  *zap.Logger
 	UserRepository       repository.UserRepositoryInterface
-	ShopRepository       repository.ShopRepositoryInterface
+	ShopService          shop.Service
 	ProductRepository    repository.ProductRepositoryInterface
 	StockLevelRepository repository.StockLevelRepositoryInterface
 	WarehouseRepository  repository.WarehouseRepositoryInterface
+	InventoryService     inventory.Service
+	TransferService      transfer.Service
+	AuditService         audit.Service
 }
 
 func NewService(f *factory.Factory) Service {
 	return &service{
 		Log:                  f.Log,
 		UserRepository:       f.UserRepository,
-		ShopRepository:       f.ShopRepository,
+		ShopService:          shop.NewService(f),
 		ProductRepository:    f.ProductRepository,
 		StockLevelRepository: f.StockLevelRepository,
 		WarehouseRepository:  f.WarehouseRepository,
+		InventoryService:     inventory.NewService(f),
+		TransferService:      transfer.NewService(f),
+		AuditService:         audit.NewService(f),
 	}
 }
 
-func (s *service) TransferProductWarehouse(ctx context.Context, payload dto.TransferProductWarehouse, userClaim dto.UserClaimJwt, productId int) error {
+// log is the logger of the request that made the call, see middleware.RequestId
+func (s *service) log(ctx context.Context) *zap.Logger {
+	return util.Logger(ctx, s.Log)
+}
+
+func (s *service) TransferProductWarehouse(ctx context.Context, payload dto.TransferProductWarehouse, userClaim dto.UserClaimJwt, productId int) (models.StockTransfer, error) {
+	ctx, span := tracing.Start(ctx, "product.TransferProductWarehouse")
+	defer span.End()
+
 	initialData, err := s.SetupProcessTransferProduct(ctx, userClaim, payload, productId)
 	if err != nil {
-		return err
+		return models.StockTransfer{}, err
 	}
 
-	tx := s.ProductRepository.Begin()
+	tx := s.ProductRepository.Begin().WithContext(ctx)
 	defer func() {
 		if r := recover(); r != nil {
 			tx.Rollback()
@@ -57,38 +78,52 @@ func (s *service) TransferProductWarehouse(ctx context.Context, payload dto.Tran
 	}()
 
 	if err := tx.Error; err != nil {
-		s.Log.Error("error begin transaction", zap.Error(err))
-		return err
+		s.log(ctx).Error("error begin transaction", zap.Error(err))
+		return models.StockTransfer{}, err
 	}
 
-	if err := s.ProcessTransferProduct(tx, initialData, payload); err != nil {
+	stockTransfer, err := s.ProcessTransferProduct(tx, userClaim, initialData, payload)
+	if err != nil {
 		tx.Rollback()
-		return err
+		return models.StockTransfer{}, err
 	}
 
 	if err := tx.Commit().Error; err != nil {
-		s.Log.Error("error commit transaction", zap.Error(err))
-		return err
+		s.log(ctx).Error("error commit transaction", zap.Error(err))
+		return models.StockTransfer{}, err
 	}
 
-	return nil
+	metrics.StockTransferTotal.WithLabelValues(constants.TRANSFER_STATUS_DISPATCHED).Inc()
+
+	s.AuditService.Record(ctx, userClaim, dto.AuditEntry{
+		ShopId:       initialData.FromWarehouse.ShopId,
+		Action:       constants.AUDIT_ACTION_STOCK_TRANSFER,
+		ResourceType: constants.AUDIT_RESOURCE_TRANSFER,
+		ResourceId:   stockTransfer.ID,
+		After:        map[string]any{"product_id": productId, "from_warehouse_id": stockTransfer.FromWarehouseId, "to_warehouse_id": stockTransfer.ToWarehouseId, "qty": payload.Qty},
+	})
+
+	return stockTransfer, nil
 }
 
 func (s *service) GetProductDetail(ctx context.Context, userClaim dto.UserClaimJwt, productId int) (any, error) {
+	ctx, span := tracing.Start(ctx, "product.GetProductDetail")
+	defer span.End()
+
 	var (
 		stock         int
 		reservedStock int
 	)
 
-	if userClaim.Role != constants.ROLE_ADMIN_SHOP {
-		return nil, constants.RoleUserInvalid
-	}
-
 	selectField := "id,name,sku,price,shop_id"
 	product, err := s.ProductRepository.GetProductDetail(ctx, selectField, "id = ?", productId)
 	if err != nil {
 		return nil, err
 	}
+
+	if _, err := s.ShopService.Authorize(ctx, userClaim, product.ShopId, constants.SHOP_PERMISSION_VIEW); err != nil {
+		return nil, err
+	}
 	for _, level := range product.Stock {
 		stock += level.Stock
 		reservedStock += level.ReservedStock
@@ -108,18 +143,24 @@ func (s *service) GetProductDetail(ctx context.Context, userClaim dto.UserClaimJ
 }
 
 func (s *service) ProductList(ctx context.Context, payload dto.ParameterQuery) (any, error) {
-	var query string
+	ctx, span := tracing.Start(ctx, "product.ProductList")
+	defer span.End()
+
+	// products of a closed shop are hidden from buyers
+	query := "shop_id in (select id from shops where is_active = 1)"
 	limit := 20
 	if payload.Limit > 0 {
 		limit = payload.Limit
 	}
 
+	var args []any
 	if payload.Search != "" {
-		query += fmt.Sprintf("name LIKE '%s'", "%"+payload.Search+"%")
+		query += " and name LIKE ?"
+		args = append(args, "%"+payload.Search+"%")
 	}
 
 	selectField := "id,name,sku,price,shop_id"
-	products, err := s.ProductRepository.GetProductDetails(ctx, payload.Offset, limit, selectField, query)
+	products, err := s.ProductRepository.GetProductDetails(ctx, payload.Offset, limit, selectField, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -145,14 +186,26 @@ func (s *service) ProductList(ctx context.Context, payload dto.ParameterQuery) (
 }
 
 func (s *service) AddProduct(ctx context.Context, payload dto.PayloadAddProduct, userClaim dto.UserClaimJwt) error {
+	ctx, span := tracing.Start(ctx, "product.AddProduct")
+	defer span.End()
+
 	if err := s.ValidateAddProduct(ctx, payload, userClaim); err != nil {
 		return err
 	}
 
-	if err := s.CreateProduct(payload, payload.ShopId); err != nil {
+	product, err := s.CreateProduct(ctx, payload, payload.ShopId)
+	if err != nil {
 		return err
 	}
 
+	s.AuditService.Record(ctx, userClaim, dto.AuditEntry{
+		ShopId:       product.ShopId,
+		Action:       constants.AUDIT_ACTION_PRODUCT_CREATE,
+		ResourceType: constants.AUDIT_RESOURCE_PRODUCT,
+		ResourceId:   product.Id,
+		After:        map[string]any{"product": product, "warehouse_id": payload.WarehouseId, "qty": payload.Qty},
+	})
+
 	return nil
 }
 
@@ -176,18 +229,11 @@ func (s *service) ValidateAddProduct(ctx context.Context, payload dto.PayloadAdd
 		case <-c.Done():
 			return
 		default:
-			res, err := s.ShopRepository.FindOne(ctx, "id", "user_id = ? and id = ?", userClaim.UserId, payload.ShopId)
-			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-				s.Log.Error("error get shop", zap.Error(err), zap.Any("payload", payload))
+			if _, err := s.ShopService.Authorize(ctx, userClaim, payload.ShopId, constants.SHOP_PERMISSION_PRODUCT_MANAGE); err != nil {
 				errChan <- err
 				return
 			}
 
-			if res == (models.Shop{}) {
-				errChan <- constants.ShopNotFound
-				return
-			}
-
 			return
 		}
 	}()
@@ -202,7 +248,7 @@ func (s *service) ValidateAddProduct(ctx context.Context, payload dto.PayloadAdd
 		default:
 			productData, err := s.ProductRepository.FindOne(ctx, "id", "sku = ? and shop_id = ?", payload.Sku, payload.ShopId)
 			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-				s.Log.Error("error get product", zap.Error(err), zap.Any("payload", payload))
+				s.log(ctx).Error("error get product", zap.Error(err), zap.Any("payload", payload))
 				errChan <- err
 				return
 			}
@@ -221,9 +267,9 @@ func (s *service) ValidateAddProduct(ctx context.Context, payload dto.PayloadAdd
 		case <-c.Done():
 			return
 		default:
-			warehouseData, err := s.WarehouseRepository.FindOne(ctx, "id", "user_id = ? and id = ?", userClaim.UserId, payload.WarehouseId)
+			warehouseData, err := s.WarehouseRepository.FindOne(ctx, "id", "id = ? and shop_id = ?", payload.WarehouseId, payload.ShopId)
 			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-				s.Log.Error("error get warehouse", zap.Error(err), zap.Any("payload", payload))
+				s.log(ctx).Error("error get warehouse", zap.Error(err), zap.Any("payload", payload))
 				errChan <- err
 				return
 			}
@@ -251,8 +297,8 @@ func (s *service) ValidateAddProduct(ctx context.Context, payload dto.PayloadAdd
 	return nil
 }
 
-func (s *service) CreateProduct(payload dto.PayloadAddProduct, shopId int) error {
-	tx := s.ProductRepository.Begin()
+func (s *service) CreateProduct(ctx context.Context, payload dto.PayloadAddProduct, shopId int) (models.Product, error) {
+	tx := s.ProductRepository.Begin().WithContext(ctx)
 	defer func() {
 		if r := recover(); r != nil {
 			tx.Rollback()
@@ -260,8 +306,8 @@ func (s *service) CreateProduct(payload dto.PayloadAddProduct, shopId int) error
 	}()
 
 	if err := tx.Error; err != nil {
-		s.Log.Error("error begin transaction", zap.Error(err))
-		return err
+		s.log(ctx).Error("error begin transaction", zap.Error(err))
+		return models.Product{}, err
 	}
 
 	product := models.Product{
@@ -274,8 +320,8 @@ func (s *service) CreateProduct(payload dto.PayloadAddProduct, shopId int) error
 	}
 	if err := s.ProductRepository.Create(tx, &product); err != nil {
 		tx.Rollback()
-		s.Log.Error("error insert product", zap.String("product", product.Name), zap.Error(err))
-		return err
+		s.log(ctx).Error("error insert product", zap.String("product", product.Name), zap.Error(err))
+		return models.Product{}, err
 	}
 
 	stockLevel := models.StockLevel{
@@ -289,21 +335,29 @@ func (s *service) CreateProduct(payload dto.PayloadAddProduct, shopId int) error
 
 	if err := s.StockLevelRepository.Create(tx, &stockLevel); err != nil {
 		tx.Rollback()
-		s.Log.Error("error insert stock level", zap.String("product", product.Name), zap.Error(err))
-		return err
+		s.log(ctx).Error("error insert stock level", zap.String("product", product.Name), zap.Error(err))
+		return models.Product{}, err
+	}
+
+	if err := s.InventoryService.CheckCapacityTx(tx, payload.WarehouseId); err != nil {
+		tx.Rollback()
+		return models.Product{}, err
 	}
 
 	if err := tx.Commit().Error; err != nil {
-		s.Log.Error("error commit transaction", zap.Error(err))
-		return err
+		s.log(ctx).Error("error commit transaction", zap.Error(err))
+		return models.Product{}, err
 	}
 
-	s.Log.Info("success insert product", zap.String("product", product.Name))
+	s.log(ctx).Info("success insert product", zap.String("product", product.Name))
 
-	return nil
+	return product, nil
 }
 
 func (s *service) SetupProcessTransferProduct(ctx context.Context, userClaim dto.UserClaimJwt, payload dto.TransferProductWarehouse, productId int) (dto.InitialTransferProduct, error) {
+	ctx, span := tracing.Start(ctx, "product.SetupProcessTransferProduct")
+	defer span.End()
+
 	var (
 		wg            sync.WaitGroup
 		product       models.Product
@@ -322,11 +376,14 @@ func (s *service) SetupProcessTransferProduct(ctx context.Context, userClaim dto
 	go func() {
 		defer wg.Done()
 
+		c, span := tracing.Start(c, "product.SetupProcessTransferProduct.product")
+		defer span.End()
+
 		select {
 		case <-c.Done():
 			return
 		default:
-			res, err := s.ProductRepository.FindOne(c, "id,name", "id = ?", productId)
+			res, err := s.ProductRepository.FindOne(c, "id,name,shop_id", "id = ?", productId)
 			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 				errChan <- err
 				return
@@ -343,11 +400,14 @@ func (s *service) SetupProcessTransferProduct(ctx context.Context, userClaim dto
 	go func() {
 		defer wg.Done()
 
+		c, span := tracing.Start(c, "product.SetupProcessTransferProduct.from_warehouse")
+		defer span.End()
+
 		select {
 		case <-c.Done():
 			return
 		default:
-			res, err := s.WarehouseRepository.FindOne(c, "id,name", "id = ? and user_id = ? and is_active = 1", payload.FromWarehouseId, userClaim.UserId)
+			res, err := s.WarehouseRepository.FindOne(c, "id,name,shop_id", queryWarehouseOwner, payload.FromWarehouseId, userClaim.UserId, constants.MapPermissionShopRole[constants.SHOP_PERMISSION_STOCK_ADJUST])
 			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 				errChan <- err
 				return
@@ -364,11 +424,14 @@ func (s *service) SetupProcessTransferProduct(ctx context.Context, userClaim dto
 	go func() {
 		defer wg.Done()
 
+		c, span := tracing.Start(c, "product.SetupProcessTransferProduct.to_warehouse")
+		defer span.End()
+
 		select {
 		case <-c.Done():
 			return
 		default:
-			res, err := s.WarehouseRepository.FindOne(c, "id,name", "id = ? and user_id = ? and is_active = 1", payload.ToWarehouseId, userClaim.UserId)
+			res, err := s.WarehouseRepository.FindOne(c, "id,name,shop_id", queryWarehouseOwner, payload.ToWarehouseId, userClaim.UserId, constants.MapPermissionShopRole[constants.SHOP_PERMISSION_STOCK_ADJUST])
 			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 				errChan <- err
 				return
@@ -395,6 +458,15 @@ func (s *service) SetupProcessTransferProduct(ctx context.Context, userClaim dto
 		return dto.InitialTransferProduct{}, err
 	}
 
+	// stock of a shop never leaves its own warehouses
+	if fromWarehouse.ShopId != toWarehouse.ShopId {
+		return dto.InitialTransferProduct{}, constants.WarehouseShopMismatch
+	}
+
+	if product.ShopId != fromWarehouse.ShopId {
+		return dto.InitialTransferProduct{}, constants.ProductShopMismatch
+	}
+
 	return dto.InitialTransferProduct{
 		Product:       product,
 		FromWarehouse: fromWarehouse,
@@ -402,58 +474,22 @@ func (s *service) SetupProcessTransferProduct(ctx context.Context, userClaim dto
 	}, nil
 }
 
-func (s *service) ProcessTransferProduct(tx *gorm.DB, initialData dto.InitialTransferProduct, payload dto.TransferProductWarehouse) error {
-	q := "product_id = ? and warehouse_id = ?"
-	stockFrom, err := s.StockLevelRepository.FindOneTx(tx, "updated_at asc", q, initialData.Product.Id, initialData.FromWarehouse.ID)
-	if err != nil {
-		s.Log.Error("error get stock", zap.String("product", initialData.Product.Name), zap.Error(err))
-		return err
-	}
-
-	if stockFrom.Stock < payload.Qty {
-		return constants.NotEnoughStockToTransfer
+// ProcessTransferProduct dispatches the qty from the source warehouse, the destination only gets the stock
+// once the transfer is received
+func (s *service) ProcessTransferProduct(tx *gorm.DB, userClaim dto.UserClaimJwt, initialData dto.InitialTransferProduct, payload dto.TransferProductWarehouse) (models.StockTransfer, error) {
+	stockTransfer := models.StockTransfer{
+		FromWarehouseId: initialData.FromWarehouse.ID,
+		ToWarehouseId:   initialData.ToWarehouse.ID,
+		UserId:          userClaim.UserId,
 	}
 
-	stockDest, err := s.StockLevelRepository.FindOneTx(tx, "updated_at asc", q, initialData.Product.Id, initialData.ToWarehouse.ID)
-	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-		s.Log.Error("error get stock", zap.String("product", initialData.Product.Name), zap.Error(err))
-		return err
+	items := []models.StockTransferItem{{ProductId: initialData.Product.Id, Qty: payload.Qty}}
+	if err := s.TransferService.DispatchTransferTx(tx, &stockTransfer, items); err != nil {
+		s.log(util.TxContext(tx)).Error("error dispatch product transfer", zap.String("product", initialData.Product.Name), zap.Error(err))
+		return models.StockTransfer{}, err
 	}
 
-	if stockDest == (models.StockLevelProduct{}) {
-		stockLevel := models.StockLevel{
-			ProductId:   initialData.Product.Id,
-			WarehouseId: initialData.ToWarehouse.ID,
-			Stock:       payload.Qty,
-			CreatedAt:   time.Now().In(util.LocationTime),
-			UpdatedAt:   time.Now().In(util.LocationTime),
-		}
-		if err := s.StockLevelRepository.Create(tx, &stockLevel); err != nil {
-			s.Log.Error("error creating stock level", zap.Error(err))
-			return err
-		}
-	} else {
-		updatedStockLevel := models.StockLevel{
-			Stock:     stockDest.Stock + payload.Qty,
-			UpdatedAt: time.Now().In(util.LocationTime),
-		}
-
-		if err := s.StockLevelRepository.UpdateOneTx(tx, &updatedStockLevel, "stock,updated_at", "warehouse_id = ? and product_id = ?", stockDest.WarehouseId, initialData.Product.Id); err != nil {
-			return err
-		}
-	}
-
-	updatedStockLevel := models.StockLevel{
-		Stock:     stockFrom.Stock - payload.Qty,
-		UpdatedAt: time.Now().In(util.LocationTime),
-	}
-
-	if err := s.StockLevelRepository.UpdateOneTx(tx, &updatedStockLevel, "stock,updated_at", "warehouse_id = ? and product_id = ?", stockFrom.WarehouseId, initialData.Product.Id); err != nil {
-		return err
-	}
-
-	s.Log.Info("success move product stock to another warehouse")
-
-	return nil
+	s.log(util.TxContext(tx)).Info("success dispatch product stock to another warehouse", zap.String("transferNo", stockTransfer.TransferNo))
 
+	return stockTransfer, nil
 }
@@ -0,0 +1,179 @@
+package purchase
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"test-edot/src/dto"
+	"test-edot/src/factory"
+)
+
+type handler struct {
+	service Service
+}
+
+func NewHandler(f *factory.Factory) *handler {
+	return &handler{
+		service: NewService(f),
+	}
+}
+
+func (h *handler) CreatePurchaseOrder(g *gin.Context) {
+	userClaim := g.Value("userClaim").(dto.UserClaimJwt)
+
+	var payload dto.PayloadCreatePurchaseOrder
+	if err := g.ShouldBindJSON(&payload); err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	res, err := h.service.CreatePurchaseOrder(g, userClaim, payload)
+	if err != nil {
+		g.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	g.JSON(http.StatusCreated, dto.Response{
+		Message: "success create purchase order",
+		Data:    res,
+	})
+	return
+}
+
+func (h *handler) GetPurchaseOrders(g *gin.Context) {
+	userClaim := g.Value("userClaim").(dto.UserClaimJwt)
+
+	var payload dto.ParameterQueryPurchaseOrder
+	if err := g.ShouldBind(&payload); err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	res, err := h.service.GetPurchaseOrders(g, userClaim, payload)
+	if err != nil {
+		g.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	g.JSON(http.StatusOK, dto.Response{
+		Message: "success list purchase order",
+		Data:    res,
+	})
+	return
+}
+
+func (h *handler) GetPurchaseOrder(g *gin.Context) {
+	userClaim := g.Value("userClaim").(dto.UserClaimJwt)
+
+	purchaseOrderId, err := strconv.Atoi(g.Param("purchase_order_id"))
+	if err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: "purchase_order_id is not valid",
+		})
+		return
+	}
+
+	res, err := h.service.GetPurchaseOrder(g, userClaim, purchaseOrderId)
+	if err != nil {
+		g.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	g.JSON(http.StatusOK, dto.Response{
+		Message: "success fetch purchase order",
+		Data:    res,
+	})
+	return
+}
+
+func (h *handler) SendPurchaseOrder(g *gin.Context) {
+	userClaim := g.Value("userClaim").(dto.UserClaimJwt)
+
+	purchaseOrderId, err := strconv.Atoi(g.Param("purchase_order_id"))
+	if err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: "purchase_order_id is not valid",
+		})
+		return
+	}
+
+	if err := h.service.SendPurchaseOrder(g, userClaim, purchaseOrderId); err != nil {
+		g.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	g.JSON(http.StatusOK, dto.Response{
+		Message: "success send purchase order",
+	})
+	return
+}
+
+func (h *handler) ReceivePurchaseOrder(g *gin.Context) {
+	userClaim := g.Value("userClaim").(dto.UserClaimJwt)
+
+	purchaseOrderId, err := strconv.Atoi(g.Param("purchase_order_id"))
+	if err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: "purchase_order_id is not valid",
+		})
+		return
+	}
+
+	var payload dto.PayloadReceivePurchaseOrder
+	if err := g.ShouldBindJSON(&payload); err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	res, err := h.service.ReceivePurchaseOrder(g, userClaim, purchaseOrderId, payload)
+	if err != nil {
+		g.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	g.JSON(http.StatusOK, dto.Response{
+		Message: "success receive purchase order",
+		Data:    res,
+	})
+	return
+}
+
+func (h *handler) ClosePurchaseOrder(g *gin.Context) {
+	userClaim := g.Value("userClaim").(dto.UserClaimJwt)
+
+	purchaseOrderId, err := strconv.Atoi(g.Param("purchase_order_id"))
+	if err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: "purchase_order_id is not valid",
+		})
+		return
+	}
+
+	if err := h.service.ClosePurchaseOrder(g, userClaim, purchaseOrderId); err != nil {
+		g.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	g.JSON(http.StatusOK, dto.Response{
+		Message: "success close purchase order",
+	})
+	return
+}
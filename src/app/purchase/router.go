@@ -0,0 +1,16 @@
+package purchase
+
+import (
+	"github.com/gin-gonic/gin"
+	"test-edot/constants"
+	"test-edot/src/middleware"
+)
+
+func (h *handler) PurchaseOrderRouter(g *gin.RouterGroup) {
+	g.POST("", middleware.Authorize(constants.PERMISSION_PURCHASE_WRITE), h.CreatePurchaseOrder)
+	g.GET("", middleware.Authorize(constants.PERMISSION_PURCHASE_READ), h.GetPurchaseOrders)
+	g.GET("/:purchase_order_id", middleware.Authorize(constants.PERMISSION_PURCHASE_READ), h.GetPurchaseOrder)
+	g.PUT("/:purchase_order_id/send", middleware.Authorize(constants.PERMISSION_PURCHASE_WRITE), h.SendPurchaseOrder)
+	g.POST("/:purchase_order_id/receive", middleware.Authorize(constants.PERMISSION_PURCHASE_WRITE), h.ReceivePurchaseOrder)
+	g.PUT("/:purchase_order_id/close", middleware.Authorize(constants.PERMISSION_PURCHASE_WRITE), h.ClosePurchaseOrder)
+}
@@ -0,0 +1,425 @@
+package purchase
+
+import (
+	"context"
+	"errors"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"test-edot/constants"
+	"test-edot/src/app/inventory"
+	"test-edot/src/app/shop"
+	"test-edot/src/dto"
+	"test-edot/src/factory"
+	"test-edot/src/models"
+	"test-edot/src/repository"
+	"test-edot/src/tracing"
+	"test-edot/util"
+	"time"
+)
+
+type Service interface {
+	CreatePurchaseOrder(ctx context.Context, userClaim dto.UserClaimJwt, payload dto.PayloadCreatePurchaseOrder) (models.PurchaseOrderDetail, error)
+	GetPurchaseOrders(ctx context.Context, userClaim dto.UserClaimJwt, payload dto.ParameterQueryPurchaseOrder) ([]models.PurchaseOrder, error)
+	GetPurchaseOrder(ctx context.Context, userClaim dto.UserClaimJwt, purchaseOrderId int) (models.PurchaseOrderDetail, error)
+	SendPurchaseOrder(ctx context.Context, userClaim dto.UserClaimJwt, purchaseOrderId int) error
+	ReceivePurchaseOrder(ctx context.Context, userClaim dto.UserClaimJwt, purchaseOrderId int, payload dto.PayloadReceivePurchaseOrder) (models.PurchaseOrderDetail, error)
+	ClosePurchaseOrder(ctx context.Context, userClaim dto.UserClaimJwt, purchaseOrderId int) error
+}
+
+type service struct {
+	Log                         *zap.Logger
+	ShopService                 shop.Service
+	ProductRepository           repository.ProductRepositoryInterface
+	WarehouseRepository         repository.WarehouseRepositoryInterface
+	SupplierRepository          repository.SupplierRepositoryInterface
+	PurchaseOrderRepository     repository.PurchaseOrderRepositoryInterface
+	PurchaseOrderItemRepository repository.PurchaseOrderItemRepositoryInterface
+	InventoryService            inventory.Service
+}
+
+func NewService(f *factory.Factory) Service {
+	return &service{
+		Log:                         f.Log,
+		ShopService:                 shop.NewService(f),
+		ProductRepository:           f.ProductRepository,
+		WarehouseRepository:         f.WarehouseRepository,
+		SupplierRepository:          f.SupplierRepository,
+		PurchaseOrderRepository:     f.PurchaseOrderRepository,
+		PurchaseOrderItemRepository: f.PurchaseOrderItemRepository,
+		InventoryService:            inventory.NewService(f),
+	}
+}
+
+// log is the logger of the request that made the call, see middleware.RequestId
+func (s *service) log(ctx context.Context) *zap.Logger {
+	return util.Logger(ctx, s.Log)
+}
+
+const (
+	queryShopPurchaseOrders = "shop_id in (select shop_id from shop_members where user_id = ? and role in ?)"
+	queryPurchaseOrderOwner = "id = ? and " + queryShopPurchaseOrders
+)
+
+func (s *service) CreatePurchaseOrder(ctx context.Context, userClaim dto.UserClaimJwt, payload dto.PayloadCreatePurchaseOrder) (models.PurchaseOrderDetail, error) {
+	ctx, span := tracing.Start(ctx, "purchase.CreatePurchaseOrder")
+	defer span.End()
+
+	if err := s.ValidateCreatePurchaseOrder(ctx, userClaim, payload); err != nil {
+		return models.PurchaseOrderDetail{}, err
+	}
+
+	tx := s.PurchaseOrderRepository.Begin().WithContext(ctx)
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		s.log(ctx).Error("error begin transaction", zap.Error(err))
+		return models.PurchaseOrderDetail{}, err
+	}
+
+	now := time.Now().In(util.LocationTime)
+	purchaseOrder := models.PurchaseOrder{
+		PoNo:        util.CreatePurchaseOrderNo(),
+		ShopId:      payload.ShopId,
+		SupplierId:  payload.SupplierId,
+		WarehouseId: payload.WarehouseId,
+		UserId:      userClaim.UserId,
+		Status:      constants.PO_STATUS_DRAFT,
+		Note:        payload.Note,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	for _, item := range payload.Items {
+		purchaseOrder.Total += item.Price * float64(item.Qty)
+	}
+
+	if err := s.PurchaseOrderRepository.Create(tx, &purchaseOrder); err != nil {
+		tx.Rollback()
+		s.log(ctx).Error("error creating purchase order", zap.Error(err))
+		return models.PurchaseOrderDetail{}, err
+	}
+
+	for _, item := range payload.Items {
+		purchaseOrderItem := models.PurchaseOrderItem{
+			PurchaseOrderId: purchaseOrder.ID,
+			ProductId:       item.ProductId,
+			Qty:             item.Qty,
+			Price:           item.Price,
+			CreatedAt:       now,
+			UpdatedAt:       now,
+		}
+
+		if err := s.PurchaseOrderItemRepository.Create(tx, &purchaseOrderItem); err != nil {
+			tx.Rollback()
+			s.log(ctx).Error("error creating purchase order item", zap.Error(err))
+			return models.PurchaseOrderDetail{}, err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		s.log(ctx).Error("error commit transaction", zap.Error(err))
+		return models.PurchaseOrderDetail{}, err
+	}
+
+	s.log(ctx).Info("success create purchase order", zap.String("poNo", purchaseOrder.PoNo))
+
+	return s.GetPurchaseOrder(ctx, userClaim, purchaseOrder.ID)
+}
+
+func (s *service) ValidateCreatePurchaseOrder(ctx context.Context, userClaim dto.UserClaimJwt, payload dto.PayloadCreatePurchaseOrder) error {
+	if len(payload.Items) == 0 {
+		return constants.PurchaseOrderItemEmpty
+	}
+
+	if _, err := s.ShopService.Authorize(ctx, userClaim, payload.ShopId, constants.SHOP_PERMISSION_PURCHASE_MANAGE); err != nil {
+		return err
+	}
+
+	if _, err := s.SupplierRepository.FindOne(ctx, "id", "id = ? and shop_id = ? and is_active = 1", payload.SupplierId, payload.ShopId); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return constants.SupplierNotFound
+		}
+
+		s.log(ctx).Error("error get supplier", zap.Error(err), zap.Any("payload", payload))
+		return err
+	}
+
+	if _, err := s.WarehouseRepository.FindOne(ctx, "id", "id = ? and shop_id = ? and is_active = 1", payload.WarehouseId, payload.ShopId); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return constants.WarehouseNotFound
+		}
+
+		s.log(ctx).Error("error get warehouse", zap.Error(err), zap.Any("payload", payload))
+		return err
+	}
+
+	mapProductId := make(map[int]bool)
+	for _, item := range payload.Items {
+		if item.Qty <= 0 {
+			return constants.QtyMustPositive
+		}
+
+		if mapProductId[item.ProductId] {
+			return constants.DuplicateProduct
+		}
+
+		if _, err := s.ProductRepository.FindOne(ctx, "id", "id = ? and shop_id = ?", item.ProductId, payload.ShopId); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return constants.ProductNotFound
+			}
+
+			s.log(ctx).Error("error get product", zap.Error(err), zap.Any("payload", payload))
+			return err
+		}
+
+		mapProductId[item.ProductId] = true
+	}
+
+	return nil
+}
+
+func (s *service) GetPurchaseOrders(ctx context.Context, userClaim dto.UserClaimJwt, payload dto.ParameterQueryPurchaseOrder) ([]models.PurchaseOrder, error) {
+	ctx, span := tracing.Start(ctx, "purchase.GetPurchaseOrders")
+	defer span.End()
+
+	q := queryShopPurchaseOrders
+	args := []any{userClaim.UserId, constants.MapPermissionShopRole[constants.SHOP_PERMISSION_VIEW]}
+
+	if payload.ShopId != 0 {
+		q += " and shop_id = ?"
+		args = append(args, payload.ShopId)
+	}
+
+	if payload.SupplierId != 0 {
+		q += " and supplier_id = ?"
+		args = append(args, payload.SupplierId)
+	}
+
+	if payload.WarehouseId != 0 {
+		q += " and warehouse_id = ?"
+		args = append(args, payload.WarehouseId)
+	}
+
+	if payload.Status != "" {
+		q += " and status = ?"
+		args = append(args, payload.Status)
+	}
+
+	purchaseOrders, err := s.PurchaseOrderRepository.Find(ctx, "*", q, args...)
+	if err != nil {
+		s.log(ctx).Error("error fetch purchase orders", zap.Error(err), zap.Int("user_id", userClaim.UserId))
+		return nil, err
+	}
+
+	return purchaseOrders, nil
+}
+
+func (s *service) GetPurchaseOrder(ctx context.Context, userClaim dto.UserClaimJwt, purchaseOrderId int) (models.PurchaseOrderDetail, error) {
+	ctx, span := tracing.Start(ctx, "purchase.GetPurchaseOrder")
+	defer span.End()
+
+	purchaseOrder, err := s.PurchaseOrderRepository.GetDetail(ctx, queryPurchaseOrderOwner, purchaseOrderId, userClaim.UserId, constants.MapPermissionShopRole[constants.SHOP_PERMISSION_VIEW])
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.PurchaseOrderDetail{}, constants.PurchaseOrderNotFound
+		}
+
+		s.log(ctx).Error("error fetch purchase order", zap.Error(err), zap.Int("purchaseOrderId", purchaseOrderId))
+		return models.PurchaseOrderDetail{}, err
+	}
+
+	return purchaseOrder, nil
+}
+
+func (s *service) SendPurchaseOrder(ctx context.Context, userClaim dto.UserClaimJwt, purchaseOrderId int) error {
+	ctx, span := tracing.Start(ctx, "purchase.SendPurchaseOrder")
+	defer span.End()
+
+	return s.ChangeStatusPurchaseOrder(ctx, userClaim, purchaseOrderId, constants.PO_STATUS_SENT)
+}
+
+func (s *service) ClosePurchaseOrder(ctx context.Context, userClaim dto.UserClaimJwt, purchaseOrderId int) error {
+	ctx, span := tracing.Start(ctx, "purchase.ClosePurchaseOrder")
+	defer span.End()
+
+	return s.ChangeStatusPurchaseOrder(ctx, userClaim, purchaseOrderId, constants.PO_STATUS_CLOSED)
+}
+
+func (s *service) ChangeStatusPurchaseOrder(ctx context.Context, userClaim dto.UserClaimJwt, purchaseOrderId int, status string) error {
+	tx := s.PurchaseOrderRepository.Begin().WithContext(ctx)
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		s.log(ctx).Error("error begin transaction", zap.Error(err))
+		return err
+	}
+
+	purchaseOrder, err := s.PurchaseOrderRepository.FindOneTx(tx, "id,status", queryPurchaseOrderOwner, purchaseOrderId, userClaim.UserId, constants.MapPermissionShopRole[constants.SHOP_PERMISSION_PURCHASE_MANAGE])
+	if err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return constants.PurchaseOrderNotFound
+		}
+
+		s.log(ctx).Error("error get purchase order", zap.Error(err))
+		return err
+	}
+
+	if err := s.UpdateStatusPurchaseOrder(tx, purchaseOrder, status); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		s.log(ctx).Error("error commit transaction", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) UpdateStatusPurchaseOrder(tx *gorm.DB, purchaseOrder models.PurchaseOrder, status string) error {
+	if !constants.MapPoStatusNext[purchaseOrder.Status][status] {
+		return constants.PurchaseOrderStatusFlow
+	}
+
+	updatedField := models.PurchaseOrder{Status: status, UpdatedAt: time.Now().In(util.LocationTime)}
+	if err := s.PurchaseOrderRepository.UpdateOneTx(tx, &updatedField, "status,updated_at", "id = ?", purchaseOrder.ID); err != nil {
+		s.log(util.TxContext(tx)).Error("error update purchase order", zap.Error(err))
+		return err
+	}
+
+	s.log(util.TxContext(tx)).Info("purchase order status changed", zap.Int("purchaseOrderId", purchaseOrder.ID), zap.String("from", purchaseOrder.Status), zap.String("to", status))
+	return nil
+}
+
+func (s *service) ReceivePurchaseOrder(ctx context.Context, userClaim dto.UserClaimJwt, purchaseOrderId int, payload dto.PayloadReceivePurchaseOrder) (models.PurchaseOrderDetail, error) {
+	ctx, span := tracing.Start(ctx, "purchase.ReceivePurchaseOrder")
+	defer span.End()
+
+	if len(payload.Items) == 0 {
+		return models.PurchaseOrderDetail{}, constants.PurchaseOrderItemEmpty
+	}
+
+	tx := s.PurchaseOrderRepository.Begin().WithContext(ctx)
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		s.log(ctx).Error("error begin transaction", zap.Error(err))
+		return models.PurchaseOrderDetail{}, err
+	}
+
+	purchaseOrder, err := s.PurchaseOrderRepository.FindOneTx(tx, "id,status,warehouse_id", queryPurchaseOrderOwner, purchaseOrderId, userClaim.UserId, constants.MapPermissionShopRole[constants.SHOP_PERMISSION_STOCK_ADJUST])
+	if err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.PurchaseOrderDetail{}, constants.PurchaseOrderNotFound
+		}
+
+		s.log(ctx).Error("error get purchase order", zap.Error(err))
+		return models.PurchaseOrderDetail{}, err
+	}
+
+	status, err := s.ProcessReceivePurchaseOrder(tx, userClaim, purchaseOrder, payload)
+	if err != nil {
+		tx.Rollback()
+		return models.PurchaseOrderDetail{}, err
+	}
+
+	if err := s.InventoryService.CheckCapacityTx(tx, purchaseOrder.WarehouseId); err != nil {
+		tx.Rollback()
+		return models.PurchaseOrderDetail{}, err
+	}
+
+	if err := s.UpdateStatusPurchaseOrder(tx, purchaseOrder, status); err != nil {
+		tx.Rollback()
+		return models.PurchaseOrderDetail{}, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		s.log(ctx).Error("error commit transaction", zap.Error(err))
+		return models.PurchaseOrderDetail{}, err
+	}
+
+	return s.GetPurchaseOrder(ctx, userClaim, purchaseOrderId)
+}
+
+// ProcessReceivePurchaseOrder adds the received qty to the warehouse stock and returns the status the
+// purchase order has to move to afterwards
+func (s *service) ProcessReceivePurchaseOrder(tx *gorm.DB, userClaim dto.UserClaimJwt, purchaseOrder models.PurchaseOrder, payload dto.PayloadReceivePurchaseOrder) (string, error) {
+	if !constants.MapPoStatusNext[purchaseOrder.Status][constants.PO_STATUS_PARTIALLY_RECEIVED] {
+		return "", constants.PurchaseOrderStatusFlow
+	}
+
+	items, err := s.PurchaseOrderItemRepository.FindTx(tx, "*", "purchase_order_id = ?", purchaseOrder.ID)
+	if err != nil {
+		s.log(util.TxContext(tx)).Error("error get purchase order items", zap.Error(err))
+		return "", err
+	}
+
+	mapItem := make(map[int]*models.PurchaseOrderItem)
+	for i := range items {
+		mapItem[items[i].ID] = &items[i]
+	}
+
+	now := time.Now().In(util.LocationTime)
+	for _, receive := range payload.Items {
+		item, ok := mapItem[receive.ItemId]
+		if !ok {
+			return "", constants.PurchaseOrderItemInvalid
+		}
+
+		if receive.Qty <= 0 {
+			return "", constants.QtyMustPositive
+		}
+
+		if item.ReceivedQty+receive.Qty > item.Qty {
+			return "", constants.ReceiveQtyExceeded
+		}
+
+		stockId, err := s.InventoryService.AddStockTx(tx, purchaseOrder.WarehouseId, item.ProductId, receive.Qty)
+		if err != nil {
+			return "", err
+		}
+
+		item.ReceivedQty += receive.Qty
+		updatedItem := models.PurchaseOrderItem{ReceivedQty: item.ReceivedQty, UpdatedAt: now}
+		if err := s.PurchaseOrderItemRepository.UpdateOneTx(tx, &updatedItem, "received_qty,updated_at", "id = ?", item.ID); err != nil {
+			s.log(util.TxContext(tx)).Error("error update purchase order item", zap.Error(err))
+			return "", err
+		}
+
+		receipt := models.PurchaseOrderReceipt{
+			PurchaseOrderId:     purchaseOrder.ID,
+			PurchaseOrderItemId: item.ID,
+			StockId:             stockId,
+			Qty:                 receive.Qty,
+			UserId:              userClaim.UserId,
+			CreatedAt:           now,
+		}
+		if err := s.PurchaseOrderItemRepository.CreateReceipt(tx, &receipt); err != nil {
+			s.log(util.TxContext(tx)).Error("error creating purchase order receipt", zap.Error(err))
+			return "", err
+		}
+	}
+
+	for _, item := range items {
+		if item.ReceivedQty < item.Qty {
+			return constants.PO_STATUS_PARTIALLY_RECEIVED, nil
+		}
+	}
+
+	return constants.PO_STATUS_RECEIVED, nil
+}
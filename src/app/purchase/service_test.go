@@ -0,0 +1,98 @@
+package purchase
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"test-edot/constants"
+	"test-edot/src/app/inventory"
+	"test-edot/src/dto"
+	"test-edot/src/factory"
+	"test-edot/src/models"
+	"test-edot/src/repository/mocks"
+	"testing"
+)
+
+type (
+	TestProcessReceiveData struct {
+		name         string
+		status       string
+		items        []models.PurchaseOrderItem
+		payload      dto.PayloadReceivePurchaseOrder
+		expectStatus string
+		expectErr    error
+	}
+)
+
+func TestProcessReceivePurchaseOrder(t *testing.T) {
+
+	tableTests := []TestProcessReceiveData{
+		{
+			name:   "test partial receive",
+			status: constants.PO_STATUS_SENT,
+			items: []models.PurchaseOrderItem{
+				{ID: 1, ProductId: 1, Qty: 10},
+				{ID: 2, ProductId: 2, Qty: 5},
+			},
+			payload:      dto.PayloadReceivePurchaseOrder{Items: []dto.PayloadReceivePurchaseOrderItem{{ItemId: 1, Qty: 10}}},
+			expectStatus: constants.PO_STATUS_PARTIALLY_RECEIVED,
+		},
+		{
+			name:   "test receive remaining items",
+			status: constants.PO_STATUS_PARTIALLY_RECEIVED,
+			items: []models.PurchaseOrderItem{
+				{ID: 1, ProductId: 1, Qty: 10, ReceivedQty: 10},
+				{ID: 2, ProductId: 2, Qty: 5, ReceivedQty: 2},
+			},
+			payload:      dto.PayloadReceivePurchaseOrder{Items: []dto.PayloadReceivePurchaseOrderItem{{ItemId: 2, Qty: 3}}},
+			expectStatus: constants.PO_STATUS_RECEIVED,
+		},
+		{
+			name:   "test receive more than ordered",
+			status: constants.PO_STATUS_SENT,
+			items: []models.PurchaseOrderItem{
+				{ID: 1, ProductId: 1, Qty: 10, ReceivedQty: 8},
+			},
+			payload:   dto.PayloadReceivePurchaseOrder{Items: []dto.PayloadReceivePurchaseOrderItem{{ItemId: 1, Qty: 3}}},
+			expectErr: constants.ReceiveQtyExceeded,
+		},
+		{
+			name:   "test receive draft purchase order",
+			status: constants.PO_STATUS_DRAFT,
+			items: []models.PurchaseOrderItem{
+				{ID: 1, ProductId: 1, Qty: 10},
+			},
+			payload:   dto.PayloadReceivePurchaseOrder{Items: []dto.PayloadReceivePurchaseOrderItem{{ItemId: 1, Qty: 1}}},
+			expectErr: constants.PurchaseOrderStatusFlow,
+		},
+	}
+
+	for _, test := range tableTests {
+		t.Run(test.name, func(t *testing.T) {
+
+			tx := gorm.DB{}
+			mockItemRepo := new(mocks.PurchaseOrderItemRepositoryInterface)
+			mockStockRepo := new(mocks.StockLevelRepositoryInterface)
+
+			mockItemRepo.On("FindTx", &tx, "*", "purchase_order_id = ?", 1).Return(test.items, nil)
+			mockItemRepo.On("UpdateOneTx", &tx, mock.AnythingOfType("*models.PurchaseOrderItem"), mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			mockItemRepo.On("CreateReceipt", &tx, mock.AnythingOfType("*models.PurchaseOrderReceipt")).Return(nil)
+
+			mockStockRepo.On("FindOneTx", &tx, "id asc", mock.Anything, mock.Anything, mock.Anything).Return(models.StockLevelProduct{ID: 1, Stock: 1}, nil)
+			mockStockRepo.On("UpdateOneTx", &tx, mock.AnythingOfType("*models.StockLevel"), mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+			f := factory.Factory{Log: zap.NewNop(), StockLevelRepository: mockStockRepo}
+			s := service{Log: zap.NewNop(), PurchaseOrderItemRepository: mockItemRepo, InventoryService: inventory.NewService(&f)}
+
+			purchaseOrder := models.PurchaseOrder{ID: 1, Status: test.status, WarehouseId: 1}
+			status, err := s.ProcessReceivePurchaseOrder(&tx, dto.UserClaimJwt{UserId: 1}, purchaseOrder, test.payload)
+			if test.expectErr != nil {
+				assert.ErrorIs(t, err, test.expectErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expectStatus, status)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package shop
 import (
 	"github.com/gin-gonic/gin"
 	"net/http"
+	"strconv"
 	"test-edot/src/dto"
 	"test-edot/src/factory"
 )
@@ -41,3 +42,293 @@ func (h *handler) CreateShop(g *gin.Context) {
 	})
 	return
 }
+
+func (h *handler) AddMember(g *gin.Context) {
+	userClaim := g.Value("userClaim").(dto.UserClaimJwt)
+
+	shopId, err := strconv.Atoi(g.Param("shop_id"))
+	if err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: "shop_id is not valid",
+		})
+		return
+	}
+
+	var payload dto.PayloadAddShopMember
+	if err := g.ShouldBindJSON(&payload); err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	res, err := h.service.AddMember(g, userClaim, shopId, payload)
+	if err != nil {
+		g.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	g.JSON(http.StatusCreated, dto.Response{
+		Message: "success add shop member",
+		Data:    res,
+	})
+	return
+}
+
+func (h *handler) GetMembers(g *gin.Context) {
+	userClaim := g.Value("userClaim").(dto.UserClaimJwt)
+
+	shopId, err := strconv.Atoi(g.Param("shop_id"))
+	if err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: "shop_id is not valid",
+		})
+		return
+	}
+
+	res, err := h.service.GetMembers(g, userClaim, shopId)
+	if err != nil {
+		g.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	g.JSON(http.StatusOK, dto.Response{
+		Message: "success list shop member",
+		Data:    res,
+	})
+	return
+}
+
+func (h *handler) UpdateMember(g *gin.Context) {
+	userClaim := g.Value("userClaim").(dto.UserClaimJwt)
+
+	shopId, err := strconv.Atoi(g.Param("shop_id"))
+	if err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: "shop_id is not valid",
+		})
+		return
+	}
+
+	memberId, err := strconv.Atoi(g.Param("member_id"))
+	if err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: "member_id is not valid",
+		})
+		return
+	}
+
+	var payload dto.PayloadUpdateShopMember
+	if err := g.ShouldBindJSON(&payload); err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	if err := h.service.UpdateMember(g, userClaim, shopId, memberId, payload); err != nil {
+		g.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	g.JSON(http.StatusOK, dto.Response{
+		Message: "success update shop member",
+	})
+	return
+}
+
+func (h *handler) RemoveMember(g *gin.Context) {
+	userClaim := g.Value("userClaim").(dto.UserClaimJwt)
+
+	shopId, err := strconv.Atoi(g.Param("shop_id"))
+	if err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: "shop_id is not valid",
+		})
+		return
+	}
+
+	memberId, err := strconv.Atoi(g.Param("member_id"))
+	if err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: "member_id is not valid",
+		})
+		return
+	}
+
+	if err := h.service.RemoveMember(g, userClaim, shopId, memberId); err != nil {
+		g.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	g.JSON(http.StatusOK, dto.Response{
+		Message: "success remove shop member",
+	})
+	return
+}
+
+func (h *handler) GetShops(g *gin.Context) {
+	userClaim := g.Value("userClaim").(dto.UserClaimJwt)
+
+	res, err := h.service.GetShops(g, userClaim)
+	if err != nil {
+		g.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	g.JSON(http.StatusOK, dto.Response{
+		Message: "success list shop",
+		Data:    res,
+	})
+	return
+}
+
+func (h *handler) GetShop(g *gin.Context) {
+	userClaim := g.Value("userClaim").(dto.UserClaimJwt)
+
+	shopId, err := strconv.Atoi(g.Param("shop_id"))
+	if err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: "shop_id is not valid",
+		})
+		return
+	}
+
+	res, err := h.service.GetShop(g, userClaim, shopId)
+	if err != nil {
+		g.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	g.JSON(http.StatusOK, dto.Response{
+		Message: "success fetch shop",
+		Data:    res,
+	})
+	return
+}
+
+func (h *handler) UpdateShop(g *gin.Context) {
+	userClaim := g.Value("userClaim").(dto.UserClaimJwt)
+
+	shopId, err := strconv.Atoi(g.Param("shop_id"))
+	if err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: "shop_id is not valid",
+		})
+		return
+	}
+
+	var payload dto.PayloadUpdateShop
+	if err := g.ShouldBindJSON(&payload); err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	res, err := h.service.UpdateShop(g, userClaim, shopId, payload)
+	if err != nil {
+		g.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	g.JSON(http.StatusOK, dto.Response{
+		Message: "success update shop",
+		Data:    res,
+	})
+	return
+}
+
+func (h *handler) CloseShop(g *gin.Context) {
+	userClaim := g.Value("userClaim").(dto.UserClaimJwt)
+
+	shopId, err := strconv.Atoi(g.Param("shop_id"))
+	if err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: "shop_id is not valid",
+		})
+		return
+	}
+
+	if err := h.service.ChangeStatusShop(g, userClaim, shopId, false); err != nil {
+		g.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	g.JSON(http.StatusOK, dto.Response{
+		Message: "success close shop",
+	})
+	return
+}
+
+func (h *handler) ReopenShop(g *gin.Context) {
+	userClaim := g.Value("userClaim").(dto.UserClaimJwt)
+
+	shopId, err := strconv.Atoi(g.Param("shop_id"))
+	if err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: "shop_id is not valid",
+		})
+		return
+	}
+
+	if err := h.service.ChangeStatusShop(g, userClaim, shopId, true); err != nil {
+		g.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	g.JSON(http.StatusOK, dto.Response{
+		Message: "success reopen shop",
+	})
+	return
+}
+
+func (h *handler) GetShopProfile(g *gin.Context) {
+	shopId, err := strconv.Atoi(g.Param("shop_id"))
+	if err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: "shop_id is not valid",
+		})
+		return
+	}
+
+	var payload dto.ParameterQuery
+	if err := g.ShouldBind(&payload); err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	res, err := h.service.GetShopProfile(g, shopId, payload)
+	if err != nil {
+		g.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	g.JSON(http.StatusOK, dto.Response{
+		Message: "success fetch shop profile",
+		Data:    res,
+	})
+	return
+}
@@ -1,7 +1,24 @@
 package shop
 
-import "github.com/gin-gonic/gin"
+import (
+	"github.com/gin-gonic/gin"
+	"test-edot/constants"
+	"test-edot/src/middleware"
+)
+
+func (h *handler) ShopPublicRouter(g *gin.RouterGroup) {
+	g.GET("/:shop_id", h.GetShopProfile)
+}
 
 func (h *handler) ShopRouter(g *gin.RouterGroup) {
-	g.POST("", h.CreateShop)
+	g.POST("", middleware.Authorize(constants.PERMISSION_SHOP_WRITE), h.CreateShop)
+	g.GET("", middleware.Authorize(constants.PERMISSION_SHOP_READ), h.GetShops)
+	g.GET("/:shop_id/detail", middleware.Authorize(constants.PERMISSION_SHOP_READ), h.GetShop)
+	g.PUT("/:shop_id", middleware.Authorize(constants.PERMISSION_SHOP_WRITE), h.UpdateShop)
+	g.PUT("/:shop_id/close", middleware.Authorize(constants.PERMISSION_SHOP_WRITE), h.CloseShop)
+	g.PUT("/:shop_id/reopen", middleware.Authorize(constants.PERMISSION_SHOP_WRITE), h.ReopenShop)
+	g.POST("/:shop_id/members", middleware.Authorize(constants.PERMISSION_SHOP_WRITE), h.AddMember)
+	g.GET("/:shop_id/members", middleware.Authorize(constants.PERMISSION_SHOP_READ), h.GetMembers)
+	g.PUT("/:shop_id/members/:member_id", middleware.Authorize(constants.PERMISSION_SHOP_WRITE), h.UpdateMember)
+	g.DELETE("/:shop_id/members/:member_id", middleware.Authorize(constants.PERMISSION_SHOP_WRITE), h.RemoveMember)
 }
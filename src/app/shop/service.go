@@ -3,36 +3,64 @@ package shop
 import (
 	"context"
 	"errors"
+	"fmt"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 	"test-edot/constants"
+	"test-edot/src/app/audit"
 	"test-edot/src/dto"
 	"test-edot/src/factory"
 	"test-edot/src/models"
+	"test-edot/src/policy"
 	"test-edot/src/repository"
+	"test-edot/src/tracing"
 	"test-edot/util"
 	"time"
 )
 
 type Service interface {
 	CreateShop(ctx context.Context, user dto.UserClaimJwt, payload dto.PayloadCreateShop) (dto.ResponseCreateShop, error)
+	Authorize(ctx context.Context, userClaim dto.UserClaimJwt, shopId int, permission string) (models.ShopMember, error)
+	AddMember(ctx context.Context, userClaim dto.UserClaimJwt, shopId int, payload dto.PayloadAddShopMember) (models.ShopMember, error)
+	GetMembers(ctx context.Context, userClaim dto.UserClaimJwt, shopId int) ([]models.ShopMemberUser, error)
+	UpdateMember(ctx context.Context, userClaim dto.UserClaimJwt, shopId, memberId int, payload dto.PayloadUpdateShopMember) error
+	RemoveMember(ctx context.Context, userClaim dto.UserClaimJwt, shopId, memberId int) error
+	GetShops(ctx context.Context, userClaim dto.UserClaimJwt) ([]models.Shop, error)
+	GetShop(ctx context.Context, userClaim dto.UserClaimJwt, shopId int) (models.Shop, error)
+	UpdateShop(ctx context.Context, userClaim dto.UserClaimJwt, shopId int, payload dto.PayloadUpdateShop) (models.Shop, error)
+	ChangeStatusShop(ctx context.Context, userClaim dto.UserClaimJwt, shopId int, isActive bool) error
+	GetShopProfile(ctx context.Context, shopId int, payload dto.ParameterQuery) (dto.ResponseShopProfile, error)
 }
 
 type service struct {
-	Log            *zap.Logger
-	UserRepository repository.UserRepositoryInterface
-	ShopRepository repository.ShopRepositoryInterface
+	Log                  *zap.Logger
+	UserRepository       repository.UserRepositoryInterface
+	ShopRepository       repository.ShopRepositoryInterface
+	ShopMemberRepository repository.ShopMemberRepositoryInterface
+	ProductRepository    repository.ProductRepositoryInterface
+	AuditService         audit.Service
 }
 
 func NewService(f *factory.Factory) Service {
 	return &service{
-		Log:            f.Log,
-		UserRepository: f.UserRepository,
-		ShopRepository: f.ShopRepository,
+		Log:                  f.Log,
+		UserRepository:       f.UserRepository,
+		ShopRepository:       f.ShopRepository,
+		ShopMemberRepository: f.ShopMemberRepository,
+		ProductRepository:    f.ProductRepository,
+		AuditService:         audit.NewService(f),
 	}
 }
 
+// log is the logger of the request that made the call, see middleware.RequestId
+func (s *service) log(ctx context.Context) *zap.Logger {
+	return util.Logger(ctx, s.Log)
+}
+
 func (s *service) CreateShop(ctx context.Context, user dto.UserClaimJwt, payload dto.PayloadCreateShop) (dto.ResponseCreateShop, error) {
+	ctx, span := tracing.Start(ctx, "shop.CreateShop")
+	defer span.End()
+
 	shop, err := s.ShopRepository.FindOne(ctx, "name", "name = ? and user_id = ?", payload.Name, user.UserId)
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		return dto.ResponseCreateShop{}, err
@@ -42,19 +70,59 @@ func (s *service) CreateShop(ctx context.Context, user dto.UserClaimJwt, payload
 		return dto.ResponseCreateShop{}, constants.ShopAlreadyInserted
 	}
 
+	tx := s.ShopRepository.Begin().WithContext(ctx)
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		s.log(ctx).Error("error begin transaction", zap.Error(err))
+		return dto.ResponseCreateShop{}, err
+	}
+
 	shopData := models.Shop{
 		Name:      payload.Name,
 		Location:  payload.Location,
+		IsActive:  true,
 		UserId:    user.UserId,
 		CreatedAt: time.Now().In(util.LocationTime),
 		UpdatedAt: time.Now().In(util.LocationTime),
 	}
 
-	if err := s.ShopRepository.Create(ctx, &shopData); err != nil {
+	if err := s.ShopRepository.CreateTx(tx, &shopData); err != nil {
+		tx.Rollback()
 		return dto.ResponseCreateShop{}, err
 	}
 
-	s.Log.Info("shop created", zap.String("name", shopData.Name), zap.String("location", shopData.Location))
+	// the creator of the shop is always its first owner
+	owner := models.ShopMember{
+		ShopId:    shopData.ID,
+		UserId:    user.UserId,
+		Role:      constants.SHOP_ROLE_OWNER,
+		CreatedAt: shopData.CreatedAt,
+		UpdatedAt: shopData.UpdatedAt,
+	}
+	if err := s.ShopMemberRepository.CreateTx(tx, &owner); err != nil {
+		tx.Rollback()
+		s.log(ctx).Error("error creating shop member", zap.Error(err))
+		return dto.ResponseCreateShop{}, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		s.log(ctx).Error("error commit transaction", zap.Error(err))
+		return dto.ResponseCreateShop{}, err
+	}
+
+	s.log(ctx).Info("shop created", zap.String("name", shopData.Name), zap.String("location", shopData.Location))
+	s.AuditService.Record(ctx, user, dto.AuditEntry{
+		ShopId:       shopData.ID,
+		Action:       constants.AUDIT_ACTION_SHOP_CREATE,
+		ResourceType: constants.AUDIT_RESOURCE_SHOP,
+		ResourceId:   shopData.ID,
+		After:        shopData,
+	})
 
 	return dto.ResponseCreateShop{
 		Id:       shopData.ID,
@@ -62,3 +130,432 @@ func (s *service) CreateShop(ctx context.Context, user dto.UserClaimJwt, payload
 		Location: shopData.Name,
 	}, nil
 }
+
+// Authorize returns the membership of the user in the shop when its role is granted the permission,
+// an api key only reaches the shop it was created for
+func (s *service) Authorize(ctx context.Context, userClaim dto.UserClaimJwt, shopId int, permission string) (models.ShopMember, error) {
+	ctx, span := tracing.Start(ctx, "shop.Authorize")
+	defer span.End()
+
+	if userClaim.ShopId != 0 && userClaim.ShopId != shopId {
+		return models.ShopMember{}, constants.ShopNotFound
+	}
+
+	member, err := s.ShopMemberRepository.FindOne(ctx, "id,shop_id,user_id,role", "shop_id = ? and user_id = ?", shopId, userClaim.UserId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.ShopMember{}, constants.ShopNotFound
+		}
+
+		s.log(ctx).Error("error get shop member", zap.Error(err), zap.Int("shopId", shopId))
+		return models.ShopMember{}, err
+	}
+
+	if !HasPermission(member.Role, permission) {
+		return models.ShopMember{}, constants.ShopPermissionDenied
+	}
+
+	return member, nil
+}
+
+// MemberShops is the subquery of the shops where the user has a role granted the permission
+func MemberShops(userClaim dto.UserClaimJwt, permission string) (string, []any) {
+	query := "select shop_id from shop_members where user_id = ? and role in ?"
+	args := []any{userClaim.UserId, constants.MapPermissionShopRole[permission]}
+	if userClaim.ShopId != 0 {
+		query += " and shop_id = ?"
+		args = append(args, userClaim.ShopId)
+	}
+
+	return query, args
+}
+
+func HasPermission(role, permission string) bool {
+	for _, r := range constants.MapPermissionShopRole[permission] {
+		if r == role {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *service) AddMember(ctx context.Context, userClaim dto.UserClaimJwt, shopId int, payload dto.PayloadAddShopMember) (models.ShopMember, error) {
+	ctx, span := tracing.Start(ctx, "shop.AddMember")
+	defer span.End()
+
+	if !constants.MapShopRoleAvail[payload.Role] {
+		return models.ShopMember{}, constants.ShopRoleNotAvailable
+	}
+
+	if _, err := s.Authorize(ctx, userClaim, shopId, constants.SHOP_PERMISSION_MEMBER_MANAGE); err != nil {
+		return models.ShopMember{}, err
+	}
+
+	user, err := s.UserRepository.FindOne(ctx, "id,role", "email = ?", payload.Email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.ShopMember{}, constants.UserNotFound
+		}
+
+		s.log(ctx).Error("error get user", zap.Error(err))
+		return models.ShopMember{}, err
+	}
+
+	// members work through the back office routes, a platform role the policy keeps out of them gains nothing
+	if !policy.Allowed(user.Role, constants.PERMISSION_SHOP_READ) {
+		return models.ShopMember{}, constants.ShopMemberNoBackOffice
+	}
+
+	existed, err := s.ShopMemberRepository.FindOne(ctx, "id", "shop_id = ? and user_id = ?", shopId, user.Id)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		s.log(ctx).Error("error get shop member", zap.Error(err))
+		return models.ShopMember{}, err
+	}
+
+	if existed != (models.ShopMember{}) {
+		return models.ShopMember{}, constants.ShopMemberAlreadyExisted
+	}
+
+	member := models.ShopMember{
+		ShopId:    shopId,
+		UserId:    user.Id,
+		Role:      payload.Role,
+		CreatedAt: time.Now().In(util.LocationTime),
+		UpdatedAt: time.Now().In(util.LocationTime),
+	}
+
+	if err := s.ShopMemberRepository.Create(ctx, &member); err != nil {
+		s.log(ctx).Error("error creating shop member", zap.Error(err))
+		return models.ShopMember{}, err
+	}
+
+	s.log(ctx).Info("shop member added", zap.Int("shopId", shopId), zap.Int("userId", user.Id), zap.String("role", member.Role))
+	s.AuditService.Record(ctx, userClaim, dto.AuditEntry{
+		ShopId:       shopId,
+		Action:       constants.AUDIT_ACTION_MEMBER_ADD,
+		ResourceType: constants.AUDIT_RESOURCE_SHOP_MEMBER,
+		ResourceId:   member.ID,
+		After:        member,
+	})
+	return member, nil
+}
+
+func (s *service) GetMembers(ctx context.Context, userClaim dto.UserClaimJwt, shopId int) ([]models.ShopMemberUser, error) {
+	ctx, span := tracing.Start(ctx, "shop.GetMembers")
+	defer span.End()
+
+	if _, err := s.Authorize(ctx, userClaim, shopId, constants.SHOP_PERMISSION_VIEW); err != nil {
+		return []models.ShopMemberUser{}, err
+	}
+
+	members, err := s.ShopMemberRepository.FindUser(ctx, "shop_members.shop_id = ?", shopId)
+	if err != nil {
+		s.log(ctx).Error("error fetch shop members", zap.Error(err), zap.Int("shopId", shopId))
+		return []models.ShopMemberUser{}, err
+	}
+
+	return members, nil
+}
+
+func (s *service) UpdateMember(ctx context.Context, userClaim dto.UserClaimJwt, shopId, memberId int, payload dto.PayloadUpdateShopMember) error {
+	ctx, span := tracing.Start(ctx, "shop.UpdateMember")
+	defer span.End()
+
+	if !constants.MapShopRoleAvail[payload.Role] {
+		return constants.ShopRoleNotAvailable
+	}
+
+	member, err := s.FindMember(ctx, userClaim, shopId, memberId)
+	if err != nil {
+		return err
+	}
+
+	if member.Role == constants.SHOP_ROLE_OWNER && payload.Role != constants.SHOP_ROLE_OWNER {
+		if err := s.ValidateOtherOwner(ctx, member); err != nil {
+			return err
+		}
+	}
+
+	updatedMember := models.ShopMember{Role: payload.Role, UpdatedAt: time.Now().In(util.LocationTime)}
+	if err := s.ShopMemberRepository.Update(ctx, updatedMember, "role,updated_at", "id = ?", member.ID); err != nil {
+		s.log(ctx).Error("error update shop member", zap.Error(err))
+		return err
+	}
+
+	s.log(ctx).Info("shop member updated", zap.Int("shopId", shopId), zap.Int("memberId", memberId), zap.String("role", payload.Role))
+	s.AuditService.Record(ctx, userClaim, dto.AuditEntry{
+		ShopId:       shopId,
+		Action:       constants.AUDIT_ACTION_MEMBER_UPDATE,
+		ResourceType: constants.AUDIT_RESOURCE_SHOP_MEMBER,
+		ResourceId:   member.ID,
+		Before:       map[string]any{"role": member.Role},
+		After:        map[string]any{"role": payload.Role},
+	})
+	return nil
+}
+
+func (s *service) RemoveMember(ctx context.Context, userClaim dto.UserClaimJwt, shopId, memberId int) error {
+	ctx, span := tracing.Start(ctx, "shop.RemoveMember")
+	defer span.End()
+
+	member, err := s.FindMember(ctx, userClaim, shopId, memberId)
+	if err != nil {
+		return err
+	}
+
+	if member.Role == constants.SHOP_ROLE_OWNER {
+		if err := s.ValidateOtherOwner(ctx, member); err != nil {
+			return err
+		}
+	}
+
+	if err := s.ShopMemberRepository.Delete(ctx, "id = ?", member.ID); err != nil {
+		s.log(ctx).Error("error delete shop member", zap.Error(err))
+		return err
+	}
+
+	s.log(ctx).Info("shop member removed", zap.Int("shopId", shopId), zap.Int("memberId", memberId))
+	s.AuditService.Record(ctx, userClaim, dto.AuditEntry{
+		ShopId:       shopId,
+		Action:       constants.AUDIT_ACTION_MEMBER_REMOVE,
+		ResourceType: constants.AUDIT_RESOURCE_SHOP_MEMBER,
+		ResourceId:   member.ID,
+		Before:       member,
+	})
+	return nil
+}
+
+func (s *service) FindMember(ctx context.Context, userClaim dto.UserClaimJwt, shopId, memberId int) (models.ShopMember, error) {
+	if _, err := s.Authorize(ctx, userClaim, shopId, constants.SHOP_PERMISSION_MEMBER_MANAGE); err != nil {
+		return models.ShopMember{}, err
+	}
+
+	member, err := s.ShopMemberRepository.FindOne(ctx, "id,shop_id,user_id,role", "id = ? and shop_id = ?", memberId, shopId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.ShopMember{}, constants.ShopMemberNotFound
+		}
+
+		s.log(ctx).Error("error get shop member", zap.Error(err))
+		return models.ShopMember{}, err
+	}
+
+	return member, nil
+}
+
+// ValidateOtherOwner makes sure the shop keeps an owner after the member stops being one
+func (s *service) ValidateOtherOwner(ctx context.Context, member models.ShopMember) error {
+	owners, err := s.ShopMemberRepository.Find(ctx, "id", "shop_id = ? and role = ? and id <> ?", member.ShopId, constants.SHOP_ROLE_OWNER, member.ID)
+	if err != nil {
+		s.log(ctx).Error("error fetch shop owners", zap.Error(err))
+		return err
+	}
+
+	if len(owners) == 0 {
+		return constants.ShopLastOwner
+	}
+
+	return nil
+}
+
+const shopFields = "id,name,location,is_active,closed_at,user_id,created_at,updated_at"
+
+func (s *service) GetShops(ctx context.Context, userClaim dto.UserClaimJwt) ([]models.Shop, error) {
+	ctx, span := tracing.Start(ctx, "shop.GetShops")
+	defer span.End()
+
+	shops, err := s.ShopRepository.Find(ctx, shopFields, "id in (select shop_id from shop_members where user_id = ?)", userClaim.UserId)
+	if err != nil {
+		s.log(ctx).Error("error fetch shops", zap.Error(err), zap.Int("user_id", userClaim.UserId))
+		return nil, err
+	}
+
+	return shops, nil
+}
+
+func (s *service) GetShop(ctx context.Context, userClaim dto.UserClaimJwt, shopId int) (models.Shop, error) {
+	ctx, span := tracing.Start(ctx, "shop.GetShop")
+	defer span.End()
+
+	if _, err := s.Authorize(ctx, userClaim, shopId, constants.SHOP_PERMISSION_VIEW); err != nil {
+		return models.Shop{}, err
+	}
+
+	return s.FindShop(ctx, shopId)
+}
+
+func (s *service) FindShop(ctx context.Context, shopId int) (models.Shop, error) {
+	shop, err := s.ShopRepository.FindOne(ctx, shopFields, "id = ?", shopId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.Shop{}, constants.ShopNotFound
+		}
+
+		s.log(ctx).Error("error get shop", zap.Error(err), zap.Int("shopId", shopId))
+		return models.Shop{}, err
+	}
+
+	return shop, nil
+}
+
+func (s *service) UpdateShop(ctx context.Context, userClaim dto.UserClaimJwt, shopId int, payload dto.PayloadUpdateShop) (models.Shop, error) {
+	ctx, span := tracing.Start(ctx, "shop.UpdateShop")
+	defer span.End()
+
+	if payload.Name == nil && payload.Location == nil {
+		return models.Shop{}, constants.ShopUpdateEmpty
+	}
+
+	if _, err := s.Authorize(ctx, userClaim, shopId, constants.SHOP_PERMISSION_MANAGE); err != nil {
+		return models.Shop{}, err
+	}
+
+	shop, err := s.FindShop(ctx, shopId)
+	if err != nil {
+		return models.Shop{}, err
+	}
+
+	before := shop
+	if payload.Name != nil && *payload.Name != shop.Name {
+		existed, err := s.ShopRepository.FindOne(ctx, "id", "name = ? and user_id = ? and id <> ?", *payload.Name, shop.UserId, shop.ID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			s.log(ctx).Error("error finding shop", zap.Error(err))
+			return models.Shop{}, err
+		}
+
+		if existed != (models.Shop{}) {
+			return models.Shop{}, constants.ShopAlreadyInserted
+		}
+
+		shop.Name = *payload.Name
+	}
+
+	if payload.Location != nil {
+		shop.Location = *payload.Location
+	}
+
+	shop.UpdatedAt = time.Now().In(util.LocationTime)
+	if err := s.ShopRepository.Update(ctx, shop, "name,location,updated_at", "id = ?", shop.ID); err != nil {
+		s.log(ctx).Error("error update shop", zap.Error(err))
+		return models.Shop{}, err
+	}
+
+	s.log(ctx).Info("shop updated", zap.Int("shopId", shop.ID))
+	s.AuditService.Record(ctx, userClaim, dto.AuditEntry{
+		ShopId:       shop.ID,
+		Action:       constants.AUDIT_ACTION_SHOP_UPDATE,
+		ResourceType: constants.AUDIT_RESOURCE_SHOP,
+		ResourceId:   shop.ID,
+		Before:       before,
+		After:        shop,
+	})
+	return shop, nil
+}
+
+// ChangeStatusShop closes or reopens the shop, products of a closed shop are hidden from buyers
+func (s *service) ChangeStatusShop(ctx context.Context, userClaim dto.UserClaimJwt, shopId int, isActive bool) error {
+	ctx, span := tracing.Start(ctx, "shop.ChangeStatusShop")
+	defer span.End()
+
+	if _, err := s.Authorize(ctx, userClaim, shopId, constants.SHOP_PERMISSION_MANAGE); err != nil {
+		return err
+	}
+
+	shop, err := s.FindShop(ctx, shopId)
+	if err != nil {
+		return err
+	}
+
+	if shop.IsActive == isActive {
+		if isActive {
+			return constants.ShopAlreadyOpen
+		}
+
+		return constants.ShopAlreadyClosed
+	}
+
+	now := time.Now().In(util.LocationTime)
+	updatedShop := models.Shop{IsActive: isActive, UpdatedAt: now}
+	if !isActive {
+		updatedShop.ClosedAt = &now
+	}
+
+	if err := s.ShopRepository.Update(ctx, updatedShop, "is_active,closed_at,updated_at", "id = ?", shop.ID); err != nil {
+		s.log(ctx).Error("error update shop", zap.Error(err))
+		return err
+	}
+
+	s.log(ctx).Info("shop status changed", zap.Int("shopId", shop.ID), zap.Bool("isActive", isActive))
+
+	action := constants.AUDIT_ACTION_SHOP_CLOSE
+	if isActive {
+		action = constants.AUDIT_ACTION_SHOP_REOPEN
+	}
+	s.AuditService.Record(ctx, userClaim, dto.AuditEntry{
+		ShopId:       shop.ID,
+		Action:       action,
+		ResourceType: constants.AUDIT_RESOURCE_SHOP,
+		ResourceId:   shop.ID,
+		Before:       map[string]any{"is_active": shop.IsActive},
+		After:        map[string]any{"is_active": isActive},
+	})
+	return nil
+}
+
+// GetShopProfile is the public profile of an open shop with its products that still have stock
+func (s *service) GetShopProfile(ctx context.Context, shopId int, payload dto.ParameterQuery) (dto.ResponseShopProfile, error) {
+	ctx, span := tracing.Start(ctx, "shop.GetShopProfile")
+	defer span.End()
+
+	shop, err := s.FindShop(ctx, shopId)
+	if err != nil {
+		return dto.ResponseShopProfile{}, err
+	}
+
+	if !shop.IsActive {
+		return dto.ResponseShopProfile{}, constants.ShopClosed
+	}
+
+	limit := 20
+	if payload.Limit > 0 {
+		limit = payload.Limit
+	}
+
+	query := "shop_id = ? and id in (select product_id from stock_levels where stock > 0)"
+	args := []any{shop.ID}
+	if payload.Search != "" {
+		query += " and name like ?"
+		args = append(args, fmt.Sprintf("%%%s%%", payload.Search))
+	}
+
+	products, err := s.ProductRepository.GetProductDetails(ctx, payload.Offset, limit, "id,name,sku,price,shop_id", query, args...)
+	if err != nil {
+		s.log(ctx).Error("error fetch shop products", zap.Error(err), zap.Int("shopId", shop.ID))
+		return dto.ResponseShopProfile{}, err
+	}
+
+	response := dto.ResponseShopProfile{
+		Id:       shop.ID,
+		Name:     shop.Name,
+		Location: shop.Location,
+		Products: []dto.ProductResponse{},
+	}
+	for _, product := range products {
+		var stock int
+		for _, level := range product.Stock {
+			stock += level.Stock
+		}
+
+		response.Products = append(response.Products, dto.ProductResponse{
+			Id:    product.Id,
+			Name:  product.Name,
+			Price: product.Price,
+			Sku:   product.Sku,
+			Shop:  shop.Name,
+			Stock: stock,
+		})
+	}
+
+	return response, nil
+}
@@ -0,0 +1,275 @@
+package shop
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"test-edot/constants"
+	"test-edot/src/app/audit"
+	"test-edot/src/dto"
+	"test-edot/src/factory"
+	"test-edot/src/models"
+	"test-edot/src/repository/mocks"
+	"testing"
+)
+
+type (
+	TestAuthorizeData struct {
+		name        string
+		member      models.ShopMember
+		findErr     error
+		permission  string
+		claimShopId int
+		expectErr   error
+	}
+
+	TestChangeStatusShopData struct {
+		name      string
+		shop      models.Shop
+		isActive  bool
+		action    string
+		expectErr error
+	}
+
+	TestAddMemberData struct {
+		name      string
+		user      models.User
+		existed   models.ShopMember
+		expectErr error
+	}
+
+	TestRemoveMemberData struct {
+		name      string
+		member    models.ShopMember
+		owners    []models.ShopMember
+		expectErr error
+	}
+)
+
+func TestAuthorize(t *testing.T) {
+
+	tableTests := []TestAuthorizeData{
+		{
+			name:       "test owner manage member",
+			member:     models.ShopMember{ID: 1, ShopId: 1, UserId: 1, Role: constants.SHOP_ROLE_OWNER},
+			permission: constants.SHOP_PERMISSION_MEMBER_MANAGE,
+		},
+		{
+			name:       "test manager manage member",
+			member:     models.ShopMember{ID: 1, ShopId: 1, UserId: 1, Role: constants.SHOP_ROLE_MANAGER},
+			permission: constants.SHOP_PERMISSION_MEMBER_MANAGE,
+			expectErr:  constants.ShopPermissionDenied,
+		},
+		{
+			name:       "test warehouse staff adjust stock",
+			member:     models.ShopMember{ID: 1, ShopId: 1, UserId: 1, Role: constants.SHOP_ROLE_WAREHOUSE_STAFF},
+			permission: constants.SHOP_PERMISSION_STOCK_ADJUST,
+		},
+		{
+			name:       "test warehouse staff change product",
+			member:     models.ShopMember{ID: 1, ShopId: 1, UserId: 1, Role: constants.SHOP_ROLE_WAREHOUSE_STAFF},
+			permission: constants.SHOP_PERMISSION_PRODUCT_MANAGE,
+			expectErr:  constants.ShopPermissionDenied,
+		},
+		{
+			name:       "test user not a member",
+			findErr:    gorm.ErrRecordNotFound,
+			permission: constants.SHOP_PERMISSION_VIEW,
+			expectErr:  constants.ShopNotFound,
+		},
+		{
+			name:        "test api key of the shop",
+			member:      models.ShopMember{ID: 1, ShopId: 1, UserId: 1, Role: constants.SHOP_ROLE_OWNER},
+			permission:  constants.SHOP_PERMISSION_PRODUCT_MANAGE,
+			claimShopId: 1,
+		},
+		{
+			name:        "test api key of another shop",
+			member:      models.ShopMember{ID: 1, ShopId: 1, UserId: 1, Role: constants.SHOP_ROLE_OWNER},
+			permission:  constants.SHOP_PERMISSION_PRODUCT_MANAGE,
+			claimShopId: 2,
+			expectErr:   constants.ShopNotFound,
+		},
+	}
+
+	for _, test := range tableTests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			mockMemberRepo := new(mocks.ShopMemberRepositoryInterface)
+			mockMemberRepo.On("FindOne", ctx, mock.Anything, "shop_id = ? and user_id = ?", 1, 1).Return(test.member, test.findErr)
+
+			s := service{Log: zap.NewNop(), ShopMemberRepository: mockMemberRepo}
+
+			member, err := s.Authorize(ctx, dto.UserClaimJwt{UserId: 1, ShopId: test.claimShopId}, 1, test.permission)
+			if test.expectErr != nil {
+				assert.ErrorIs(t, err, test.expectErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.member.Role, member.Role)
+			}
+		})
+	}
+}
+
+func TestAddMember(t *testing.T) {
+
+	tableTests := []TestAddMemberData{
+		{
+			name: "test add shop admin",
+			user: models.User{Id: 2, Role: constants.ROLE_ADMIN_SHOP},
+		},
+		{
+			name:      "test add user without back office access",
+			user:      models.User{Id: 2, Role: constants.ROLE_USER},
+			expectErr: constants.ShopMemberNoBackOffice,
+		},
+		{
+			name:      "test add existing member",
+			user:      models.User{Id: 2, Role: constants.ROLE_ADMIN_SHOP},
+			existed:   models.ShopMember{ID: 3},
+			expectErr: constants.ShopMemberAlreadyExisted,
+		},
+	}
+
+	for _, test := range tableTests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			mockMemberRepo := new(mocks.ShopMemberRepositoryInterface)
+			mockUserRepo := new(mocks.UserRepositoryInterface)
+
+			mockMemberRepo.On("FindOne", ctx, mock.Anything, "shop_id = ? and user_id = ?", 1, 1).
+				Return(models.ShopMember{ID: 1, ShopId: 1, UserId: 1, Role: constants.SHOP_ROLE_OWNER}, nil)
+			mockMemberRepo.On("FindOne", ctx, "id", "shop_id = ? and user_id = ?", 1, 2).Return(test.existed, nil)
+			mockMemberRepo.On("Create", ctx, mock.AnythingOfType("*models.ShopMember")).Return(nil)
+			mockUserRepo.On("FindOne", ctx, "id,role", "email = ?", "staff@mail.com").Return(test.user, nil)
+
+			mockAuditRepo := new(mocks.AuditEventRepositoryInterface)
+			mockAuditRepo.On("Create", ctx, mock.Anything).Return(nil)
+			f := factory.Factory{Log: zap.NewNop(), AuditEventRepository: mockAuditRepo}
+
+			s := service{Log: zap.NewNop(), UserRepository: mockUserRepo, ShopMemberRepository: mockMemberRepo, AuditService: audit.NewService(&f)}
+
+			payload := dto.PayloadAddShopMember{Email: "staff@mail.com", Role: constants.SHOP_ROLE_WAREHOUSE_STAFF}
+			member, err := s.AddMember(ctx, dto.UserClaimJwt{UserId: 1}, 1, payload)
+			if test.expectErr != nil {
+				assert.ErrorIs(t, err, test.expectErr)
+				mockMemberRepo.AssertNotCalled(t, "Create", ctx, mock.Anything)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.user.Id, member.UserId)
+				assert.Equal(t, payload.Role, member.Role)
+			}
+		})
+	}
+}
+
+func TestRemoveMember(t *testing.T) {
+
+	tableTests := []TestRemoveMemberData{
+		{
+			name:   "test remove staff",
+			member: models.ShopMember{ID: 2, ShopId: 1, UserId: 2, Role: constants.SHOP_ROLE_WAREHOUSE_STAFF},
+		},
+		{
+			name:   "test remove owner with another owner",
+			member: models.ShopMember{ID: 2, ShopId: 1, UserId: 2, Role: constants.SHOP_ROLE_OWNER},
+			owners: []models.ShopMember{{ID: 1}},
+		},
+		{
+			name:      "test remove last owner",
+			member:    models.ShopMember{ID: 1, ShopId: 1, UserId: 1, Role: constants.SHOP_ROLE_OWNER},
+			owners:    []models.ShopMember{},
+			expectErr: constants.ShopLastOwner,
+		},
+	}
+
+	for _, test := range tableTests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			mockMemberRepo := new(mocks.ShopMemberRepositoryInterface)
+			mockMemberRepo.On("FindOne", ctx, mock.Anything, "shop_id = ? and user_id = ?", 1, 1).
+				Return(models.ShopMember{ID: 1, ShopId: 1, UserId: 1, Role: constants.SHOP_ROLE_OWNER}, nil)
+			mockMemberRepo.On("FindOne", ctx, mock.Anything, "id = ? and shop_id = ?", test.member.ID, 1).Return(test.member, nil)
+			mockMemberRepo.On("Find", ctx, "id", mock.Anything, 1, constants.SHOP_ROLE_OWNER, test.member.ID).Return(test.owners, nil)
+			mockMemberRepo.On("Delete", ctx, "id = ?", test.member.ID).Return(nil)
+
+			mockAuditRepo := new(mocks.AuditEventRepositoryInterface)
+			mockAuditRepo.On("Create", ctx, mock.Anything).Return(nil)
+			f := factory.Factory{Log: zap.NewNop(), AuditEventRepository: mockAuditRepo}
+
+			s := service{Log: zap.NewNop(), ShopMemberRepository: mockMemberRepo, AuditService: audit.NewService(&f)}
+
+			err := s.RemoveMember(ctx, dto.UserClaimJwt{UserId: 1}, 1, test.member.ID)
+			if test.expectErr != nil {
+				assert.ErrorIs(t, err, test.expectErr)
+				mockMemberRepo.AssertNotCalled(t, "Delete", ctx, "id = ?", test.member.ID)
+			} else {
+				assert.NoError(t, err)
+				mockMemberRepo.AssertCalled(t, "Delete", ctx, "id = ?", test.member.ID)
+			}
+		})
+	}
+}
+
+func TestChangeStatusShop(t *testing.T) {
+
+	tableTests := []TestChangeStatusShopData{
+		{
+			name:     "test close open shop",
+			shop:     models.Shop{ID: 1, IsActive: true},
+			isActive: false,
+			action:   constants.AUDIT_ACTION_SHOP_CLOSE,
+		},
+		{
+			name:     "test reopen closed shop",
+			shop:     models.Shop{ID: 1, IsActive: false},
+			isActive: true,
+			action:   constants.AUDIT_ACTION_SHOP_REOPEN,
+		},
+		{
+			name:      "test close closed shop",
+			shop:      models.Shop{ID: 1, IsActive: false},
+			isActive:  false,
+			expectErr: constants.ShopAlreadyClosed,
+		},
+	}
+
+	for _, test := range tableTests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				updated models.Shop
+				event   models.AuditEvent
+			)
+			ctx := context.Background()
+			mockMemberRepo := new(mocks.ShopMemberRepositoryInterface)
+			mockShopRepo := new(mocks.ShopRepositoryInterface)
+
+			mockMemberRepo.On("FindOne", ctx, mock.Anything, "shop_id = ? and user_id = ?", 1, 1).
+				Return(models.ShopMember{ID: 1, ShopId: 1, UserId: 1, Role: constants.SHOP_ROLE_OWNER}, nil)
+			mockShopRepo.On("FindOne", ctx, mock.Anything, "id = ?", 1).Return(test.shop, nil)
+			mockShopRepo.On("Update", ctx, mock.AnythingOfType("models.Shop"), "is_active,closed_at,updated_at", "id = ?", 1).
+				Run(func(args mock.Arguments) { updated = args.Get(1).(models.Shop) }).Return(nil)
+
+			mockAuditRepo := new(mocks.AuditEventRepositoryInterface)
+			mockAuditRepo.On("Create", ctx, mock.AnythingOfType("*models.AuditEvent")).
+				Run(func(args mock.Arguments) { event = *args.Get(1).(*models.AuditEvent) }).Return(nil)
+			f := factory.Factory{Log: zap.NewNop(), AuditEventRepository: mockAuditRepo}
+
+			s := service{Log: zap.NewNop(), ShopRepository: mockShopRepo, ShopMemberRepository: mockMemberRepo, AuditService: audit.NewService(&f)}
+
+			err := s.ChangeStatusShop(ctx, dto.UserClaimJwt{UserId: 1}, 1, test.isActive)
+			if test.expectErr != nil {
+				assert.ErrorIs(t, err, test.expectErr)
+				mockAuditRepo.AssertNotCalled(t, "Create", ctx, mock.Anything)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.isActive, updated.IsActive)
+				assert.Equal(t, test.isActive, updated.ClosedAt == nil)
+				assert.Equal(t, test.action, event.Action)
+				assert.Equal(t, "1", event.ResourceId)
+			}
+		})
+	}
+}
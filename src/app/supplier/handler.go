@@ -0,0 +1,119 @@
+package supplier
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"test-edot/src/dto"
+	"test-edot/src/factory"
+)
+
+type handler struct {
+	service Service
+}
+
+func NewHandler(f *factory.Factory) *handler {
+	return &handler{
+		service: NewService(f),
+	}
+}
+
+func (h *handler) AddSupplier(g *gin.Context) {
+	userClaim := g.Value("userClaim").(dto.UserClaimJwt)
+
+	shopId, err := strconv.Atoi(g.Param("shop_id"))
+	if err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: "shop_id is not valid",
+		})
+		return
+	}
+
+	var payload dto.PayloadSupplier
+	if err := g.ShouldBindJSON(&payload); err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	res, err := h.service.AddSupplier(g, userClaim, shopId, payload)
+	if err != nil {
+		g.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	g.JSON(http.StatusCreated, dto.Response{
+		Message: "success create supplier",
+		Data:    res,
+	})
+	return
+}
+
+func (h *handler) GetSuppliers(g *gin.Context) {
+	userClaim := g.Value("userClaim").(dto.UserClaimJwt)
+
+	shopId, err := strconv.Atoi(g.Param("shop_id"))
+	if err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: "shop_id is not valid",
+		})
+		return
+	}
+
+	res, err := h.service.GetSuppliers(g, userClaim, shopId)
+	if err != nil {
+		g.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	g.JSON(http.StatusOK, dto.Response{
+		Message: "success list supplier",
+		Data:    res,
+	})
+	return
+}
+
+func (h *handler) UpdateSupplier(g *gin.Context) {
+	userClaim := g.Value("userClaim").(dto.UserClaimJwt)
+
+	shopId, err := strconv.Atoi(g.Param("shop_id"))
+	if err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: "shop_id is not valid",
+		})
+		return
+	}
+
+	supplierId, err := strconv.Atoi(g.Param("supplier_id"))
+	if err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: "supplier_id is not valid",
+		})
+		return
+	}
+
+	var payload dto.PayloadSupplier
+	if err := g.ShouldBindJSON(&payload); err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	if err := h.service.UpdateSupplier(g, userClaim, shopId, supplierId, payload); err != nil {
+		g.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	g.JSON(http.StatusOK, dto.Response{
+		Message: "success update supplier",
+	})
+	return
+}
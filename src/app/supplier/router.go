@@ -0,0 +1,13 @@
+package supplier
+
+import (
+	"github.com/gin-gonic/gin"
+	"test-edot/constants"
+	"test-edot/src/middleware"
+)
+
+func (h *handler) SupplierRouter(g *gin.RouterGroup) {
+	g.POST("", middleware.Authorize(constants.PERMISSION_SUPPLIER_WRITE), h.AddSupplier)
+	g.GET("", middleware.Authorize(constants.PERMISSION_SUPPLIER_READ), h.GetSuppliers)
+	g.PUT("/:supplier_id", middleware.Authorize(constants.PERMISSION_SUPPLIER_WRITE), h.UpdateSupplier)
+}
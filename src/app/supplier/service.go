@@ -0,0 +1,147 @@
+package supplier
+
+import (
+	"context"
+	"errors"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"test-edot/constants"
+	"test-edot/src/app/shop"
+	"test-edot/src/dto"
+	"test-edot/src/factory"
+	"test-edot/src/models"
+	"test-edot/src/repository"
+	"test-edot/src/tracing"
+	"test-edot/util"
+	"time"
+)
+
+type Service interface {
+	AddSupplier(ctx context.Context, userClaim dto.UserClaimJwt, shopId int, payload dto.PayloadSupplier) (models.Supplier, error)
+	GetSuppliers(ctx context.Context, userClaim dto.UserClaimJwt, shopId int) ([]models.Supplier, error)
+	UpdateSupplier(ctx context.Context, userClaim dto.UserClaimJwt, shopId, supplierId int, payload dto.PayloadSupplier) error
+}
+
+type service struct {
+	Log                *zap.Logger
+	ShopService        shop.Service
+	SupplierRepository repository.SupplierRepositoryInterface
+}
+
+func NewService(f *factory.Factory) Service {
+	return &service{
+		Log:                f.Log,
+		ShopService:        shop.NewService(f),
+		SupplierRepository: f.SupplierRepository,
+	}
+}
+
+// log is the logger of the request that made the call, see middleware.RequestId
+func (s *service) log(ctx context.Context) *zap.Logger {
+	return util.Logger(ctx, s.Log)
+}
+
+func (s *service) AddSupplier(ctx context.Context, userClaim dto.UserClaimJwt, shopId int, payload dto.PayloadSupplier) (models.Supplier, error) {
+	ctx, span := tracing.Start(ctx, "supplier.AddSupplier")
+	defer span.End()
+
+	if _, err := s.ShopService.Authorize(ctx, userClaim, shopId, constants.SHOP_PERMISSION_SUPPLIER_MANAGE); err != nil {
+		return models.Supplier{}, err
+	}
+
+	supplierDt, err := s.SupplierRepository.FindOne(ctx, "id", "name = ? and shop_id = ?", payload.Name, shopId)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		s.log(ctx).Error("error finding supplier", zap.Error(err))
+		return models.Supplier{}, err
+	}
+
+	if supplierDt != (models.Supplier{}) {
+		return models.Supplier{}, constants.SupplierAlreadyExisted
+	}
+
+	supplier := models.Supplier{
+		ShopId:    shopId,
+		Name:      payload.Name,
+		Email:     payload.Email,
+		Phone:     payload.Phone,
+		Address:   payload.Address,
+		IsActive:  true,
+		CreatedAt: time.Now().In(util.LocationTime),
+		UpdatedAt: time.Now().In(util.LocationTime),
+	}
+
+	if err := s.SupplierRepository.Create(ctx, &supplier); err != nil {
+		s.log(ctx).Error("error creating supplier", zap.Error(err))
+		return models.Supplier{}, err
+	}
+
+	s.log(ctx).Info("success create supplier", zap.Int("supplierId", supplier.ID), zap.Int("shopId", shopId))
+
+	return supplier, nil
+}
+
+func (s *service) GetSuppliers(ctx context.Context, userClaim dto.UserClaimJwt, shopId int) ([]models.Supplier, error) {
+	ctx, span := tracing.Start(ctx, "supplier.GetSuppliers")
+	defer span.End()
+
+	if _, err := s.ShopService.Authorize(ctx, userClaim, shopId, constants.SHOP_PERMISSION_VIEW); err != nil {
+		return nil, err
+	}
+
+	suppliers, err := s.SupplierRepository.Find(ctx, "*", "shop_id = ?", shopId)
+	if err != nil {
+		s.log(ctx).Error("error fetch suppliers", zap.Error(err), zap.Int("shopId", shopId))
+		return nil, err
+	}
+
+	return suppliers, nil
+}
+
+func (s *service) UpdateSupplier(ctx context.Context, userClaim dto.UserClaimJwt, shopId, supplierId int, payload dto.PayloadSupplier) error {
+	ctx, span := tracing.Start(ctx, "supplier.UpdateSupplier")
+	defer span.End()
+
+	if _, err := s.ShopService.Authorize(ctx, userClaim, shopId, constants.SHOP_PERMISSION_SUPPLIER_MANAGE); err != nil {
+		return err
+	}
+
+	supplier, err := s.SupplierRepository.FindOne(ctx, "id,is_active", "id = ? and shop_id = ?", supplierId, shopId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return constants.SupplierNotFound
+		}
+
+		s.log(ctx).Error("error finding supplier", zap.Error(err))
+		return err
+	}
+
+	duplicate, err := s.SupplierRepository.FindOne(ctx, "id", "name = ? and shop_id = ? and id <> ?", payload.Name, shopId, supplierId)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		s.log(ctx).Error("error finding supplier", zap.Error(err))
+		return err
+	}
+
+	if duplicate != (models.Supplier{}) {
+		return constants.SupplierAlreadyExisted
+	}
+
+	isActive := supplier.IsActive
+	if payload.IsActive != nil {
+		isActive = *payload.IsActive
+	}
+
+	updatedField := models.Supplier{
+		Name:      payload.Name,
+		Email:     payload.Email,
+		Phone:     payload.Phone,
+		Address:   payload.Address,
+		IsActive:  isActive,
+		UpdatedAt: time.Now().In(util.LocationTime),
+	}
+	if err := s.SupplierRepository.Update(ctx, updatedField, "name,email,phone,address,is_active,updated_at", "id = ?", supplierId); err != nil {
+		s.log(ctx).Error("error update supplier", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,153 @@
+package transfer
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"test-edot/src/dto"
+	"test-edot/src/factory"
+)
+
+type handler struct {
+	service Service
+}
+
+func NewHandler(f *factory.Factory) *handler {
+	return &handler{
+		service: NewService(f),
+	}
+}
+
+func (h *handler) GetTransfers(g *gin.Context) {
+	userClaim := g.Value("userClaim").(dto.UserClaimJwt)
+
+	var payload dto.ParameterQueryTransfer
+	if err := g.ShouldBind(&payload); err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	res, err := h.service.GetTransfers(g, userClaim, payload)
+	if err != nil {
+		g.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	g.JSON(http.StatusOK, dto.Response{
+		Message: "success list stock transfer",
+		Data:    res,
+	})
+	return
+}
+
+func (h *handler) GetTransfer(g *gin.Context) {
+	userClaim := g.Value("userClaim").(dto.UserClaimJwt)
+
+	transferId, err := strconv.Atoi(g.Param("transfer_id"))
+	if err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: "transfer_id is not valid",
+		})
+		return
+	}
+
+	res, err := h.service.GetTransfer(g, userClaim, transferId)
+	if err != nil {
+		g.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	g.JSON(http.StatusOK, dto.Response{
+		Message: "success fetch stock transfer",
+		Data:    res,
+	})
+	return
+}
+
+func (h *handler) MarkInTransit(g *gin.Context) {
+	userClaim := g.Value("userClaim").(dto.UserClaimJwt)
+
+	transferId, err := strconv.Atoi(g.Param("transfer_id"))
+	if err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: "transfer_id is not valid",
+		})
+		return
+	}
+
+	if err := h.service.MarkInTransit(g, userClaim, transferId); err != nil {
+		g.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	g.JSON(http.StatusOK, dto.Response{
+		Message: "success mark stock transfer in transit",
+	})
+	return
+}
+
+func (h *handler) ReceiveTransfer(g *gin.Context) {
+	userClaim := g.Value("userClaim").(dto.UserClaimJwt)
+
+	transferId, err := strconv.Atoi(g.Param("transfer_id"))
+	if err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: "transfer_id is not valid",
+		})
+		return
+	}
+
+	var payload dto.PayloadReceiveTransfer
+	if err := g.ShouldBindJSON(&payload); err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	res, err := h.service.ReceiveTransfer(g, userClaim, transferId, payload)
+	if err != nil {
+		g.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	g.JSON(http.StatusOK, dto.Response{
+		Message: "success receive stock transfer",
+		Data:    res,
+	})
+	return
+}
+
+func (h *handler) CancelTransfer(g *gin.Context) {
+	userClaim := g.Value("userClaim").(dto.UserClaimJwt)
+
+	transferId, err := strconv.Atoi(g.Param("transfer_id"))
+	if err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: "transfer_id is not valid",
+		})
+		return
+	}
+
+	if err := h.service.CancelTransfer(g, userClaim, transferId); err != nil {
+		g.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	g.JSON(http.StatusOK, dto.Response{
+		Message: "success cancel stock transfer",
+	})
+	return
+}
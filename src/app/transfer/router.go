@@ -0,0 +1,15 @@
+package transfer
+
+import (
+	"github.com/gin-gonic/gin"
+	"test-edot/constants"
+	"test-edot/src/middleware"
+)
+
+func (h *handler) TransferRouter(g *gin.RouterGroup) {
+	g.GET("", middleware.Authorize(constants.PERMISSION_TRANSFER_READ), h.GetTransfers)
+	g.GET("/:transfer_id", middleware.Authorize(constants.PERMISSION_TRANSFER_READ), h.GetTransfer)
+	g.PUT("/:transfer_id/in-transit", middleware.Authorize(constants.PERMISSION_TRANSFER_WRITE), h.MarkInTransit)
+	g.POST("/:transfer_id/receive", middleware.Authorize(constants.PERMISSION_TRANSFER_WRITE), h.ReceiveTransfer)
+	g.PUT("/:transfer_id/cancel", middleware.Authorize(constants.PERMISSION_TRANSFER_WRITE), h.CancelTransfer)
+}
@@ -0,0 +1,413 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"test-edot/constants"
+	"test-edot/metrics"
+	"test-edot/src/app/inventory"
+	"test-edot/src/dto"
+	"test-edot/src/factory"
+	"test-edot/src/models"
+	"test-edot/src/repository"
+	"test-edot/src/tracing"
+	"test-edot/util"
+	"time"
+)
+
+type Service interface {
+	DispatchTransferTx(tx *gorm.DB, transfer *models.StockTransfer, items []models.StockTransferItem) error
+	GetTransfers(ctx context.Context, userClaim dto.UserClaimJwt, payload dto.ParameterQueryTransfer) ([]models.StockTransfer, error)
+	GetTransfer(ctx context.Context, userClaim dto.UserClaimJwt, transferId int) (models.StockTransferDetail, error)
+	MarkInTransit(ctx context.Context, userClaim dto.UserClaimJwt, transferId int) error
+	ReceiveTransfer(ctx context.Context, userClaim dto.UserClaimJwt, transferId int, payload dto.PayloadReceiveTransfer) (models.StockTransferDetail, error)
+	CancelTransfer(ctx context.Context, userClaim dto.UserClaimJwt, transferId int) error
+}
+
+type service struct {
+	Log                         *zap.Logger
+	StockTransferRepository     repository.StockTransferRepositoryInterface
+	StockTransferItemRepository repository.StockTransferItemRepositoryInterface
+	InventoryService            inventory.Service
+}
+
+func NewService(f *factory.Factory) Service {
+	return &service{
+		Log:                         f.Log,
+		StockTransferRepository:     f.StockTransferRepository,
+		StockTransferItemRepository: f.StockTransferItemRepository,
+		InventoryService:            inventory.NewService(f),
+	}
+}
+
+// log is the logger of the request that made the call, see middleware.RequestId
+func (s *service) log(ctx context.Context) *zap.Logger {
+	return util.Logger(ctx, s.Log)
+}
+
+const (
+	queryTransferOwner = "id = ? and " + queryShopTransfers
+	queryShopTransfers = "from_warehouse_id in (select id from warehouses where shop_id in (select shop_id from shop_members where user_id = ? and role in ?))"
+)
+
+// DispatchTransferTx creates the transfer document and takes its items out of the source warehouse,
+// the stock is not added to the destination until the transfer is received. An item with IncludeReserved
+// also ships the held reservations of its product, the reserved qty is written back to the item
+func (s *service) DispatchTransferTx(tx *gorm.DB, transfer *models.StockTransfer, items []models.StockTransferItem) error {
+	if transfer.FromWarehouseId == transfer.ToWarehouseId {
+		return constants.TransferSameWarehouse
+	}
+
+	now := time.Now().In(util.LocationTime)
+	transfer.TransferNo = util.CreateTransferNo()
+	transfer.Status = constants.TRANSFER_STATUS_DISPATCHED
+	transfer.DispatchedAt = &now
+	transfer.CreatedAt = now
+	transfer.UpdatedAt = now
+
+	if err := s.StockTransferRepository.Create(tx, transfer); err != nil {
+		s.log(util.TxContext(tx)).Error("error creating stock transfer", zap.Error(err))
+		return err
+	}
+
+	for i, item := range items {
+		if item.Qty <= 0 {
+			return constants.QtyMustPositive
+		}
+
+		if _, err := s.InventoryService.DeductStockTx(tx, transfer.FromWarehouseId, item.ProductId, item.Qty); err != nil {
+			return err
+		}
+
+		transferItem := models.StockTransferItem{
+			StockTransferId: transfer.ID,
+			ProductId:       item.ProductId,
+			Qty:             item.Qty,
+			CreatedAt:       now,
+			UpdatedAt:       now,
+		}
+
+		if item.IncludeReserved {
+			reservedQty, err := s.InventoryService.AttachReservationTx(tx, transfer.FromWarehouseId, item.ProductId, transfer.ID)
+			if err != nil {
+				return err
+			}
+
+			transferItem.ReservedQty = reservedQty
+		}
+
+		if err := s.StockTransferItemRepository.Create(tx, &transferItem); err != nil {
+			s.log(util.TxContext(tx)).Error("error creating stock transfer item", zap.Error(err))
+			return err
+		}
+
+		items[i].ID = transferItem.ID
+		items[i].ReservedQty = transferItem.ReservedQty
+	}
+
+	if err := s.InventoryService.CheckCapacityTx(tx, transfer.ToWarehouseId); err != nil {
+		return err
+	}
+
+	s.log(util.TxContext(tx)).Info("stock transfer dispatched", zap.String("transferNo", transfer.TransferNo), zap.Int("fromWarehouseId", transfer.FromWarehouseId), zap.Int("toWarehouseId", transfer.ToWarehouseId))
+	return nil
+}
+
+func (s *service) GetTransfers(ctx context.Context, userClaim dto.UserClaimJwt, payload dto.ParameterQueryTransfer) ([]models.StockTransfer, error) {
+	ctx, span := tracing.Start(ctx, "transfer.GetTransfers")
+	defer span.End()
+
+	q := queryShopTransfers
+	args := []any{userClaim.UserId, constants.MapPermissionShopRole[constants.SHOP_PERMISSION_VIEW]}
+
+	if payload.FromWarehouseId != 0 {
+		q += " and from_warehouse_id = ?"
+		args = append(args, payload.FromWarehouseId)
+	}
+
+	if payload.ToWarehouseId != 0 {
+		q += " and to_warehouse_id = ?"
+		args = append(args, payload.ToWarehouseId)
+	}
+
+	if payload.Status != "" {
+		q += " and status = ?"
+		args = append(args, payload.Status)
+	}
+
+	transfers, err := s.StockTransferRepository.Find(ctx, "*", q, args...)
+	if err != nil {
+		s.log(ctx).Error("error fetch stock transfers", zap.Error(err), zap.Int("user_id", userClaim.UserId))
+		return nil, err
+	}
+
+	return transfers, nil
+}
+
+func (s *service) GetTransfer(ctx context.Context, userClaim dto.UserClaimJwt, transferId int) (models.StockTransferDetail, error) {
+	ctx, span := tracing.Start(ctx, "transfer.GetTransfer")
+	defer span.End()
+
+	transfer, err := s.StockTransferRepository.GetDetail(ctx, queryTransferOwner, transferId, userClaim.UserId, constants.MapPermissionShopRole[constants.SHOP_PERMISSION_VIEW])
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.StockTransferDetail{}, constants.TransferNotFound
+		}
+
+		s.log(ctx).Error("error fetch stock transfer", zap.Error(err), zap.Int("transferId", transferId))
+		return models.StockTransferDetail{}, err
+	}
+
+	return transfer, nil
+}
+
+func (s *service) MarkInTransit(ctx context.Context, userClaim dto.UserClaimJwt, transferId int) error {
+	ctx, span := tracing.Start(ctx, "transfer.MarkInTransit")
+	defer span.End()
+
+	tx := s.StockTransferRepository.Begin().WithContext(ctx)
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		s.log(ctx).Error("error begin transaction", zap.Error(err))
+		return err
+	}
+
+	transfer, err := s.FindTransferTx(tx, userClaim, transferId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if transfer.Status != constants.TRANSFER_STATUS_DISPATCHED {
+		tx.Rollback()
+		return constants.TransferStatusFlow
+	}
+
+	if err := s.UpdateStatusTransfer(tx, transfer, constants.TRANSFER_STATUS_IN_TRANSIT); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		s.log(ctx).Error("error commit transaction", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) ReceiveTransfer(ctx context.Context, userClaim dto.UserClaimJwt, transferId int, payload dto.PayloadReceiveTransfer) (models.StockTransferDetail, error) {
+	ctx, span := tracing.Start(ctx, "transfer.ReceiveTransfer")
+	defer span.End()
+
+	if len(payload.Items) == 0 {
+		return models.StockTransferDetail{}, constants.TransferItemInvalid
+	}
+
+	tx := s.StockTransferRepository.Begin().WithContext(ctx)
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		s.log(ctx).Error("error begin transaction", zap.Error(err))
+		return models.StockTransferDetail{}, err
+	}
+
+	transfer, err := s.FindTransferTx(tx, userClaim, transferId)
+	if err != nil {
+		tx.Rollback()
+		return models.StockTransferDetail{}, err
+	}
+
+	status, err := s.ProcessReceiveTransfer(tx, transfer, payload)
+	if err != nil {
+		tx.Rollback()
+		return models.StockTransferDetail{}, err
+	}
+
+	if err := s.UpdateStatusTransfer(tx, transfer, status); err != nil {
+		tx.Rollback()
+		return models.StockTransferDetail{}, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		s.log(ctx).Error("error commit transaction", zap.Error(err))
+		return models.StockTransferDetail{}, err
+	}
+
+	// a partial receive keeps the transfer in transit, only the last one completes it
+	if status == constants.TRANSFER_STATUS_RECEIVED {
+		metrics.StockTransferTotal.WithLabelValues(status).Inc()
+	}
+
+	return s.GetTransfer(ctx, userClaim, transferId)
+}
+
+// ProcessReceiveTransfer adds the received qty to the destination warehouse and returns the status
+// the transfer has to move to afterwards, the reservations shipped with an item follow once it is fully received
+func (s *service) ProcessReceiveTransfer(tx *gorm.DB, transfer models.StockTransfer, payload dto.PayloadReceiveTransfer) (string, error) {
+	if !constants.MapTransferStatusNext[transfer.Status][constants.TRANSFER_STATUS_RECEIVED] {
+		return "", constants.TransferStatusFlow
+	}
+
+	items, err := s.StockTransferItemRepository.FindTx(tx, "*", "stock_transfer_id = ?", transfer.ID)
+	if err != nil {
+		s.log(util.TxContext(tx)).Error("error get stock transfer items", zap.Error(err))
+		return "", err
+	}
+
+	mapItem := make(map[int]*models.StockTransferItem)
+	for i := range items {
+		mapItem[items[i].ID] = &items[i]
+	}
+
+	for _, receive := range payload.Items {
+		item, ok := mapItem[receive.ItemId]
+		if !ok {
+			return "", constants.TransferItemInvalid
+		}
+
+		if receive.Qty <= 0 {
+			return "", constants.QtyMustPositive
+		}
+
+		if item.ReceivedQty+receive.Qty > item.Qty {
+			return "", constants.ReceiveQtyExceeded
+		}
+
+		if _, err := s.InventoryService.AddStockTx(tx, transfer.ToWarehouseId, item.ProductId, receive.Qty); err != nil {
+			return "", err
+		}
+
+		item.ReceivedQty += receive.Qty
+		updatedItem := models.StockTransferItem{ReceivedQty: item.ReceivedQty, UpdatedAt: time.Now().In(util.LocationTime)}
+		if err := s.StockTransferItemRepository.UpdateOneTx(tx, &updatedItem, "received_qty,updated_at", "id = ?", item.ID); err != nil {
+			s.log(util.TxContext(tx)).Error("error update stock transfer item", zap.Error(err))
+			return "", err
+		}
+
+		if item.ReservedQty > 0 && item.ReceivedQty == item.Qty {
+			if _, err := s.InventoryService.MoveReservationTx(tx, transfer.ID, item.ProductId, transfer.ToWarehouseId); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	for _, item := range items {
+		if item.ReceivedQty < item.Qty {
+			return constants.TRANSFER_STATUS_IN_TRANSIT, nil
+		}
+	}
+
+	return constants.TRANSFER_STATUS_RECEIVED, nil
+}
+
+// CancelTransfer puts every qty that has not been received yet back to the source warehouse and takes the
+// reservations that did not move yet off the transfer
+func (s *service) CancelTransfer(ctx context.Context, userClaim dto.UserClaimJwt, transferId int) error {
+	ctx, span := tracing.Start(ctx, "transfer.CancelTransfer")
+	defer span.End()
+
+	tx := s.StockTransferRepository.Begin().WithContext(ctx)
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		s.log(ctx).Error("error begin transaction", zap.Error(err))
+		return err
+	}
+
+	transfer, err := s.FindTransferTx(tx, userClaim, transferId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if !constants.MapTransferStatusNext[transfer.Status][constants.TRANSFER_STATUS_CANCELLED] {
+		tx.Rollback()
+		return constants.TransferStatusFlow
+	}
+
+	items, err := s.StockTransferItemRepository.FindTx(tx, "*", "stock_transfer_id = ?", transfer.ID)
+	if err != nil {
+		tx.Rollback()
+		s.log(ctx).Error("error get stock transfer items", zap.Error(err))
+		return err
+	}
+
+	for _, item := range items {
+		remaining := item.Qty - item.ReceivedQty
+		if remaining <= 0 {
+			continue
+		}
+
+		if _, err := s.InventoryService.AddStockTx(tx, transfer.FromWarehouseId, item.ProductId, remaining); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err := s.InventoryService.DetachReservationTx(tx, transfer.ID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := s.UpdateStatusTransfer(tx, transfer, constants.TRANSFER_STATUS_CANCELLED); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		s.log(ctx).Error("error commit transaction", zap.Error(err))
+		return err
+	}
+
+	metrics.StockTransferTotal.WithLabelValues(constants.TRANSFER_STATUS_CANCELLED).Inc()
+
+	return nil
+}
+
+func (s *service) FindTransferTx(tx *gorm.DB, userClaim dto.UserClaimJwt, transferId int) (models.StockTransfer, error) {
+	transfer, err := s.StockTransferRepository.FindOneTx(tx, "id,status,from_warehouse_id,to_warehouse_id", queryTransferOwner, transferId, userClaim.UserId, constants.MapPermissionShopRole[constants.SHOP_PERMISSION_STOCK_ADJUST])
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.StockTransfer{}, constants.TransferNotFound
+		}
+
+		s.log(util.TxContext(tx)).Error("error get stock transfer", zap.Error(err))
+		return models.StockTransfer{}, err
+	}
+
+	return transfer, nil
+}
+
+func (s *service) UpdateStatusTransfer(tx *gorm.DB, transfer models.StockTransfer, status string) error {
+	now := time.Now().In(util.LocationTime)
+	updatedField := models.StockTransfer{Status: status, UpdatedAt: now}
+	fields := "status,updated_at"
+	if status == constants.TRANSFER_STATUS_RECEIVED {
+		updatedField.ReceivedAt = &now
+		fields += ",received_at"
+	}
+
+	if err := s.StockTransferRepository.UpdateOneTx(tx, &updatedField, fields, "id = ?", transfer.ID); err != nil {
+		s.log(util.TxContext(tx)).Error("error update stock transfer", zap.Error(err))
+		return err
+	}
+
+	s.log(util.TxContext(tx)).Info("stock transfer status changed", zap.Int("transferId", transfer.ID), zap.String("from", transfer.Status), zap.String("to", status))
+	return nil
+}
@@ -0,0 +1,136 @@
+package transfer
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"test-edot/constants"
+	"test-edot/src/app/inventory"
+	"test-edot/src/dto"
+	"test-edot/src/factory"
+	"test-edot/src/models"
+	"test-edot/src/repository/mocks"
+	"testing"
+)
+
+type (
+	TestProcessReceiveTransferData struct {
+		name         string
+		status       string
+		items        []models.StockTransferItem
+		payload      dto.PayloadReceiveTransfer
+		expectStatus string
+		expectMoved  bool
+		expectErr    error
+	}
+)
+
+func TestProcessReceiveTransfer(t *testing.T) {
+
+	tableTests := []TestProcessReceiveTransferData{
+		{
+			name:   "test partial receive",
+			status: constants.TRANSFER_STATUS_DISPATCHED,
+			items: []models.StockTransferItem{
+				{ID: 1, ProductId: 1, Qty: 10},
+				{ID: 2, ProductId: 2, Qty: 5},
+			},
+			payload:      dto.PayloadReceiveTransfer{Items: []dto.PayloadReceiveTransferItem{{ItemId: 1, Qty: 10}}},
+			expectStatus: constants.TRANSFER_STATUS_IN_TRANSIT,
+		},
+		{
+			name:   "test receive remaining items",
+			status: constants.TRANSFER_STATUS_IN_TRANSIT,
+			items: []models.StockTransferItem{
+				{ID: 1, ProductId: 1, Qty: 10, ReceivedQty: 10},
+				{ID: 2, ProductId: 2, Qty: 5, ReceivedQty: 2},
+			},
+			payload:      dto.PayloadReceiveTransfer{Items: []dto.PayloadReceiveTransferItem{{ItemId: 2, Qty: 3}}},
+			expectStatus: constants.TRANSFER_STATUS_RECEIVED,
+		},
+		{
+			name:   "test keep reservations of partially received item",
+			status: constants.TRANSFER_STATUS_DISPATCHED,
+			items: []models.StockTransferItem{
+				{ID: 1, ProductId: 1, Qty: 10, ReservedQty: 2},
+			},
+			payload:      dto.PayloadReceiveTransfer{Items: []dto.PayloadReceiveTransferItem{{ItemId: 1, Qty: 4}}},
+			expectStatus: constants.TRANSFER_STATUS_IN_TRANSIT,
+		},
+		{
+			name:   "test move reservations of fully received item",
+			status: constants.TRANSFER_STATUS_IN_TRANSIT,
+			items: []models.StockTransferItem{
+				{ID: 1, ProductId: 1, Qty: 10, ReceivedQty: 4, ReservedQty: 2},
+			},
+			payload:      dto.PayloadReceiveTransfer{Items: []dto.PayloadReceiveTransferItem{{ItemId: 1, Qty: 6}}},
+			expectStatus: constants.TRANSFER_STATUS_RECEIVED,
+			expectMoved:  true,
+		},
+		{
+			name:   "test receive more than dispatched",
+			status: constants.TRANSFER_STATUS_IN_TRANSIT,
+			items: []models.StockTransferItem{
+				{ID: 1, ProductId: 1, Qty: 10, ReceivedQty: 8},
+			},
+			payload:   dto.PayloadReceiveTransfer{Items: []dto.PayloadReceiveTransferItem{{ItemId: 1, Qty: 3}}},
+			expectErr: constants.ReceiveQtyExceeded,
+		},
+		{
+			name:   "test receive unknown item",
+			status: constants.TRANSFER_STATUS_DISPATCHED,
+			items: []models.StockTransferItem{
+				{ID: 1, ProductId: 1, Qty: 10},
+			},
+			payload:   dto.PayloadReceiveTransfer{Items: []dto.PayloadReceiveTransferItem{{ItemId: 9, Qty: 1}}},
+			expectErr: constants.TransferItemInvalid,
+		},
+		{
+			name:   "test receive cancelled transfer",
+			status: constants.TRANSFER_STATUS_CANCELLED,
+			items: []models.StockTransferItem{
+				{ID: 1, ProductId: 1, Qty: 10},
+			},
+			payload:   dto.PayloadReceiveTransfer{Items: []dto.PayloadReceiveTransferItem{{ItemId: 1, Qty: 1}}},
+			expectErr: constants.TransferStatusFlow,
+		},
+	}
+
+	for _, test := range tableTests {
+		t.Run(test.name, func(t *testing.T) {
+
+			tx := gorm.DB{}
+			mockItemRepo := new(mocks.StockTransferItemRepositoryInterface)
+			mockStockRepo := new(mocks.StockLevelRepositoryInterface)
+			mockReservationRepo := new(mocks.StockReservationRepositoryInterface)
+
+			mockItemRepo.On("FindTx", &tx, "*", "stock_transfer_id = ?", 1).Return(test.items, nil)
+			mockItemRepo.On("UpdateOneTx", &tx, mock.AnythingOfType("*models.StockTransferItem"), mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+			mockStockRepo.On("FindOneTx", &tx, "id asc", mock.Anything, mock.Anything, mock.Anything).Return(models.StockLevelProduct{ID: 1, Stock: 1}, nil)
+			mockStockRepo.On("FindOneTx", &tx, "id asc", "id = ?", 3).Return(models.StockLevelProduct{ID: 3, ProductId: 1, ReservedStock: 2}, nil)
+			mockStockRepo.On("UpdateOneTx", &tx, mock.AnythingOfType("*models.StockLevel"), mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+			mockReservationRepo.On("FindTx", &tx, "*", mock.Anything, 1, 1, constants.RESERVATION_STATE_HELD).Return([]models.StockReservation{{ID: 1, StockId: 3, ProductId: 1, Qty: 2}}, nil)
+			mockReservationRepo.On("UpdateOneTx", &tx, mock.AnythingOfType("*models.StockReservation"), "stock_id,warehouse_id,stock_transfer_id,updated_at", mock.Anything, 1, 1, constants.RESERVATION_STATE_HELD).Return(nil)
+
+			f := factory.Factory{Log: zap.NewNop(), StockLevelRepository: mockStockRepo, StockReservationRepository: mockReservationRepo}
+			s := service{Log: zap.NewNop(), StockTransferItemRepository: mockItemRepo, InventoryService: inventory.NewService(&f)}
+
+			stockTransfer := models.StockTransfer{ID: 1, Status: test.status, FromWarehouseId: 1, ToWarehouseId: 2}
+			status, err := s.ProcessReceiveTransfer(&tx, stockTransfer, test.payload)
+			if test.expectErr != nil {
+				assert.ErrorIs(t, err, test.expectErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expectStatus, status)
+				if test.expectMoved {
+					mockReservationRepo.AssertNumberOfCalls(t, "UpdateOneTx", 1)
+				} else {
+					mockReservationRepo.AssertNumberOfCalls(t, "FindTx", 0)
+				}
+			}
+		})
+	}
+}
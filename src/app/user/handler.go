@@ -1,9 +1,10 @@
 package user
 
 import (
-	"fmt"
+	"errors"
 	"github.com/gin-gonic/gin"
 	"net/http"
+	"test-edot/constants"
 	"test-edot/src/dto"
 	"test-edot/src/factory"
 )
@@ -21,10 +22,83 @@ func NewHandler(f *factory.Factory) *handler {
 func (h *handler) UserMe(g *gin.Context) {
 	userClaim := g.Value("userClaim").(dto.UserClaimJwt)
 
-	fmt.Println(userClaim)
+	user, err := h.service.GetProfile(g, userClaim)
+	if err != nil {
+		g.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
 
-	g.JSON(http.StatusCreated, dto.Response{
+	g.JSON(http.StatusOK, dto.Response{
 		Message: "user fetched",
+		Data:    user,
+	})
+	return
+}
+
+func (h *handler) UpdateUserMe(g *gin.Context) {
+	userClaim := g.Value("userClaim").(dto.UserClaimJwt)
+
+	var payload dto.PayloadUpdateProfile
+	if err := g.ShouldBindJSON(&payload); err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	user, err := h.service.UpdateProfile(g, userClaim, payload)
+	if err != nil {
+		g.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	g.JSON(http.StatusOK, dto.Response{
+		Message: "user updated",
+		Data:    user,
+	})
+	return
+}
+
+func (h *handler) ChangePassword(g *gin.Context) {
+	userClaim := g.Value("userClaim").(dto.UserClaimJwt)
+
+	var payload dto.PayloadChangePassword
+	if err := g.ShouldBindJSON(&payload); err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	if err := h.service.ChangePassword(g, userClaim, payload); err != nil {
+		g.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	g.JSON(http.StatusOK, dto.Response{
+		Message: "password changed, please login again",
+	})
+	return
+}
+
+func (h *handler) DeactivateUserMe(g *gin.Context) {
+	userClaim := g.Value("userClaim").(dto.UserClaimJwt)
+
+	if err := h.service.Deactivate(g, userClaim); err != nil {
+		g.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	g.JSON(http.StatusOK, dto.Response{
+		Message: "user deactivated",
 	})
 	return
 }
@@ -38,17 +112,60 @@ func (h *handler) LoginUser(g *gin.Context) {
 		return
 	}
 
-	token, err := h.service.Login(g, payload)
+	token, err := h.service.Login(g, payload, g.ClientIP())
 	if err != nil {
+		if errors.Is(err, constants.LoginLocked) {
+			g.JSON(http.StatusTooManyRequests, dto.ErrorResponse{
+				Error: err.Error(),
+			})
+			return
+		}
+
 		g.JSON(http.StatusBadRequest, dto.ErrorResponse{
 			Error: err.Error(),
 		})
 		return
 	}
 
+	message := "login successfully"
+	if token.TwoFactorRequired {
+		message = "two factor code required"
+	}
+
+	g.JSON(http.StatusOK, dto.Response{
+		Message: message,
+		Data:    token,
+	})
+	return
+}
+
+func (h *handler) LoginTwoFactor(g *gin.Context) {
+	var payload dto.PayloadLoginTwoFactor
+	if err := g.ShouldBindJSON(&payload); err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	token, err := h.service.LoginTwoFactor(g, payload, g.ClientIP())
+	if err != nil {
+		if errors.Is(err, constants.LoginLocked) {
+			g.JSON(http.StatusTooManyRequests, dto.ErrorResponse{
+				Error: err.Error(),
+			})
+			return
+		}
+
+		g.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
 	g.JSON(http.StatusOK, dto.Response{
 		Message: "login successfully",
-		Data:    dto.ResponseToken{Token: token},
+		Data:    token,
 	})
 	return
 }
@@ -76,3 +193,254 @@ func (h *handler) RegisterUser(g *gin.Context) {
 	})
 	return
 }
+
+func (h *handler) RefreshToken(g *gin.Context) {
+	var payload dto.PayloadRefreshToken
+	if err := g.ShouldBindJSON(&payload); err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	token, err := h.service.Refresh(g, payload)
+	if err != nil {
+		g.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	g.JSON(http.StatusOK, dto.Response{
+		Message: "refresh token successfully",
+		Data:    token,
+	})
+	return
+}
+
+func (h *handler) Logout(g *gin.Context) {
+	userClaim := g.Value("userClaim").(dto.UserClaimJwt)
+
+	var payload dto.PayloadLogout
+	if g.Request.ContentLength > 0 {
+		if err := g.ShouldBindJSON(&payload); err != nil {
+			g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+				Error: err.Error(),
+			})
+			return
+		}
+	}
+
+	if err := h.service.Logout(g, userClaim, payload); err != nil {
+		g.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	g.JSON(http.StatusOK, dto.Response{
+		Message: "logout successfully",
+	})
+	return
+}
+
+func (h *handler) Jwks(g *gin.Context) {
+	jwks, err := h.service.Jwks(g)
+	if err != nil {
+		g.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	// plain JWKS (RFC 7517) without dto.Response so JWT libraries can read it
+	g.Header("Cache-Control", "public, max-age=300")
+	g.JSON(http.StatusOK, jwks)
+	return
+}
+
+func (h *handler) RequestVerification(g *gin.Context) {
+	userClaim := g.Value("userClaim").(dto.UserClaimJwt)
+
+	var payload dto.PayloadRequestVerification
+	if err := g.ShouldBindJSON(&payload); err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	if err := h.service.RequestVerification(g, userClaim, payload); err != nil {
+		g.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	g.JSON(http.StatusOK, dto.Response{
+		Message: "verification code sent",
+	})
+	return
+}
+
+func (h *handler) ConfirmVerification(g *gin.Context) {
+	userClaim := g.Value("userClaim").(dto.UserClaimJwt)
+
+	var payload dto.PayloadConfirmVerification
+	if err := g.ShouldBindJSON(&payload); err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	if err := h.service.ConfirmVerification(g, userClaim, payload); err != nil {
+		g.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	g.JSON(http.StatusOK, dto.Response{
+		Message: "verification successfully",
+	})
+	return
+}
+
+func (h *handler) ForgotPassword(g *gin.Context) {
+	var payload dto.PayloadForgotPassword
+	if err := g.ShouldBindJSON(&payload); err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	if err := h.service.ForgotPassword(g, payload); err != nil {
+		g.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	g.JSON(http.StatusOK, dto.Response{
+		Message: "if the email is registered a reset code has been sent",
+	})
+	return
+}
+
+func (h *handler) ResetPassword(g *gin.Context) {
+	var payload dto.PayloadResetPassword
+	if err := g.ShouldBindJSON(&payload); err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	if err := h.service.ResetPassword(g, payload); err != nil {
+		g.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	g.JSON(http.StatusOK, dto.Response{
+		Message: "reset password successfully",
+	})
+	return
+}
+
+func (h *handler) EnrollTwoFactor(g *gin.Context) {
+	userClaim := g.Value("userClaim").(dto.UserClaimJwt)
+
+	res, err := h.service.EnrollTwoFactor(g, userClaim)
+	if err != nil {
+		g.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	g.JSON(http.StatusOK, dto.Response{
+		Message: "scan the uri with an authenticator app and confirm with the first code",
+		Data:    res,
+	})
+	return
+}
+
+func (h *handler) ConfirmTwoFactor(g *gin.Context) {
+	userClaim := g.Value("userClaim").(dto.UserClaimJwt)
+
+	var payload dto.PayloadTwoFactorCode
+	if err := g.ShouldBindJSON(&payload); err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	res, err := h.service.ConfirmTwoFactor(g, userClaim, payload)
+	if err != nil {
+		g.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	g.JSON(http.StatusOK, dto.Response{
+		Message: "two factor enabled, keep the recovery codes in a safe place",
+		Data:    res,
+	})
+	return
+}
+
+func (h *handler) DisableTwoFactor(g *gin.Context) {
+	userClaim := g.Value("userClaim").(dto.UserClaimJwt)
+
+	var payload dto.PayloadDisableTwoFactor
+	if err := g.ShouldBindJSON(&payload); err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	if err := h.service.DisableTwoFactor(g, userClaim, payload); err != nil {
+		g.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	g.JSON(http.StatusOK, dto.Response{
+		Message: "two factor disabled",
+	})
+	return
+}
+
+func (h *handler) RegenerateRecoveryCodes(g *gin.Context) {
+	userClaim := g.Value("userClaim").(dto.UserClaimJwt)
+
+	var payload dto.PayloadTwoFactorCode
+	if err := g.ShouldBindJSON(&payload); err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	res, err := h.service.RegenerateRecoveryCodes(g, userClaim, payload)
+	if err != nil {
+		g.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	g.JSON(http.StatusOK, dto.Response{
+		Message: "recovery codes regenerated",
+		Data:    res,
+	})
+	return
+}
@@ -0,0 +1,89 @@
+package user
+
+import (
+	"bytes"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"net/http"
+	"net/http/httptest"
+	"test-edot/constants"
+	"test-edot/src/models"
+	"test-edot/src/repository/mocks"
+	"test-edot/util"
+	"testing"
+	"time"
+)
+
+type (
+	TestLoginUserClientIpData struct {
+		name           string
+		trustedProxies string
+		remoteAddr     string
+		forwardedFor   string
+		expectIp       string
+	}
+)
+
+func TestLoginUserClientIp(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	now := time.Now().In(util.LocationTime)
+	lastFailed := now.Add(-time.Second)
+	lockedUntil := now.Add(time.Minute)
+
+	tableTests := []TestLoginUserClientIpData{
+		{
+			name:         "test spoofed forwarded for without trusted proxy",
+			remoteAddr:   "10.0.0.9:1234",
+			forwardedFor: "203.0.113.7",
+			expectIp:     "10.0.0.9",
+		},
+		{
+			name:           "test spoofed forwarded for from a proxy that is not trusted",
+			trustedProxies: "10.0.0.1",
+			remoteAddr:     "10.0.0.9:1234",
+			forwardedFor:   "203.0.113.7",
+			expectIp:       "10.0.0.9",
+		},
+		{
+			name:           "test client behind trusted proxy",
+			trustedProxies: "10.0.0.1, 10.0.1.0/24",
+			remoteAddr:     "10.0.0.1:1234",
+			forwardedFor:   "10.0.0.9",
+			expectIp:       "10.0.0.9",
+		},
+	}
+
+	for _, test := range tableTests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("TRUSTED_PROXIES", test.trustedProxies)
+
+			mockUserRepo := new(mocks.UserRepositoryInterface)
+			mockUserRepo.On("FindOne", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(models.User{}, gorm.ErrRecordNotFound)
+
+			// only the ip the client really connects from is locked out
+			mockFailureRepo := new(mocks.LoginFailureRepositoryInterface)
+			mockFailureRepo.On("FindOne", mock.Anything, "*", "scope = ? and identifier = ?", constants.LOGIN_SCOPE_ACCOUNT, mock.Anything).Return(models.LoginFailure{}, gorm.ErrRecordNotFound)
+			mockFailureRepo.On("FindOne", mock.Anything, "*", "scope = ? and identifier = ?", constants.LOGIN_SCOPE_IP, test.expectIp).
+				Return(models.LoginFailure{ID: 1, FailedCount: constants.LOGIN_IP_MAX_FAILURES, LastFailedAt: &lastFailed, LockedUntil: &lockedUntil}, nil)
+
+			h := handler{service: &service{Log: zap.NewNop(), UserRepository: mockUserRepo, LoginFailureRepository: mockFailureRepo}}
+
+			g := gin.New()
+			assert.NoError(t, g.SetTrustedProxies(util.TrustedProxies()))
+			g.POST("/login", h.LoginUser)
+
+			req := httptest.NewRequest(http.MethodPost, "/login", bytes.NewBufferString(`{"email":"user@mail.com","password":"secret"}`))
+			req.RemoteAddr = test.remoteAddr
+			req.Header.Set("X-Forwarded-For", test.forwardedFor)
+
+			w := httptest.NewRecorder()
+			g.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusTooManyRequests, w.Code)
+			mockFailureRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,246 @@
+package user
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"strconv"
+	"strings"
+	"test-edot/constants"
+	"test-edot/metrics"
+	"test-edot/src/dto"
+	"test-edot/src/models"
+	"test-edot/src/tracing"
+	"test-edot/util"
+	"time"
+)
+
+// dummyPasswordHash is compared when the account does not exist, so an unknown email takes as long as a wrong password
+const dummyPasswordHash = "$2a$10$idtHYzvmv..eCVRSXlBRbOaaMM2Ml.WVepy9riLhjSr/ZbwdcWq.q"
+
+type loginScope struct {
+	Scope      string
+	Identifier string
+}
+
+// Login rejects unknown accounts and wrong passwords with the same error, failures are counted
+// per account and per ip and both are checked before the password
+func (s service) Login(ctx context.Context, payload dto.LoginUser, clientIp string) (dto.ResponseToken, error) {
+	ctx, span := tracing.Start(ctx, "user.Login")
+	defer span.End()
+
+	userTrack, err := s.UserRepository.FindOne(ctx, "id,role,is_active,email,phone,password,totp_enabled_at", "email = ? or phone = ?", payload.Email, payload.Email)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return dto.ResponseToken{}, err
+	}
+
+	scopes := loginScopes(userTrack, payload.Email, clientIp)
+	if err := s.CheckLoginLock(ctx, scopes); err != nil {
+		return dto.ResponseToken{}, err
+	}
+
+	if userTrack == (models.User{}) {
+		s.checkPasswordHash(payload.Password, dummyPasswordHash)
+		return dto.ResponseToken{}, s.RecordLoginFailure(ctx, scopes, clientIp, "unknown_account")
+	}
+
+	if !s.checkPasswordHash(payload.Password, userTrack.Password) {
+		return dto.ResponseToken{}, s.RecordLoginFailure(ctx, scopes, clientIp, "invalid_password")
+	}
+
+	if !userTrack.IsActive {
+		return dto.ResponseToken{}, constants.UserDeactivated
+	}
+
+	// with two factor on the failures are only reset once the code matches, see LoginTwoFactor
+	if userTrack.TotpEnabledAt != nil {
+		return s.StartChallenge(ctx, userTrack)
+	}
+
+	if err := s.LoginFailureRepository.Delete(ctx, "scope = ? and identifier = ?", scopes[0].Scope, scopes[0].Identifier); err != nil {
+		s.log(ctx).Error("error reset login failure", zap.Error(err), zap.Int("userId", userTrack.Id))
+	}
+
+	return s.StartSession(ctx, userTrack)
+}
+
+// loginScopes the account is the user id so email and phone share the counter,
+// an unknown account is counted by what was typed
+func loginScopes(user models.User, identifier, clientIp string) []loginScope {
+	account := strings.ToLower(strings.TrimSpace(identifier))
+	if user.Id != 0 {
+		account = "user:" + strconv.Itoa(user.Id)
+	}
+
+	scopes := []loginScope{{Scope: constants.LOGIN_SCOPE_ACCOUNT, Identifier: account}}
+	if clientIp != "" {
+		scopes = append(scopes, loginScope{Scope: constants.LOGIN_SCOPE_IP, Identifier: clientIp})
+	}
+
+	return scopes
+}
+
+func (s service) CheckLoginLock(ctx context.Context, scopes []loginScope) error {
+	now := time.Now().In(util.LocationTime)
+	for _, scope := range scopes {
+		failure, err := s.LoginFailureRepository.FindOne(ctx, "*", "scope = ? and identifier = ?", scope.Scope, scope.Identifier)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				continue
+			}
+
+			s.log(ctx).Error("error get login failure", zap.Error(err))
+			return err
+		}
+
+		if !LoginBlockedUntil(failure, now).IsZero() {
+			metrics.LoginFailureTotal.WithLabelValues("blocked_" + scope.Scope).Inc()
+			return constants.LoginLocked
+		}
+	}
+
+	return nil
+}
+
+// RecordLoginFailure counts the failed attempt on every scope and returns the generic credential error
+func (s service) RecordLoginFailure(ctx context.Context, scopes []loginScope, clientIp, reason string) error {
+	metrics.LoginFailureTotal.WithLabelValues(reason).Inc()
+
+	tx := s.LoginFailureRepository.Begin().WithContext(ctx)
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		s.log(ctx).Error("error begin transaction", zap.Error(err))
+		return err
+	}
+
+	now := time.Now().In(util.LocationTime)
+	var lockouts []models.LoginFailure
+	for _, scope := range scopes {
+		failure, locked, err := s.RecordLoginFailureTx(tx, scope, now)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		if locked {
+			lockouts = append(lockouts, failure)
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		s.log(ctx).Error("error commit transaction", zap.Error(err))
+		return err
+	}
+
+	for _, failure := range lockouts {
+		s.RecordLockout(ctx, failure, clientIp)
+	}
+
+	return constants.InvalidCredential
+}
+
+func (s service) RecordLoginFailureTx(tx *gorm.DB, scope loginScope, now time.Time) (models.LoginFailure, bool, error) {
+	created := models.LoginFailure{Scope: scope.Scope, Identifier: scope.Identifier, CreatedAt: now, UpdatedAt: now}
+	if err := s.LoginFailureRepository.CreateTx(tx, &created); err != nil {
+		s.log(util.TxContext(tx)).Error("error creating login failure", zap.Error(err))
+		return models.LoginFailure{}, false, err
+	}
+
+	failure, err := s.LoginFailureRepository.FindOneTx(tx, "*", "scope = ? and identifier = ?", scope.Scope, scope.Identifier)
+	if err != nil {
+		s.log(util.TxContext(tx)).Error("error get login failure", zap.Error(err))
+		return models.LoginFailure{}, false, err
+	}
+
+	failure, locked := NextLoginFailure(failure, constants.MapLoginMaxFailures[scope.Scope], now)
+	if err := s.LoginFailureRepository.UpdateTx(tx, &failure, "failed_count,last_failed_at,locked_until,updated_at", "id = ?", failure.ID); err != nil {
+		s.log(util.TxContext(tx)).Error("error update login failure", zap.Error(err))
+		return models.LoginFailure{}, false, err
+	}
+
+	return failure, locked, nil
+}
+
+// RecordLockout the login already failed, an error writing the audit event is only logged
+func (s service) RecordLockout(ctx context.Context, failure models.LoginFailure, clientIp string) {
+	metrics.LoginLockoutTotal.WithLabelValues(failure.Scope).Inc()
+	s.log(ctx).Warn("login locked", zap.String("scope", failure.Scope), zap.String("identifier", failure.Identifier), zap.Int("failedCount", failure.FailedCount))
+
+	after, err := json.Marshal(map[string]any{"failed_count": failure.FailedCount, "locked_until": failure.LockedUntil})
+	if err != nil {
+		s.log(ctx).Error("error marshal audit event", zap.Error(err))
+		return
+	}
+
+	afterValue := string(after)
+	resourceType := constants.AUDIT_RESOURCE_ACCOUNT
+	if failure.Scope == constants.LOGIN_SCOPE_IP {
+		resourceType = constants.AUDIT_RESOURCE_IP
+	}
+
+	event := models.AuditEvent{
+		Action:       constants.AUDIT_ACTION_LOGIN_LOCKOUT,
+		ResourceType: resourceType,
+		ResourceId:   failure.Identifier,
+		AfterValue:   &afterValue,
+		Ip:           clientIp,
+		CreatedAt:    time.Now().In(util.LocationTime),
+	}
+	if err := s.AuditEventRepository.Create(ctx, &event); err != nil {
+		s.log(ctx).Error("error creating audit event", zap.Error(err))
+	}
+}
+
+// LoginBlockedUntil is the time the scope may try again, zero when it is not blocked.
+// A lockout blocks until locked_until, before that every failure from LOGIN_DELAY_AFTER on doubles the wait
+func LoginBlockedUntil(failure models.LoginFailure, now time.Time) time.Time {
+	if failure.LockedUntil != nil && failure.LockedUntil.After(now) {
+		return *failure.LockedUntil
+	}
+
+	if failure.LastFailedAt == nil || failure.FailedCount < constants.LOGIN_DELAY_AFTER {
+		return time.Time{}
+	}
+
+	delay := constants.LOGIN_MAX_DELAY
+	if shift := failure.FailedCount - constants.LOGIN_DELAY_AFTER; shift < 16 && time.Second<<shift < delay {
+		delay = time.Second << shift
+	}
+
+	blockedUntil := failure.LastFailedAt.Add(delay)
+	if !blockedUntil.After(now) {
+		return time.Time{}
+	}
+
+	return blockedUntil
+}
+
+// NextLoginFailure counts a failed attempt, the counter starts again after the window or an ended lockout.
+// locked is true when this attempt starts a lockout
+func NextLoginFailure(failure models.LoginFailure, maxFailures int, now time.Time) (models.LoginFailure, bool) {
+	windowEnded := failure.LastFailedAt == nil || !failure.LastFailedAt.Add(constants.LOGIN_FAILURE_WINDOW).After(now)
+	lockEnded := failure.LockedUntil != nil && !failure.LockedUntil.After(now)
+	if windowEnded || lockEnded {
+		failure.FailedCount = 0
+		failure.LockedUntil = nil
+	}
+
+	failure.FailedCount++
+	failure.LastFailedAt = &now
+	failure.UpdatedAt = now
+
+	if failure.FailedCount >= maxFailures && failure.LockedUntil == nil {
+		lockedUntil := now.Add(constants.LOGIN_LOCKOUT_DURATION)
+		failure.LockedUntil = &lockedUntil
+		return failure, true
+	}
+
+	return failure, false
+}
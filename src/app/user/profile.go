@@ -0,0 +1,161 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"test-edot/constants"
+	"test-edot/src/dto"
+	"test-edot/src/models"
+	"test-edot/src/tracing"
+	"test-edot/util"
+	"time"
+)
+
+const profileFields = "id,full_name,role,is_active,email,phone,email_verified_at,phone_verified_at,totp_enabled_at,created_at,updated_at"
+
+func (s service) GetProfile(ctx context.Context, userClaim dto.UserClaimJwt) (models.User, error) {
+	ctx, span := tracing.Start(ctx, "user.GetProfile")
+	defer span.End()
+
+	user, err := s.UserRepository.FindOne(ctx, profileFields, "id = ? and is_active = 1", userClaim.UserId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.User{}, constants.UserNotFound
+		}
+
+		s.log(ctx).Error("error get user", zap.Error(err))
+		return models.User{}, err
+	}
+
+	return user, nil
+}
+
+// UpdateProfile a new phone number has to be verified again
+func (s service) UpdateProfile(ctx context.Context, userClaim dto.UserClaimJwt, payload dto.PayloadUpdateProfile) (models.User, error) {
+	ctx, span := tracing.Start(ctx, "user.UpdateProfile")
+	defer span.End()
+
+	if err := validatePhone(payload.Phone); err != nil {
+		return models.User{}, err
+	}
+
+	user, err := s.GetProfile(ctx, userClaim)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	duplicate, err := s.UserRepository.FindOne(ctx, "id", "phone = ? and id <> ?", payload.Phone, user.Id)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		s.log(ctx).Error("error get user", zap.Error(err))
+		return models.User{}, err
+	}
+
+	if duplicate.Id != 0 {
+		return models.User{}, constants.PhoneAlreadyUsed
+	}
+
+	if payload.Phone != user.Phone {
+		user.PhoneVerifiedAt = nil
+	}
+
+	user.FullName = payload.FullName
+	user.Phone = payload.Phone
+	user.UpdatedAt = time.Now().In(util.LocationTime)
+	if err := s.UserRepository.Update(ctx, &user, "full_name,phone,phone_verified_at,updated_at", "id = ?", user.Id); err != nil {
+		s.log(ctx).Error("error update user", zap.Error(err))
+		return models.User{}, err
+	}
+
+	return user, nil
+}
+
+// ChangePassword logs out every session of the user, including the one that changed the password
+func (s service) ChangePassword(ctx context.Context, userClaim dto.UserClaimJwt, payload dto.PayloadChangePassword) error {
+	ctx, span := tracing.Start(ctx, "user.ChangePassword")
+	defer span.End()
+
+	user, err := s.UserRepository.FindOne(ctx, "id,password", "id = ? and is_active = 1", userClaim.UserId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return constants.UserNotFound
+		}
+
+		s.log(ctx).Error("error get user", zap.Error(err))
+		return err
+	}
+
+	if !s.checkPasswordHash(payload.CurrentPassword, user.Password) {
+		return constants.InvalidPassword
+	}
+
+	if payload.CurrentPassword == payload.NewPassword {
+		return constants.PasswordNotChanged
+	}
+
+	passwordHashed, err := util.HashPassword(payload.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	updated := models.User{Password: passwordHashed, UpdatedAt: time.Now().In(util.LocationTime)}
+	if err := s.updateAndRevokeSessions(ctx, user.Id, &updated, "password,updated_at"); err != nil {
+		return err
+	}
+
+	s.log(ctx).Info("user password changed", zap.Int("userId", user.Id))
+	return nil
+}
+
+// Deactivate disables the account and logs out every session, the data of the user is kept
+func (s service) Deactivate(ctx context.Context, userClaim dto.UserClaimJwt) error {
+	ctx, span := tracing.Start(ctx, "user.Deactivate")
+	defer span.End()
+
+	user, err := s.GetProfile(ctx, userClaim)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().In(util.LocationTime)
+	updated := models.User{IsActive: false, DeactivatedAt: &now, UpdatedAt: now}
+	if err := s.updateAndRevokeSessions(ctx, user.Id, &updated, "is_active,deactivated_at,updated_at"); err != nil {
+		return err
+	}
+
+	s.log(ctx).Info("user deactivated", zap.Int("userId", user.Id))
+	return nil
+}
+
+func (s service) updateAndRevokeSessions(ctx context.Context, userId int, updated *models.User, selectFields string) error {
+	tx := s.RefreshTokenRepository.Begin().WithContext(ctx)
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		s.log(ctx).Error("error begin transaction", zap.Error(err))
+		return err
+	}
+
+	if err := s.UserRepository.UpdateTx(tx, updated, selectFields, "id = ?", userId); err != nil {
+		tx.Rollback()
+		s.log(ctx).Error("error update user", zap.Error(err))
+		return err
+	}
+
+	if err := s.RevokeUserSessionsTx(tx, userId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		s.log(ctx).Error("error commit transaction", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
@@ -1,12 +1,31 @@
 package user
 
-import "github.com/gin-gonic/gin"
+import (
+	"github.com/gin-gonic/gin"
+	"test-edot/constants"
+	"test-edot/src/middleware"
+)
 
 func (h *handler) UserRouter(g *gin.RouterGroup) {
 	g.POST("register", h.RegisterUser)
-	g.POST("login", h.LoginUser)
+	g.POST("login", middleware.RateLimit(constants.RATE_LIMIT_GROUP_LOGIN), h.LoginUser)
+	g.POST("login/2fa", middleware.RateLimit(constants.RATE_LIMIT_GROUP_LOGIN), h.LoginTwoFactor)
+	g.POST("refresh", h.RefreshToken)
+	g.POST("logout", middleware.Authorize(constants.PERMISSION_PROFILE_READ), h.Logout)
+	g.GET("me", middleware.Authorize(constants.PERMISSION_PROFILE_READ), h.UserMe)
+	g.PUT("me", middleware.Authorize(constants.PERMISSION_PROFILE_WRITE), h.UpdateUserMe)
+	g.PUT("me/password", middleware.Authorize(constants.PERMISSION_PROFILE_WRITE), h.ChangePassword)
+	g.DELETE("me", middleware.Authorize(constants.PERMISSION_PROFILE_WRITE), h.DeactivateUserMe)
+	g.POST("me/2fa/enroll", middleware.Authorize(constants.PERMISSION_PROFILE_WRITE), h.EnrollTwoFactor)
+	g.POST("me/2fa/confirm", middleware.Authorize(constants.PERMISSION_PROFILE_WRITE), h.ConfirmTwoFactor)
+	g.POST("me/2fa/disable", middleware.Authorize(constants.PERMISSION_PROFILE_WRITE), h.DisableTwoFactor)
+	g.POST("me/2fa/recovery-codes", middleware.Authorize(constants.PERMISSION_PROFILE_WRITE), h.RegenerateRecoveryCodes)
+	g.POST("verification/request", middleware.Authorize(constants.PERMISSION_PROFILE_READ), h.RequestVerification)
+	g.POST("verification/confirm", middleware.Authorize(constants.PERMISSION_PROFILE_READ), h.ConfirmVerification)
+	g.POST("password/forgot", middleware.RateLimit(constants.RATE_LIMIT_GROUP_LOGIN), h.ForgotPassword)
+	g.POST("password/reset", middleware.RateLimit(constants.RATE_LIMIT_GROUP_LOGIN), h.ResetPassword)
 }
 
-func (h *handler) UserBearerRouter(g *gin.RouterGroup) {
-	g.GET("me", h.UserMe)
+func (h *handler) JwksRouter(g *gin.RouterGroup) {
+	g.GET("jwks.json", h.Jwks)
 }
@@ -11,29 +11,69 @@ import (
 	"test-edot/src/dto"
 	"test-edot/src/factory"
 	"test-edot/src/models"
+	"test-edot/src/notifier"
 	"test-edot/src/repository"
+	"test-edot/src/tracing"
 	"test-edot/util"
 	"time"
 )
 
 type Service interface {
 	Register(ctx context.Context, user dto.RegisterUser) (models.User, error)
-	Login(ctx context.Context, payload dto.LoginUser) (string, error)
+	Login(ctx context.Context, payload dto.LoginUser, clientIp string) (dto.ResponseToken, error)
+	Refresh(ctx context.Context, payload dto.PayloadRefreshToken) (dto.ResponseToken, error)
+	Logout(ctx context.Context, userClaim dto.UserClaimJwt, payload dto.PayloadLogout) error
+	GetProfile(ctx context.Context, userClaim dto.UserClaimJwt) (models.User, error)
+	UpdateProfile(ctx context.Context, userClaim dto.UserClaimJwt, payload dto.PayloadUpdateProfile) (models.User, error)
+	ChangePassword(ctx context.Context, userClaim dto.UserClaimJwt, payload dto.PayloadChangePassword) error
+	Deactivate(ctx context.Context, userClaim dto.UserClaimJwt) error
+	RequestVerification(ctx context.Context, userClaim dto.UserClaimJwt, payload dto.PayloadRequestVerification) error
+	ConfirmVerification(ctx context.Context, userClaim dto.UserClaimJwt, payload dto.PayloadConfirmVerification) error
+	ForgotPassword(ctx context.Context, payload dto.PayloadForgotPassword) error
+	ResetPassword(ctx context.Context, payload dto.PayloadResetPassword) error
+	CleanupTokens()
+	Jwks(ctx context.Context) (util.JWKS, error)
+	EnrollTwoFactor(ctx context.Context, userClaim dto.UserClaimJwt) (dto.ResponseTwoFactorEnroll, error)
+	ConfirmTwoFactor(ctx context.Context, userClaim dto.UserClaimJwt, payload dto.PayloadTwoFactorCode) (dto.ResponseRecoveryCodes, error)
+	DisableTwoFactor(ctx context.Context, userClaim dto.UserClaimJwt, payload dto.PayloadDisableTwoFactor) error
+	RegenerateRecoveryCodes(ctx context.Context, userClaim dto.UserClaimJwt, payload dto.PayloadTwoFactorCode) (dto.ResponseRecoveryCodes, error)
+	LoginTwoFactor(ctx context.Context, payload dto.PayloadLoginTwoFactor, clientIp string) (dto.ResponseToken, error)
 }
 
 type service struct {
-	Log            *zap.Logger
-	UserRepository repository.UserRepositoryInterface
+	Log                        *zap.Logger
+	Notifier                   notifier.Notifier
+	UserRepository             repository.UserRepositoryInterface
+	RefreshTokenRepository     repository.RefreshTokenRepositoryInterface
+	RevokedTokenRepository     repository.RevokedTokenRepositoryInterface
+	UserVerificationRepository repository.UserVerificationRepositoryInterface
+	LoginFailureRepository     repository.LoginFailureRepositoryInterface
+	AuditEventRepository       repository.AuditEventRepositoryInterface
+	UserRecoveryCodeRepository repository.UserRecoveryCodeRepositoryInterface
+	LoginChallengeRepository   repository.LoginChallengeRepositoryInterface
 }
 
 func NewService(f *factory.Factory) Service {
 	return &service{
-		Log: f.Log,
-
-		UserRepository: f.UserRepository,
+		Log:      f.Log,
+		Notifier: f.Notifier,
+
+		UserRepository:             f.UserRepository,
+		RefreshTokenRepository:     f.RefreshTokenRepository,
+		RevokedTokenRepository:     f.RevokedTokenRepository,
+		UserVerificationRepository: f.UserVerificationRepository,
+		LoginFailureRepository:     f.LoginFailureRepository,
+		AuditEventRepository:       f.AuditEventRepository,
+		UserRecoveryCodeRepository: f.UserRecoveryCodeRepository,
+		LoginChallengeRepository:   f.LoginChallengeRepository,
 	}
 }
 
+// log is the logger of the request that made the call, see middleware.RequestId
+func (s service) log(ctx context.Context) *zap.Logger {
+	return util.Logger(ctx, s.Log)
+}
+
 func (s service) validateRegister(user dto.RegisterUser) error {
 	if !constants.MapRoleAvail[user.Role] {
 		return constants.RolePayloadInvalid
@@ -44,8 +84,12 @@ func (s service) validateRegister(user dto.RegisterUser) error {
 		return constants.FormatEmailInvalid
 	}
 
+	return validatePhone(user.Phone)
+}
+
+func validatePhone(phone string) error {
 	rePhone := regexp.MustCompile(`^[0-9]{8,15}$`)
-	if !rePhone.MatchString(user.Phone) {
+	if !rePhone.MatchString(phone) {
 		return constants.FormatPhoneInvalid
 	}
 
@@ -57,29 +101,10 @@ func (s service) checkPasswordHash(password, hashedPassword string) bool {
 	return err == nil
 }
 
-func (s service) Login(ctx context.Context, payload dto.LoginUser) (string, error) {
-	userTrack, err := s.UserRepository.FindOne(ctx, "id,role,email,phone,password", "email = ? or phone = ?", payload.Email, payload.Email)
-	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-		return "", err
-	}
-
-	if userTrack == (models.User{}) {
-		return "", constants.UserNotFound
-	}
-
-	if !s.checkPasswordHash(payload.Password, userTrack.Password) {
-		return "", constants.InvalidPassword
-	}
-
-	token, err := util.GenerateJWT(userTrack)
-	if err != nil {
-		return "", err
-	}
-
-	return token, nil
-}
-
 func (s service) Register(ctx context.Context, user dto.RegisterUser) (models.User, error) {
+	ctx, span := tracing.Start(ctx, "user.Register")
+	defer span.End()
+
 	userTrack, err := s.UserRepository.FindOne(ctx, "email,phone", "email = ? or phone = ?", user.Email, user.Phone)
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		return models.User{}, err
@@ -102,13 +127,14 @@ func (s service) Register(ctx context.Context, user dto.RegisterUser) (models.Us
 		FullName:  user.FullName,
 		Password:  passwordHashed,
 		Role:      user.Role,
+		IsActive:  true,
 		Email:     user.Email,
 		Phone:     user.Phone,
 		CreatedAt: time.Now().In(util.LocationTime),
 		UpdatedAt: time.Now().In(util.LocationTime),
 	}
 	if err := s.UserRepository.Create(ctx, &userData); err != nil {
-		s.Log.Error("error creating user", zap.Error(err))
+		s.log(ctx).Error("error creating user", zap.Error(err))
 		return models.User{}, err
 	}
 
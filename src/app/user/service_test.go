@@ -0,0 +1,577 @@
+package user
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"test-edot/constants"
+	"test-edot/src/dto"
+	"test-edot/src/models"
+	"test-edot/src/repository/mocks"
+	"test-edot/util"
+	"testing"
+	"time"
+)
+
+type (
+	TestProcessRefreshData struct {
+		name          string
+		token         models.RefreshToken
+		findErr       error
+		expectErr     error
+		expectRevoked bool
+	}
+)
+
+func TestProcessRefreshTx(t *testing.T) {
+	t.Setenv("JWT_SECRET_KEY", "secret-test")
+	now := time.Now().In(util.LocationTime)
+
+	tableTests := []TestProcessRefreshData{
+		{
+			name:  "test rotate refresh token",
+			token: models.RefreshToken{ID: 1, UserId: 1, FamilyId: "family", ExpiredAt: now.Add(time.Hour)},
+		},
+		{
+			name:          "test reuse rotated refresh token",
+			token:         models.RefreshToken{ID: 1, UserId: 1, FamilyId: "family", ExpiredAt: now.Add(time.Hour), RotatedAt: &now},
+			expectErr:     constants.RefreshTokenReused,
+			expectRevoked: true,
+		},
+		{
+			name:      "test expired refresh token",
+			token:     models.RefreshToken{ID: 1, UserId: 1, FamilyId: "family", ExpiredAt: now.Add(-time.Hour)},
+			expectErr: constants.RefreshTokenInvalid,
+		},
+		{
+			name:      "test unknown refresh token",
+			findErr:   gorm.ErrRecordNotFound,
+			expectErr: constants.RefreshTokenInvalid,
+		},
+	}
+
+	for _, test := range tableTests {
+		t.Run(test.name, func(t *testing.T) {
+			var created models.RefreshToken
+			ctx := context.Background()
+			tx := gorm.DB{}
+			mockRefreshRepo := new(mocks.RefreshTokenRepositoryInterface)
+			mockRevokedRepo := new(mocks.RevokedTokenRepositoryInterface)
+			mockUserRepo := new(mocks.UserRepositoryInterface)
+
+			mockRefreshRepo.On("FindOneTx", &tx, "*", "token_hash = ?", util.HashToken("refresh")).Return(test.token, test.findErr)
+			mockRefreshRepo.On("FindTx", &tx, mock.Anything, "family_id = ? and revoked_at is null", "family").
+				Return([]models.RefreshToken{{ID: 1, UserId: 1, AccessJti: "jti-1", CreatedAt: now}, {ID: 2, UserId: 1, AccessJti: "jti-2", CreatedAt: now}}, nil)
+			mockRefreshRepo.On("UpdateTx", &tx, mock.AnythingOfType("*models.RefreshToken"), mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			mockRefreshRepo.On("Create", &tx, mock.AnythingOfType("*models.RefreshToken")).
+				Run(func(args mock.Arguments) { created = *args.Get(1).(*models.RefreshToken) }).Return(nil)
+			mockRevokedRepo.On("CreateTx", &tx, mock.AnythingOfType("*models.RevokedToken")).Return(nil)
+			mockUserRepo.On("FindOne", ctx, "id,role", "id = ? and is_active = 1", 1).Return(models.User{Id: 1, Role: constants.ROLE_USER}, nil)
+
+			s := service{Log: zap.NewNop(), UserRepository: mockUserRepo, RefreshTokenRepository: mockRefreshRepo, RevokedTokenRepository: mockRevokedRepo}
+
+			res, err := s.ProcessRefreshTx(ctx, &tx, dto.PayloadRefreshToken{RefreshToken: "refresh"})
+			if test.expectErr != nil {
+				assert.ErrorIs(t, err, test.expectErr)
+				mockRefreshRepo.AssertNotCalled(t, "Create", &tx, mock.Anything)
+			} else {
+				assert.NoError(t, err)
+				assert.NotEqual(t, "refresh", res.RefreshToken)
+				assert.Equal(t, util.HashToken(res.RefreshToken), created.TokenHash)
+				assert.Equal(t, test.token.FamilyId, created.FamilyId)
+			}
+
+			if test.expectRevoked {
+				mockRevokedRepo.AssertNumberOfCalls(t, "CreateTx", 2)
+			} else {
+				mockRevokedRepo.AssertNotCalled(t, "CreateTx", &tx, mock.Anything)
+			}
+		})
+	}
+}
+
+type (
+	TestCheckCodeData struct {
+		name           string
+		verification   models.UserVerification
+		findErr        error
+		code           string
+		expectErr      error
+		expectAttempts int
+		expectUsed     bool
+	}
+)
+
+func TestCheckCodeTx(t *testing.T) {
+	now := time.Now().In(util.LocationTime)
+	hash := verificationHash(1, constants.VERIFICATION_EMAIL, "123456")
+
+	tableTests := []TestCheckCodeData{
+		{
+			name:         "test valid code",
+			verification: models.UserVerification{ID: 1, UserId: 1, CodeHash: hash, ExpiredAt: now.Add(time.Minute)},
+			code:         "123456",
+			expectUsed:   true,
+		},
+		{
+			name:           "test wrong code count attempt",
+			verification:   models.UserVerification{ID: 1, UserId: 1, CodeHash: hash, Attempts: 2, ExpiredAt: now.Add(time.Minute)},
+			code:           "654321",
+			expectErr:      constants.VerificationCodeInvalid,
+			expectAttempts: 3,
+		},
+		{
+			name:         "test code locked after max attempts",
+			verification: models.UserVerification{ID: 1, UserId: 1, CodeHash: hash, Attempts: constants.OTP_MAX_ATTEMPTS, ExpiredAt: now.Add(time.Minute)},
+			code:         "123456",
+			expectErr:    constants.VerificationMaxAttempts,
+		},
+		{
+			name:         "test expired code",
+			verification: models.UserVerification{ID: 1, UserId: 1, CodeHash: hash, ExpiredAt: now.Add(-time.Minute)},
+			code:         "123456",
+			expectErr:    constants.VerificationCodeInvalid,
+		},
+		{
+			name:      "test code never requested",
+			findErr:   gorm.ErrRecordNotFound,
+			code:      "123456",
+			expectErr: constants.VerificationCodeInvalid,
+		},
+	}
+
+	for _, test := range tableTests {
+		t.Run(test.name, func(t *testing.T) {
+			var updated models.UserVerification
+			tx := gorm.DB{}
+			mockVerificationRepo := new(mocks.UserVerificationRepositoryInterface)
+
+			mockVerificationRepo.On("FindOneTx", &tx, "*", "user_id = ? and purpose = ? and used_at is null", 1, constants.VERIFICATION_EMAIL).Return(test.verification, test.findErr)
+			mockVerificationRepo.On("UpdateTx", &tx, mock.AnythingOfType("*models.UserVerification"), mock.Anything, "id = ?", 1).
+				Run(func(args mock.Arguments) { updated = *args.Get(1).(*models.UserVerification) }).Return(nil)
+
+			s := service{Log: zap.NewNop(), UserVerificationRepository: mockVerificationRepo}
+
+			_, err := s.CheckCodeTx(&tx, 1, constants.VERIFICATION_EMAIL, test.code)
+			if test.expectErr != nil {
+				assert.ErrorIs(t, err, test.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, test.expectAttempts, updated.Attempts)
+			assert.Equal(t, test.expectUsed, updated.UsedAt != nil)
+		})
+	}
+}
+
+type (
+	TestLoginBlockedUntilData struct {
+		name          string
+		failure       models.LoginFailure
+		expectBlocked time.Duration
+	}
+
+	TestNextLoginFailureData struct {
+		name        string
+		failure     models.LoginFailure
+		expectCount int
+		expectLock  bool
+	}
+
+	TestCheckLoginLockData struct {
+		name           string
+		accountFailure models.LoginFailure
+		ipFailure      models.LoginFailure
+		expectErr      error
+	}
+
+	TestLoginScopesData struct {
+		name             string
+		user             models.User
+		clientIp         string
+		expectIdentifier string
+		expectScopes     int
+	}
+)
+
+func TestLoginBlockedUntil(t *testing.T) {
+	now := time.Now().In(util.LocationTime)
+	lastFailed := now.Add(-time.Second)
+	lockedUntil := now.Add(time.Minute)
+	lockEnded := now.Add(-time.Minute)
+
+	tableTests := []TestLoginBlockedUntilData{
+		{
+			name:    "test few failures not delayed",
+			failure: models.LoginFailure{FailedCount: constants.LOGIN_DELAY_AFTER - 1, LastFailedAt: &lastFailed},
+		},
+		{
+			name:          "test delay doubles after each failure",
+			failure:       models.LoginFailure{FailedCount: constants.LOGIN_DELAY_AFTER + 2, LastFailedAt: &lastFailed},
+			expectBlocked: 3 * time.Second,
+		},
+		{
+			name:          "test delay capped",
+			failure:       models.LoginFailure{FailedCount: constants.LOGIN_DELAY_AFTER + 40, LastFailedAt: &lastFailed},
+			expectBlocked: constants.LOGIN_MAX_DELAY - time.Second,
+		},
+		{
+			name:          "test locked account",
+			failure:       models.LoginFailure{FailedCount: 1, LastFailedAt: &lastFailed, LockedUntil: &lockedUntil},
+			expectBlocked: time.Minute,
+		},
+		{
+			name:    "test ended lockout",
+			failure: models.LoginFailure{FailedCount: constants.LOGIN_ACCOUNT_MAX_FAILURES, LastFailedAt: &lockEnded, LockedUntil: &lockEnded},
+		},
+	}
+
+	for _, test := range tableTests {
+		t.Run(test.name, func(t *testing.T) {
+			blockedUntil := LoginBlockedUntil(test.failure, now)
+			if test.expectBlocked == 0 {
+				assert.True(t, blockedUntil.IsZero())
+			} else {
+				assert.Equal(t, test.expectBlocked, blockedUntil.Sub(now))
+			}
+		})
+	}
+}
+
+func TestNextLoginFailure(t *testing.T) {
+	now := time.Now().In(util.LocationTime)
+	recent := now.Add(-time.Minute)
+	old := now.Add(-constants.LOGIN_FAILURE_WINDOW - time.Minute)
+
+	tableTests := []TestNextLoginFailureData{
+		{
+			name:        "test first failure",
+			failure:     models.LoginFailure{},
+			expectCount: 1,
+		},
+		{
+			name:        "test failure inside window",
+			failure:     models.LoginFailure{FailedCount: 4, LastFailedAt: &recent},
+			expectCount: 5,
+		},
+		{
+			name:        "test failure after window start again",
+			failure:     models.LoginFailure{FailedCount: 9, LastFailedAt: &old},
+			expectCount: 1,
+		},
+		{
+			name:        "test failure reach max lock",
+			failure:     models.LoginFailure{FailedCount: constants.LOGIN_ACCOUNT_MAX_FAILURES - 1, LastFailedAt: &recent},
+			expectCount: constants.LOGIN_ACCOUNT_MAX_FAILURES,
+			expectLock:  true,
+		},
+	}
+
+	for _, test := range tableTests {
+		t.Run(test.name, func(t *testing.T) {
+			failure, locked := NextLoginFailure(test.failure, constants.LOGIN_ACCOUNT_MAX_FAILURES, now)
+
+			assert.Equal(t, test.expectCount, failure.FailedCount)
+			assert.Equal(t, test.expectLock, locked)
+			assert.Equal(t, test.expectLock, failure.LockedUntil != nil)
+		})
+	}
+}
+
+func TestCheckLoginLock(t *testing.T) {
+	now := time.Now().In(util.LocationTime)
+	lastFailed := now.Add(-time.Second)
+	lockedUntil := now.Add(time.Minute)
+
+	tableTests := []TestCheckLoginLockData{
+		{
+			name: "test no failure",
+		},
+		{
+			name:           "test locked account",
+			accountFailure: models.LoginFailure{ID: 1, FailedCount: constants.LOGIN_ACCOUNT_MAX_FAILURES, LastFailedAt: &lastFailed, LockedUntil: &lockedUntil},
+			expectErr:      constants.LoginLocked,
+		},
+		{
+			name:      "test ip waiting for progressive delay",
+			ipFailure: models.LoginFailure{ID: 2, FailedCount: constants.LOGIN_DELAY_AFTER + 3, LastFailedAt: &lastFailed},
+			expectErr: constants.LoginLocked,
+		},
+	}
+
+	for _, test := range tableTests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			mockFailureRepo := new(mocks.LoginFailureRepositoryInterface)
+
+			for scope, failure := range map[string]models.LoginFailure{constants.LOGIN_SCOPE_ACCOUNT: test.accountFailure, constants.LOGIN_SCOPE_IP: test.ipFailure} {
+				var findErr error
+				if failure.ID == 0 {
+					findErr = gorm.ErrRecordNotFound
+				}
+				mockFailureRepo.On("FindOne", ctx, "*", "scope = ? and identifier = ?", scope, mock.Anything).Return(failure, findErr)
+			}
+
+			s := service{Log: zap.NewNop(), LoginFailureRepository: mockFailureRepo}
+
+			err := s.CheckLoginLock(ctx, loginScopes(models.User{Id: 1}, "user@mail.com", "127.0.0.1"))
+			if test.expectErr != nil {
+				assert.ErrorIs(t, err, test.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestLoginScopes(t *testing.T) {
+
+	tableTests := []TestLoginScopesData{
+		{
+			name:             "test known account counted by user id",
+			user:             models.User{Id: 7},
+			clientIp:         "127.0.0.1",
+			expectIdentifier: "user:7",
+			expectScopes:     2,
+		},
+		{
+			name:             "test unknown account counted by identifier",
+			clientIp:         "127.0.0.1",
+			expectIdentifier: "user@mail.com",
+			expectScopes:     2,
+		},
+		{
+			name:             "test without client ip",
+			user:             models.User{Id: 7},
+			expectIdentifier: "user:7",
+			expectScopes:     1,
+		},
+	}
+
+	for _, test := range tableTests {
+		t.Run(test.name, func(t *testing.T) {
+			scopes := loginScopes(test.user, " User@Mail.com", test.clientIp)
+
+			assert.Equal(t, test.expectScopes, len(scopes))
+			assert.Equal(t, constants.LOGIN_SCOPE_ACCOUNT, scopes[0].Scope)
+			assert.Equal(t, test.expectIdentifier, scopes[0].Identifier)
+		})
+	}
+}
+
+type (
+	TestUpdateProfileData struct {
+		name           string
+		payload        dto.PayloadUpdateProfile
+		duplicate      models.User
+		expectErr      error
+		expectVerified bool
+	}
+)
+
+func TestUpdateProfile(t *testing.T) {
+	verifiedAt := time.Now().In(util.LocationTime)
+
+	tableTests := []TestUpdateProfileData{
+		{
+			name:           "test update full name keep phone verification",
+			payload:        dto.PayloadUpdateProfile{FullName: "new name", Phone: "08123456789"},
+			expectVerified: true,
+		},
+		{
+			name:    "test change phone must verify again",
+			payload: dto.PayloadUpdateProfile{FullName: "new name", Phone: "08987654321"},
+		},
+		{
+			name:      "test phone used by another user",
+			payload:   dto.PayloadUpdateProfile{FullName: "new name", Phone: "08987654321"},
+			duplicate: models.User{Id: 2},
+			expectErr: constants.PhoneAlreadyUsed,
+		},
+		{
+			name:      "test invalid phone format",
+			payload:   dto.PayloadUpdateProfile{FullName: "new name", Phone: "08-123"},
+			expectErr: constants.FormatPhoneInvalid,
+		},
+	}
+
+	for _, test := range tableTests {
+		t.Run(test.name, func(t *testing.T) {
+			var updated models.User
+			ctx := context.Background()
+			mockUserRepo := new(mocks.UserRepositoryInterface)
+
+			duplicateErr := error(nil)
+			if test.duplicate.Id == 0 {
+				duplicateErr = gorm.ErrRecordNotFound
+			}
+			mockUserRepo.On("FindOne", ctx, profileFields, "id = ? and is_active = 1", 1).
+				Return(models.User{Id: 1, FullName: "name", Phone: "08123456789", IsActive: true, PhoneVerifiedAt: &verifiedAt}, nil)
+			mockUserRepo.On("FindOne", ctx, "id", "phone = ? and id <> ?", test.payload.Phone, 1).Return(test.duplicate, duplicateErr)
+			mockUserRepo.On("Update", ctx, mock.AnythingOfType("*models.User"), "full_name,phone,phone_verified_at,updated_at", "id = ?", 1).
+				Run(func(args mock.Arguments) { updated = *args.Get(1).(*models.User) }).Return(nil)
+
+			s := service{Log: zap.NewNop(), UserRepository: mockUserRepo}
+
+			user, err := s.UpdateProfile(ctx, dto.UserClaimJwt{UserId: 1}, test.payload)
+			if test.expectErr != nil {
+				assert.ErrorIs(t, err, test.expectErr)
+				mockUserRepo.AssertNotCalled(t, "Update", ctx, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.payload.FullName, updated.FullName)
+			assert.Equal(t, test.payload.Phone, user.Phone)
+			assert.Equal(t, test.expectVerified, updated.PhoneVerifiedAt != nil)
+		})
+	}
+}
+
+type (
+	TestVerifySecondFactorData struct {
+		name             string
+		code             string
+		lastStep         int64
+		recoveryErr      error
+		expectErr        error
+		expectStep       bool
+		expectRecoveryOk bool
+	}
+)
+
+func TestVerifySecondFactorTx(t *testing.T) {
+	secret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+	now := time.Now().In(util.LocationTime)
+	currentCode, _ := util.TOTPCode(secret, util.TOTPStep(now))
+
+	tableTests := []TestVerifySecondFactorData{
+		{
+			name:       "test valid totp code",
+			code:       currentCode,
+			expectStep: true,
+		},
+		{
+			name:      "test replayed totp code",
+			code:      currentCode,
+			lastStep:  util.TOTPStep(now) + 1,
+			expectErr: constants.TwoFactorCodeInvalid,
+		},
+		{
+			name:             "test valid recovery code",
+			code:             "ABCDE-12345",
+			expectRecoveryOk: true,
+		},
+		{
+			name:        "test used recovery code",
+			code:        "abcde-12345",
+			recoveryErr: gorm.ErrRecordNotFound,
+			expectErr:   constants.TwoFactorCodeInvalid,
+		},
+	}
+
+	for _, test := range tableTests {
+		t.Run(test.name, func(t *testing.T) {
+			var updatedUser models.User
+			var usedRecovery models.UserRecoveryCode
+			tx := gorm.DB{}
+			mockUserRepo := new(mocks.UserRepositoryInterface)
+			mockRecoveryRepo := new(mocks.UserRecoveryCodeRepositoryInterface)
+
+			mockUserRepo.On("UpdateTx", &tx, mock.AnythingOfType("*models.User"), "totp_last_step", "id = ?", 1).
+				Run(func(args mock.Arguments) { updatedUser = *args.Get(1).(*models.User) }).Return(nil)
+			mockRecoveryRepo.On("FindOneTx", &tx, "id", "user_id = ? and code_hash = ? and used_at is null", 1, recoveryCodeHash(1, "abcde12345")).
+				Return(models.UserRecoveryCode{ID: 7, UserId: 1}, test.recoveryErr)
+			mockRecoveryRepo.On("UpdateTx", &tx, mock.AnythingOfType("*models.UserRecoveryCode"), "used_at", "id = ?", 7).
+				Run(func(args mock.Arguments) { usedRecovery = *args.Get(1).(*models.UserRecoveryCode) }).Return(nil)
+
+			s := service{Log: zap.NewNop(), UserRepository: mockUserRepo, UserRecoveryCodeRepository: mockRecoveryRepo}
+
+			user := models.User{Id: 1, TotpSecret: &secret, TotpLastStep: test.lastStep}
+			err := s.VerifySecondFactorTx(&tx, user, test.code)
+			if test.expectErr != nil {
+				assert.ErrorIs(t, err, test.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, test.expectStep, updatedUser.TotpLastStep > test.lastStep)
+			assert.Equal(t, test.expectRecoveryOk, usedRecovery.UsedAt != nil)
+		})
+	}
+}
+
+type (
+	TestProcessChallengeData struct {
+		name           string
+		accountFailure models.LoginFailure
+		ipFailure      models.LoginFailure
+		expectErr      error
+		expectScopes   []loginScope
+	}
+)
+
+func TestProcessChallengeTx(t *testing.T) {
+	secret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+	now := time.Now().In(util.LocationTime)
+	lastFailed := now.Add(-time.Second)
+	lockedUntil := now.Add(time.Minute)
+
+	tableTests := []TestProcessChallengeData{
+		{
+			name:      "test wrong code is a failure of the account and the ip",
+			expectErr: constants.TwoFactorCodeInvalid,
+			expectScopes: []loginScope{
+				{Scope: constants.LOGIN_SCOPE_ACCOUNT, Identifier: "user:1"},
+				{Scope: constants.LOGIN_SCOPE_IP, Identifier: "10.0.0.9"},
+			},
+		},
+		{
+			name:           "test account locked by wrong codes of earlier challenges",
+			accountFailure: models.LoginFailure{ID: 1, FailedCount: constants.LOGIN_ACCOUNT_MAX_FAILURES, LastFailedAt: &lastFailed, LockedUntil: &lockedUntil},
+			expectErr:      constants.LoginLocked,
+		},
+		{
+			name:      "test ip locked",
+			ipFailure: models.LoginFailure{ID: 2, FailedCount: constants.LOGIN_IP_MAX_FAILURES, LastFailedAt: &lastFailed, LockedUntil: &lockedUntil},
+			expectErr: constants.LoginLocked,
+		},
+	}
+
+	for _, test := range tableTests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			tx := gorm.DB{}
+			mockChallengeRepo := new(mocks.LoginChallengeRepositoryInterface)
+			mockUserRepo := new(mocks.UserRepositoryInterface)
+			mockFailureRepo := new(mocks.LoginFailureRepositoryInterface)
+
+			mockChallengeRepo.On("FindOneTx", &tx, "*", "token_hash = ? and used_at is null", util.HashToken("challenge")).
+				Return(models.LoginChallenge{ID: 3, UserId: 1, ExpiredAt: now.Add(time.Minute)}, nil)
+			mockChallengeRepo.On("UpdateTx", &tx, &models.LoginChallenge{Attempts: 1}, "attempts", "id = ?", 3).Return(nil)
+			mockUserRepo.On("FindOne", ctx, twoFactorFields, "id = ? and is_active = 1", 1).
+				Return(models.User{Id: 1, IsActive: true, TotpSecret: &secret, TotpEnabledAt: &now}, nil)
+			for scope, failure := range map[string]models.LoginFailure{constants.LOGIN_SCOPE_ACCOUNT: test.accountFailure, constants.LOGIN_SCOPE_IP: test.ipFailure} {
+				var findErr error
+				if failure.ID == 0 {
+					findErr = gorm.ErrRecordNotFound
+				}
+				mockFailureRepo.On("FindOne", ctx, "*", "scope = ? and identifier = ?", scope, mock.Anything).Return(failure, findErr)
+			}
+
+			s := service{Log: zap.NewNop(), UserRepository: mockUserRepo, LoginChallengeRepository: mockChallengeRepo, LoginFailureRepository: mockFailureRepo}
+
+			_, scopes, err := s.ProcessChallengeTx(ctx, &tx, dto.PayloadLoginTwoFactor{ChallengeToken: "challenge", Code: "12345"}, "10.0.0.9")
+			assert.ErrorIs(t, err, test.expectErr)
+			assert.Equal(t, test.expectScopes, scopes)
+			if test.expectErr == constants.LoginLocked {
+				mockChallengeRepo.AssertNotCalled(t, "UpdateTx", &tx, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			} else {
+				mockChallengeRepo.AssertCalled(t, "UpdateTx", &tx, &models.LoginChallenge{Attempts: 1}, "attempts", "id = ?", 3)
+			}
+		})
+	}
+}
@@ -0,0 +1,326 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"test-edot/constants"
+	"test-edot/src/dto"
+	"test-edot/src/models"
+	"test-edot/src/tracing"
+	"test-edot/util"
+	"time"
+)
+
+// StartSession issues the first access and refresh token of a new token family
+func (s service) StartSession(ctx context.Context, user models.User) (dto.ResponseToken, error) {
+	familyId, err := util.GenerateRandomToken(16)
+	if err != nil {
+		return dto.ResponseToken{}, err
+	}
+
+	tx := s.RefreshTokenRepository.Begin().WithContext(ctx)
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		s.log(ctx).Error("error begin transaction", zap.Error(err))
+		return dto.ResponseToken{}, err
+	}
+
+	token, err := s.IssueTokenTx(tx, user, familyId)
+	if err != nil {
+		tx.Rollback()
+		return dto.ResponseToken{}, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		s.log(ctx).Error("error commit transaction", zap.Error(err))
+		return dto.ResponseToken{}, err
+	}
+
+	return token, nil
+}
+
+// IssueTokenTx signs an access token and stores the hash of a new refresh token in the family
+func (s service) IssueTokenTx(tx *gorm.DB, user models.User, familyId string) (dto.ResponseToken, error) {
+	jti, err := util.GenerateRandomToken(16)
+	if err != nil {
+		return dto.ResponseToken{}, err
+	}
+
+	accessToken, err := util.GenerateJWT(user, jti)
+	if err != nil {
+		return dto.ResponseToken{}, err
+	}
+
+	refreshToken, err := util.GenerateRandomToken(32)
+	if err != nil {
+		return dto.ResponseToken{}, err
+	}
+
+	now := time.Now().In(util.LocationTime)
+	stored := models.RefreshToken{
+		UserId:    user.Id,
+		FamilyId:  familyId,
+		TokenHash: util.HashToken(refreshToken),
+		AccessJti: jti,
+		ExpiredAt: now.Add(constants.REFRESH_TOKEN_TTL),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.RefreshTokenRepository.Create(tx, &stored); err != nil {
+		s.log(util.TxContext(tx)).Error("error creating refresh token", zap.Error(err))
+		return dto.ResponseToken{}, err
+	}
+
+	return dto.ResponseToken{
+		Token:        accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(constants.ACCESS_TOKEN_TTL.Seconds()),
+	}, nil
+}
+
+// Refresh rotates the refresh token, a refresh token that was already rotated means it leaked
+// so the whole family is revoked and the user has to login again
+func (s service) Refresh(ctx context.Context, payload dto.PayloadRefreshToken) (dto.ResponseToken, error) {
+	ctx, span := tracing.Start(ctx, "user.Refresh")
+	defer span.End()
+
+	tx := s.RefreshTokenRepository.Begin().WithContext(ctx)
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		s.log(ctx).Error("error begin transaction", zap.Error(err))
+		return dto.ResponseToken{}, err
+	}
+
+	res, err := s.ProcessRefreshTx(ctx, tx, payload)
+	if err != nil && !errors.Is(err, constants.RefreshTokenReused) {
+		tx.Rollback()
+		return dto.ResponseToken{}, err
+	}
+
+	// the family revocation of a reused token must be kept
+	if err := tx.Commit().Error; err != nil {
+		s.log(ctx).Error("error commit transaction", zap.Error(err))
+		return dto.ResponseToken{}, err
+	}
+
+	return res, err
+}
+
+func (s service) ProcessRefreshTx(ctx context.Context, tx *gorm.DB, payload dto.PayloadRefreshToken) (dto.ResponseToken, error) {
+	now := time.Now().In(util.LocationTime)
+	token, err := s.RefreshTokenRepository.FindOneTx(tx, "*", "token_hash = ?", util.HashToken(payload.RefreshToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.ResponseToken{}, constants.RefreshTokenInvalid
+		}
+
+		s.log(ctx).Error("error get refresh token", zap.Error(err))
+		return dto.ResponseToken{}, err
+	}
+
+	if token.RotatedAt != nil {
+		s.log(ctx).Warn("refresh token reused", zap.Int("userId", token.UserId), zap.String("familyId", token.FamilyId))
+		if err := s.RevokeFamilyTx(tx, token.FamilyId); err != nil {
+			return dto.ResponseToken{}, err
+		}
+
+		return dto.ResponseToken{}, constants.RefreshTokenReused
+	}
+
+	if token.RevokedAt != nil || !token.ExpiredAt.After(now) {
+		return dto.ResponseToken{}, constants.RefreshTokenInvalid
+	}
+
+	user, err := s.UserRepository.FindOne(ctx, "id,role", "id = ? and is_active = 1", token.UserId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.ResponseToken{}, constants.RefreshTokenInvalid
+		}
+
+		s.log(ctx).Error("error get user", zap.Error(err))
+		return dto.ResponseToken{}, err
+	}
+
+	rotated := models.RefreshToken{RotatedAt: &now, UpdatedAt: now}
+	if err := s.RefreshTokenRepository.UpdateTx(tx, &rotated, "rotated_at,updated_at", "id = ?", token.ID); err != nil {
+		s.log(ctx).Error("error update refresh token", zap.Error(err))
+		return dto.ResponseToken{}, err
+	}
+
+	return s.IssueTokenTx(tx, user, token.FamilyId)
+}
+
+// RevokeFamilyTx revokes every refresh token of the family and the access tokens issued with them
+func (s service) RevokeFamilyTx(tx *gorm.DB, familyId string) error {
+	now := time.Now().In(util.LocationTime)
+	tokens, err := s.RefreshTokenRepository.FindTx(tx, "id,user_id,access_jti,created_at", "family_id = ? and revoked_at is null", familyId)
+	if err != nil {
+		s.log(util.TxContext(tx)).Error("error get refresh token family", zap.Error(err))
+		return err
+	}
+
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	revoked := models.RefreshToken{RevokedAt: &now, UpdatedAt: now}
+	if err := s.RefreshTokenRepository.UpdateTx(tx, &revoked, "revoked_at,updated_at", "family_id = ? and revoked_at is null", familyId); err != nil {
+		s.log(util.TxContext(tx)).Error("error revoke refresh token family", zap.Error(err))
+		return err
+	}
+
+	for _, token := range tokens {
+		accessExpiredAt := token.CreatedAt.Add(constants.ACCESS_TOKEN_TTL)
+		if !accessExpiredAt.After(now) {
+			continue
+		}
+
+		revokedToken := models.RevokedToken{Jti: token.AccessJti, UserId: token.UserId, ExpiredAt: accessExpiredAt, CreatedAt: now}
+		if err := s.RevokedTokenRepository.CreateTx(tx, &revokedToken); err != nil {
+			s.log(util.TxContext(tx)).Error("error revoke access token", zap.Error(err))
+			return err
+		}
+	}
+
+	s.log(util.TxContext(tx)).Info("token family revoked", zap.String("familyId", familyId), zap.Int("tokens", len(tokens)))
+	return nil
+}
+
+// RevokeUserSessionsTx revokes every token family of the user, used when the password is changed
+func (s service) RevokeUserSessionsTx(tx *gorm.DB, userId int) error {
+	tokens, err := s.RefreshTokenRepository.FindTx(tx, "id,family_id", "user_id = ? and revoked_at is null", userId)
+	if err != nil {
+		s.log(util.TxContext(tx)).Error("error get refresh token", zap.Error(err))
+		return err
+	}
+
+	revokedFamilies := make(map[string]bool)
+	for _, token := range tokens {
+		if revokedFamilies[token.FamilyId] {
+			continue
+		}
+
+		if err := s.RevokeFamilyTx(tx, token.FamilyId); err != nil {
+			return err
+		}
+		revokedFamilies[token.FamilyId] = true
+	}
+
+	return nil
+}
+
+// Logout revokes the access token of the request and the family of the refresh token when it is sent
+func (s service) Logout(ctx context.Context, userClaim dto.UserClaimJwt, payload dto.PayloadLogout) error {
+	ctx, span := tracing.Start(ctx, "user.Logout")
+	defer span.End()
+
+	tx := s.RefreshTokenRepository.Begin().WithContext(ctx)
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		s.log(ctx).Error("error begin transaction", zap.Error(err))
+		return err
+	}
+
+	revokedToken := models.RevokedToken{
+		Jti:       userClaim.TokenId,
+		UserId:    userClaim.UserId,
+		ExpiredAt: userClaim.TokenExpiredAt,
+		CreatedAt: time.Now().In(util.LocationTime),
+	}
+	if err := s.RevokedTokenRepository.CreateTx(tx, &revokedToken); err != nil {
+		tx.Rollback()
+		s.log(ctx).Error("error revoke access token", zap.Error(err))
+		return err
+	}
+
+	if payload.RefreshToken != "" {
+		token, err := s.RefreshTokenRepository.FindOneTx(tx, "id,family_id", "token_hash = ? and user_id = ?", util.HashToken(payload.RefreshToken), userClaim.UserId)
+		if err != nil {
+			tx.Rollback()
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return constants.RefreshTokenInvalid
+			}
+
+			s.log(ctx).Error("error get refresh token", zap.Error(err))
+			return err
+		}
+
+		if err := s.RevokeFamilyTx(tx, token.FamilyId); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		s.log(ctx).Error("error commit transaction", zap.Error(err))
+		return err
+	}
+
+	s.log(ctx).Info("user logout", zap.Int("userId", userClaim.UserId))
+	return nil
+}
+
+// CleanupTokens removes expired refresh tokens, revoked access tokens, verification codes, login challenges and stale login failures
+func (s service) CleanupTokens() {
+	ctx := context.Background()
+	now := time.Now().In(util.LocationTime).Format("2006-01-02 15:04:05")
+
+	s.Log.Info("running token cleanup")
+
+	if err := s.RevokedTokenRepository.Delete(ctx, "expired_at < ?", now); err != nil {
+		s.Log.Error("error delete revoked token", zap.Error(err))
+		return
+	}
+
+	if err := s.RefreshTokenRepository.Delete(ctx, "expired_at < ?", now); err != nil {
+		s.Log.Error("error delete refresh token", zap.Error(err))
+		return
+	}
+
+	if err := s.UserVerificationRepository.Delete(ctx, "expired_at < ?", now); err != nil {
+		s.Log.Error("error delete user verification", zap.Error(err))
+		return
+	}
+
+	if err := s.LoginChallengeRepository.Delete(ctx, "expired_at < ?", now); err != nil {
+		s.Log.Error("error delete login challenge", zap.Error(err))
+		return
+	}
+
+	staleFailure := time.Now().In(util.LocationTime).Add(-constants.LOGIN_FAILURE_WINDOW).Format("2006-01-02 15:04:05")
+	if err := s.LoginFailureRepository.Delete(ctx, "last_failed_at < ? and (locked_until is null or locked_until < ?)", staleFailure, now); err != nil {
+		s.Log.Error("error delete login failure", zap.Error(err))
+		return
+	}
+}
+
+// Jwks publishes the public keys so other services can verify access tokens without the signing key
+func (s service) Jwks(ctx context.Context) (util.JWKS, error) {
+	ctx, span := tracing.Start(ctx, "user.Jwks")
+	defer span.End()
+
+	jwks, err := util.PublicJWKS()
+	if err != nil {
+		s.log(ctx).Error("error load jwt keys", zap.Error(err))
+		return util.JWKS{}, err
+	}
+
+	return jwks, nil
+}
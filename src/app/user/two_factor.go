@@ -0,0 +1,452 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"strings"
+	"test-edot/constants"
+	"test-edot/src/dto"
+	"test-edot/src/models"
+	"test-edot/src/tracing"
+	"test-edot/util"
+	"time"
+)
+
+const twoFactorFields = "id,role,is_active,email,password,totp_secret,totp_enabled_at,totp_last_step"
+
+// recoveryCodeHash binds the recovery code to the user like verificationHash
+func recoveryCodeHash(userId int, code string) string {
+	return util.HashToken(fmt.Sprintf("%d:recovery:%s", userId, code))
+}
+
+// normalizeSecondFactorCode accepts codes typed with spaces or the dash of the recovery code format
+func normalizeSecondFactorCode(code string) string {
+	return strings.ToLower(strings.NewReplacer(" ", "", "-", "").Replace(strings.TrimSpace(code)))
+}
+
+func (s service) findTwoFactorUser(ctx context.Context, userId int) (models.User, error) {
+	user, err := s.UserRepository.FindOne(ctx, twoFactorFields, "id = ? and is_active = 1", userId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.User{}, constants.UserNotFound
+		}
+
+		s.log(ctx).Error("error get user", zap.Error(err))
+		return models.User{}, err
+	}
+
+	return user, nil
+}
+
+// EnrollTwoFactor stores a new pending secret, two factor is only turned on after ConfirmTwoFactor
+// so a secret that never reached the authenticator app can not lock the user out
+func (s service) EnrollTwoFactor(ctx context.Context, userClaim dto.UserClaimJwt) (dto.ResponseTwoFactorEnroll, error) {
+	ctx, span := tracing.Start(ctx, "user.EnrollTwoFactor")
+	defer span.End()
+
+	if !constants.MapRoleTwoFactor[userClaim.Role] {
+		return dto.ResponseTwoFactorEnroll{}, constants.TwoFactorNotAvailable
+	}
+
+	user, err := s.findTwoFactorUser(ctx, userClaim.UserId)
+	if err != nil {
+		return dto.ResponseTwoFactorEnroll{}, err
+	}
+
+	if user.TotpEnabledAt != nil {
+		return dto.ResponseTwoFactorEnroll{}, constants.TwoFactorAlreadyEnabled
+	}
+
+	secret, err := util.GenerateTOTPSecret()
+	if err != nil {
+		return dto.ResponseTwoFactorEnroll{}, err
+	}
+
+	enrolled := models.User{TotpSecret: &secret, TotpLastStep: 0, UpdatedAt: time.Now().In(util.LocationTime)}
+	if err := s.UserRepository.Update(ctx, &enrolled, "totp_secret,totp_last_step,updated_at", "id = ?", user.Id); err != nil {
+		s.log(ctx).Error("error update user totp secret", zap.Error(err))
+		return dto.ResponseTwoFactorEnroll{}, err
+	}
+
+	return dto.ResponseTwoFactorEnroll{
+		Secret: secret,
+		Uri:    util.TOTPURI(constants.TOTP_ISSUER, user.Email, secret),
+	}, nil
+}
+
+// ConfirmTwoFactor turns on two factor when the first code of the app matches and returns the recovery codes,
+// they are only shown once
+func (s service) ConfirmTwoFactor(ctx context.Context, userClaim dto.UserClaimJwt, payload dto.PayloadTwoFactorCode) (dto.ResponseRecoveryCodes, error) {
+	ctx, span := tracing.Start(ctx, "user.ConfirmTwoFactor")
+	defer span.End()
+
+	user, err := s.findTwoFactorUser(ctx, userClaim.UserId)
+	if err != nil {
+		return dto.ResponseRecoveryCodes{}, err
+	}
+
+	if user.TotpEnabledAt != nil {
+		return dto.ResponseRecoveryCodes{}, constants.TwoFactorAlreadyEnabled
+	}
+
+	if user.TotpSecret == nil {
+		return dto.ResponseRecoveryCodes{}, constants.TwoFactorNotEnrolled
+	}
+
+	now := time.Now().In(util.LocationTime)
+	step, ok := util.ValidateTOTP(*user.TotpSecret, normalizeSecondFactorCode(payload.Code), now, user.TotpLastStep)
+	if !ok {
+		return dto.ResponseRecoveryCodes{}, constants.TwoFactorCodeInvalid
+	}
+
+	tx := s.UserRecoveryCodeRepository.Begin().WithContext(ctx)
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		s.log(ctx).Error("error begin transaction", zap.Error(err))
+		return dto.ResponseRecoveryCodes{}, err
+	}
+
+	enabled := models.User{TotpEnabledAt: &now, TotpLastStep: step, UpdatedAt: now}
+	if err := s.UserRepository.UpdateTx(tx, &enabled, "totp_enabled_at,totp_last_step,updated_at", "id = ?", user.Id); err != nil {
+		tx.Rollback()
+		s.log(ctx).Error("error enable user two factor", zap.Error(err))
+		return dto.ResponseRecoveryCodes{}, err
+	}
+
+	recoveryCodes, err := s.ReplaceRecoveryCodesTx(tx, user.Id)
+	if err != nil {
+		tx.Rollback()
+		return dto.ResponseRecoveryCodes{}, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		s.log(ctx).Error("error commit transaction", zap.Error(err))
+		return dto.ResponseRecoveryCodes{}, err
+	}
+
+	s.log(ctx).Info("user two factor enabled", zap.Int("userId", user.Id))
+	return dto.ResponseRecoveryCodes{RecoveryCodes: recoveryCodes}, nil
+}
+
+// DisableTwoFactor needs the password and a second factor code, a stolen access token alone is not enough
+func (s service) DisableTwoFactor(ctx context.Context, userClaim dto.UserClaimJwt, payload dto.PayloadDisableTwoFactor) error {
+	ctx, span := tracing.Start(ctx, "user.DisableTwoFactor")
+	defer span.End()
+
+	user, err := s.findTwoFactorUser(ctx, userClaim.UserId)
+	if err != nil {
+		return err
+	}
+
+	if user.TotpEnabledAt == nil {
+		return constants.TwoFactorNotEnabled
+	}
+
+	if !s.checkPasswordHash(payload.Password, user.Password) {
+		return constants.InvalidPassword
+	}
+
+	tx := s.UserRecoveryCodeRepository.Begin().WithContext(ctx)
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		s.log(ctx).Error("error begin transaction", zap.Error(err))
+		return err
+	}
+
+	if err := s.VerifySecondFactorTx(tx, user, payload.Code); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	disabled := models.User{UpdatedAt: time.Now().In(util.LocationTime)}
+	if err := s.UserRepository.UpdateTx(tx, &disabled, "totp_secret,totp_enabled_at,totp_last_step,updated_at", "id = ?", user.Id); err != nil {
+		tx.Rollback()
+		s.log(ctx).Error("error disable user two factor", zap.Error(err))
+		return err
+	}
+
+	if err := s.UserRecoveryCodeRepository.DeleteTx(tx, "user_id = ?", user.Id); err != nil {
+		tx.Rollback()
+		s.log(ctx).Error("error delete user recovery code", zap.Error(err))
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		s.log(ctx).Error("error commit transaction", zap.Error(err))
+		return err
+	}
+
+	s.log(ctx).Info("user two factor disabled", zap.Int("userId", user.Id))
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces every recovery code, the old ones stop working
+func (s service) RegenerateRecoveryCodes(ctx context.Context, userClaim dto.UserClaimJwt, payload dto.PayloadTwoFactorCode) (dto.ResponseRecoveryCodes, error) {
+	ctx, span := tracing.Start(ctx, "user.RegenerateRecoveryCodes")
+	defer span.End()
+
+	user, err := s.findTwoFactorUser(ctx, userClaim.UserId)
+	if err != nil {
+		return dto.ResponseRecoveryCodes{}, err
+	}
+
+	if user.TotpEnabledAt == nil {
+		return dto.ResponseRecoveryCodes{}, constants.TwoFactorNotEnabled
+	}
+
+	tx := s.UserRecoveryCodeRepository.Begin().WithContext(ctx)
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		s.log(ctx).Error("error begin transaction", zap.Error(err))
+		return dto.ResponseRecoveryCodes{}, err
+	}
+
+	if err := s.VerifySecondFactorTx(tx, user, payload.Code); err != nil {
+		tx.Rollback()
+		return dto.ResponseRecoveryCodes{}, err
+	}
+
+	recoveryCodes, err := s.ReplaceRecoveryCodesTx(tx, user.Id)
+	if err != nil {
+		tx.Rollback()
+		return dto.ResponseRecoveryCodes{}, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		s.log(ctx).Error("error commit transaction", zap.Error(err))
+		return dto.ResponseRecoveryCodes{}, err
+	}
+
+	return dto.ResponseRecoveryCodes{RecoveryCodes: recoveryCodes}, nil
+}
+
+// ReplaceRecoveryCodesTx only the hash is stored, the codes are returned formatted as xxxxx-xxxxx
+func (s service) ReplaceRecoveryCodesTx(tx *gorm.DB, userId int) ([]string, error) {
+	if err := s.UserRecoveryCodeRepository.DeleteTx(tx, "user_id = ?", userId); err != nil {
+		s.log(util.TxContext(tx)).Error("error delete user recovery code", zap.Error(err))
+		return nil, err
+	}
+
+	now := time.Now().In(util.LocationTime)
+	recoveryCodes := make([]string, 0, constants.RECOVERY_CODE_COUNT)
+	stored := make([]models.UserRecoveryCode, 0, constants.RECOVERY_CODE_COUNT)
+	for i := 0; i < constants.RECOVERY_CODE_COUNT; i++ {
+		code, err := util.GenerateRandomToken(constants.RECOVERY_CODE_BYTES)
+		if err != nil {
+			return nil, err
+		}
+
+		half := len(code) / 2
+		recoveryCodes = append(recoveryCodes, code[:half]+"-"+code[half:])
+		stored = append(stored, models.UserRecoveryCode{UserId: userId, CodeHash: recoveryCodeHash(userId, code), CreatedAt: now})
+	}
+
+	if err := s.UserRecoveryCodeRepository.CreateTx(tx, stored); err != nil {
+		s.log(util.TxContext(tx)).Error("error creating user recovery code", zap.Error(err))
+		return nil, err
+	}
+
+	return recoveryCodes, nil
+}
+
+// VerifySecondFactorTx accepts a totp code or an unused recovery code, the accepted totp step is stored
+// so the same code can not be replayed and a recovery code can only be used once
+func (s service) VerifySecondFactorTx(tx *gorm.DB, user models.User, code string) error {
+	if user.TotpSecret == nil {
+		return constants.TwoFactorNotEnrolled
+	}
+
+	code = normalizeSecondFactorCode(code)
+	now := time.Now().In(util.LocationTime)
+
+	if len(code) != constants.RECOVERY_CODE_BYTES*2 {
+		step, ok := util.ValidateTOTP(*user.TotpSecret, code, now, user.TotpLastStep)
+		if !ok {
+			return constants.TwoFactorCodeInvalid
+		}
+
+		used := models.User{TotpLastStep: step}
+		if err := s.UserRepository.UpdateTx(tx, &used, "totp_last_step", "id = ?", user.Id); err != nil {
+			s.log(util.TxContext(tx)).Error("error update user totp step", zap.Error(err))
+			return err
+		}
+
+		return nil
+	}
+
+	recoveryCode, err := s.UserRecoveryCodeRepository.FindOneTx(tx, "id", "user_id = ? and code_hash = ? and used_at is null", user.Id, recoveryCodeHash(user.Id, code))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return constants.TwoFactorCodeInvalid
+		}
+
+		s.log(util.TxContext(tx)).Error("error get user recovery code", zap.Error(err))
+		return err
+	}
+
+	used := models.UserRecoveryCode{UsedAt: &now}
+	if err := s.UserRecoveryCodeRepository.UpdateTx(tx, &used, "used_at", "id = ?", recoveryCode.ID); err != nil {
+		s.log(util.TxContext(tx)).Error("error update user recovery code", zap.Error(err))
+		return err
+	}
+
+	s.log(util.TxContext(tx)).Info("user recovery code used", zap.Int("userId", user.Id))
+	return nil
+}
+
+// StartChallenge is returned by Login instead of the tokens when two factor is on,
+// the challenge token is exchanged with a second factor code at LoginTwoFactor
+func (s service) StartChallenge(ctx context.Context, user models.User) (dto.ResponseToken, error) {
+	token, err := util.GenerateRandomToken(32)
+	if err != nil {
+		return dto.ResponseToken{}, err
+	}
+
+	now := time.Now().In(util.LocationTime)
+	challenge := models.LoginChallenge{
+		UserId:    user.Id,
+		TokenHash: util.HashToken(token),
+		ExpiredAt: now.Add(constants.LOGIN_CHALLENGE_TTL),
+		CreatedAt: now,
+	}
+	if err := s.LoginChallengeRepository.Create(ctx, &challenge); err != nil {
+		s.log(ctx).Error("error creating login challenge", zap.Error(err))
+		return dto.ResponseToken{}, err
+	}
+
+	return dto.ResponseToken{
+		TwoFactorRequired: true,
+		ChallengeToken:    token,
+		ExpiresIn:         int(constants.LOGIN_CHALLENGE_TTL.Seconds()),
+	}, nil
+}
+
+// LoginTwoFactor issues the tokens of a new session when the second factor of the challenge matches,
+// a wrong code is a failed login of the account and the ip so new challenges do not give new guesses
+func (s service) LoginTwoFactor(ctx context.Context, payload dto.PayloadLoginTwoFactor, clientIp string) (dto.ResponseToken, error) {
+	ctx, span := tracing.Start(ctx, "user.LoginTwoFactor")
+	defer span.End()
+
+	tx := s.LoginChallengeRepository.Begin().WithContext(ctx)
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		s.log(ctx).Error("error begin transaction", zap.Error(err))
+		return dto.ResponseToken{}, err
+	}
+
+	res, scopes, err := s.ProcessChallengeTx(ctx, tx, payload, clientIp)
+	if err != nil && !errors.Is(err, constants.TwoFactorCodeInvalid) {
+		tx.Rollback()
+		return dto.ResponseToken{}, err
+	}
+
+	// the failed attempt of a wrong code must be kept
+	if err := tx.Commit().Error; err != nil {
+		s.log(ctx).Error("error commit transaction", zap.Error(err))
+		return dto.ResponseToken{}, err
+	}
+
+	if errors.Is(err, constants.TwoFactorCodeInvalid) {
+		if err := s.RecordLoginFailure(ctx, scopes, clientIp, "invalid_second_factor"); !errors.Is(err, constants.InvalidCredential) {
+			return dto.ResponseToken{}, err
+		}
+
+		return dto.ResponseToken{}, constants.TwoFactorCodeInvalid
+	}
+
+	// the password alone does not reset the failures of the account, see Login
+	if err := s.LoginFailureRepository.Delete(ctx, "scope = ? and identifier = ?", scopes[0].Scope, scopes[0].Identifier); err != nil {
+		s.log(ctx).Error("error reset login failure", zap.Error(err), zap.String("identifier", scopes[0].Identifier))
+	}
+
+	return res, nil
+}
+
+// ProcessChallengeTx checks the login lock of the challenge user before the code, the scopes are returned
+// so the caller counts a wrong code once the attempt of the challenge is committed
+func (s service) ProcessChallengeTx(ctx context.Context, tx *gorm.DB, payload dto.PayloadLoginTwoFactor, clientIp string) (dto.ResponseToken, []loginScope, error) {
+	now := time.Now().In(util.LocationTime)
+	challenge, err := s.LoginChallengeRepository.FindOneTx(tx, "*", "token_hash = ? and used_at is null", util.HashToken(payload.ChallengeToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.ResponseToken{}, nil, constants.LoginChallengeInvalid
+		}
+
+		s.log(ctx).Error("error get login challenge", zap.Error(err))
+		return dto.ResponseToken{}, nil, err
+	}
+
+	if !challenge.ExpiredAt.After(now) || challenge.Attempts >= constants.LOGIN_CHALLENGE_MAX_ATTEMPTS {
+		return dto.ResponseToken{}, nil, constants.LoginChallengeInvalid
+	}
+
+	user, err := s.UserRepository.FindOne(ctx, twoFactorFields, "id = ? and is_active = 1", challenge.UserId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.ResponseToken{}, nil, constants.LoginChallengeInvalid
+		}
+
+		s.log(ctx).Error("error get user", zap.Error(err))
+		return dto.ResponseToken{}, nil, err
+	}
+
+	if user.TotpEnabledAt == nil {
+		return dto.ResponseToken{}, nil, constants.LoginChallengeInvalid
+	}
+
+	scopes := loginScopes(user, "", clientIp)
+	if err := s.CheckLoginLock(ctx, scopes); err != nil {
+		return dto.ResponseToken{}, nil, err
+	}
+
+	if err := s.VerifySecondFactorTx(tx, user, payload.Code); err != nil {
+		if !errors.Is(err, constants.TwoFactorCodeInvalid) {
+			return dto.ResponseToken{}, nil, err
+		}
+
+		attempt := models.LoginChallenge{Attempts: challenge.Attempts + 1}
+		if err := s.LoginChallengeRepository.UpdateTx(tx, &attempt, "attempts", "id = ?", challenge.ID); err != nil {
+			s.log(ctx).Error("error update login challenge", zap.Error(err))
+			return dto.ResponseToken{}, nil, err
+		}
+
+		return dto.ResponseToken{}, scopes, constants.TwoFactorCodeInvalid
+	}
+
+	used := models.LoginChallenge{UsedAt: &now}
+	if err := s.LoginChallengeRepository.UpdateTx(tx, &used, "used_at", "id = ?", challenge.ID); err != nil {
+		s.log(ctx).Error("error update login challenge", zap.Error(err))
+		return dto.ResponseToken{}, nil, err
+	}
+
+	familyId, err := util.GenerateRandomToken(16)
+	if err != nil {
+		return dto.ResponseToken{}, nil, err
+	}
+
+	res, err := s.IssueTokenTx(tx, user, familyId)
+	return res, scopes, err
+}
@@ -0,0 +1,336 @@
+package user
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"test-edot/constants"
+	"test-edot/src/dto"
+	"test-edot/src/models"
+	"test-edot/src/notifier"
+	"test-edot/src/tracing"
+	"test-edot/util"
+	"time"
+)
+
+// verificationHash binds the code to the user and the purpose, a code can not be reused for another flow
+func verificationHash(userId int, purpose, code string) string {
+	return util.HashToken(fmt.Sprintf("%d:%s:%s", userId, purpose, code))
+}
+
+// RequestVerification sends an otp code to the email or phone of the user
+func (s service) RequestVerification(ctx context.Context, userClaim dto.UserClaimJwt, payload dto.PayloadRequestVerification) error {
+	ctx, span := tracing.Start(ctx, "user.RequestVerification")
+	defer span.End()
+
+	if !constants.MapVerificationChannel[payload.Channel] {
+		return constants.VerificationNotAvailable
+	}
+
+	user, err := s.UserRepository.FindOne(ctx, "id,email,phone,email_verified_at,phone_verified_at", "id = ?", userClaim.UserId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return constants.UserNotFound
+		}
+
+		s.log(ctx).Error("error get user", zap.Error(err))
+		return err
+	}
+
+	target, verifiedAt := user.Email, user.EmailVerifiedAt
+	if payload.Channel == constants.VERIFICATION_PHONE {
+		target, verifiedAt = user.Phone, user.PhoneVerifiedAt
+	}
+
+	if verifiedAt != nil {
+		return constants.UserAlreadyVerified
+	}
+
+	return s.SendCode(ctx, user.Id, payload.Channel, payload.Channel, target)
+}
+
+// ConfirmVerification marks the email or phone as verified when the code matches
+func (s service) ConfirmVerification(ctx context.Context, userClaim dto.UserClaimJwt, payload dto.PayloadConfirmVerification) error {
+	ctx, span := tracing.Start(ctx, "user.ConfirmVerification")
+	defer span.End()
+
+	if !constants.MapVerificationChannel[payload.Channel] {
+		return constants.VerificationNotAvailable
+	}
+
+	user, err := s.UserRepository.FindOne(ctx, "id,email,phone", "id = ?", userClaim.UserId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return constants.UserNotFound
+		}
+
+		s.log(ctx).Error("error get user", zap.Error(err))
+		return err
+	}
+
+	tx := s.UserVerificationRepository.Begin().WithContext(ctx)
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		s.log(ctx).Error("error begin transaction", zap.Error(err))
+		return err
+	}
+
+	verification, err := s.CheckCodeTx(tx, user.Id, payload.Channel, payload.Code)
+	if err != nil && !errors.Is(err, constants.VerificationCodeInvalid) {
+		tx.Rollback()
+		return err
+	}
+
+	if err == nil {
+		target, field := user.Email, "email_verified_at"
+		if payload.Channel == constants.VERIFICATION_PHONE {
+			target, field = user.Phone, "phone_verified_at"
+		}
+
+		// the email or phone was changed after the code was sent
+		if verification.Target != target {
+			tx.Rollback()
+			return constants.VerificationCodeInvalid
+		}
+
+		now := time.Now().In(util.LocationTime)
+		verified := models.User{EmailVerifiedAt: &now, PhoneVerifiedAt: &now, UpdatedAt: now}
+		if err := s.UserRepository.UpdateTx(tx, &verified, field+",updated_at", "id = ?", user.Id); err != nil {
+			tx.Rollback()
+			s.log(ctx).Error("error update user verification", zap.Error(err))
+			return err
+		}
+	}
+
+	// the failed attempt of a wrong code must be kept
+	if err := tx.Commit().Error; err != nil {
+		s.log(ctx).Error("error commit transaction", zap.Error(err))
+		return err
+	}
+
+	if err == nil {
+		s.log(ctx).Info("user verified", zap.Int("userId", user.Id), zap.String("channel", payload.Channel))
+	}
+
+	return err
+}
+
+// ForgotPassword sends a reset code to the email, unknown emails get the same answer so they can not be enumerated
+func (s service) ForgotPassword(ctx context.Context, payload dto.PayloadForgotPassword) error {
+	ctx, span := tracing.Start(ctx, "user.ForgotPassword")
+	defer span.End()
+
+	user, err := s.UserRepository.FindOne(ctx, "id,email", "email = ? and is_active = 1", payload.Email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.log(ctx).Info("password reset requested for unknown email")
+			return nil
+		}
+
+		s.log(ctx).Error("error get user", zap.Error(err))
+		return err
+	}
+
+	err = s.SendCode(ctx, user.Id, constants.VERIFICATION_PASSWORD_RESET, constants.VERIFICATION_EMAIL, user.Email)
+	if errors.Is(err, constants.VerificationTooFrequent) {
+		s.log(ctx).Info("password reset requested too frequent", zap.Int("userId", user.Id))
+		return nil
+	}
+
+	return err
+}
+
+// ResetPassword replaces the password when the reset code matches and logs out every session of the user
+func (s service) ResetPassword(ctx context.Context, payload dto.PayloadResetPassword) error {
+	ctx, span := tracing.Start(ctx, "user.ResetPassword")
+	defer span.End()
+
+	user, err := s.UserRepository.FindOne(ctx, "id", "email = ? and is_active = 1", payload.Email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return constants.VerificationCodeInvalid
+		}
+
+		s.log(ctx).Error("error get user", zap.Error(err))
+		return err
+	}
+
+	passwordHashed, err := util.HashPassword(payload.Password)
+	if err != nil {
+		return err
+	}
+
+	tx := s.UserVerificationRepository.Begin().WithContext(ctx)
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		s.log(ctx).Error("error begin transaction", zap.Error(err))
+		return err
+	}
+
+	_, err = s.CheckCodeTx(tx, user.Id, constants.VERIFICATION_PASSWORD_RESET, payload.Code)
+	if err != nil && !errors.Is(err, constants.VerificationCodeInvalid) {
+		tx.Rollback()
+		return err
+	}
+
+	if err == nil {
+		updated := models.User{Password: passwordHashed, UpdatedAt: time.Now().In(util.LocationTime)}
+		if err := s.UserRepository.UpdateTx(tx, &updated, "password,updated_at", "id = ?", user.Id); err != nil {
+			tx.Rollback()
+			s.log(ctx).Error("error update user password", zap.Error(err))
+			return err
+		}
+
+		if err := s.RevokeUserSessionsTx(tx, user.Id); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	// the failed attempt of a wrong code must be kept
+	if err := tx.Commit().Error; err != nil {
+		s.log(ctx).Error("error commit transaction", zap.Error(err))
+		return err
+	}
+
+	if err == nil {
+		s.log(ctx).Info("user password reset", zap.Int("userId", user.Id))
+	}
+
+	return err
+}
+
+// SendCode stores a new code for the purpose and delivers it through the notifier
+func (s service) SendCode(ctx context.Context, userId int, purpose, channel, target string) error {
+	code, err := util.GenerateOTP(constants.OTP_LENGTH)
+	if err != nil {
+		return err
+	}
+
+	tx := s.UserVerificationRepository.Begin().WithContext(ctx)
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		s.log(ctx).Error("error begin transaction", zap.Error(err))
+		return err
+	}
+
+	if err := s.CreateCodeTx(tx, userId, purpose, target, code); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		s.log(ctx).Error("error commit transaction", zap.Error(err))
+		return err
+	}
+
+	message := notifier.Message{
+		Channel: channel,
+		To:      target,
+		Subject: fmt.Sprintf("your %s code", purpose),
+		Body:    fmt.Sprintf("your code is %s, valid for %d minutes", code, int(constants.OTP_TTL.Minutes())),
+	}
+	if err := s.Notifier.Send(ctx, message); err != nil {
+		s.log(ctx).Error("error send verification code", zap.Error(err), zap.Int("userId", userId), zap.String("purpose", purpose))
+		return err
+	}
+
+	return nil
+}
+
+// CreateCodeTx replaces the pending code of the purpose, a new code can only be requested after OTP_RESEND_INTERVAL
+func (s service) CreateCodeTx(tx *gorm.DB, userId int, purpose, target, code string) error {
+	now := time.Now().In(util.LocationTime)
+	pending, err := s.UserVerificationRepository.FindOneTx(tx, "id,created_at", "user_id = ? and purpose = ? and used_at is null", userId, purpose)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		s.log(util.TxContext(tx)).Error("error get user verification", zap.Error(err))
+		return err
+	}
+
+	if pending.ID != 0 {
+		if now.Before(pending.CreatedAt.Add(constants.OTP_RESEND_INTERVAL)) {
+			return constants.VerificationTooFrequent
+		}
+
+		replaced := models.UserVerification{UsedAt: &now, UpdatedAt: now}
+		if err := s.UserVerificationRepository.UpdateTx(tx, &replaced, "used_at,updated_at", "user_id = ? and purpose = ? and used_at is null", userId, purpose); err != nil {
+			s.log(util.TxContext(tx)).Error("error update user verification", zap.Error(err))
+			return err
+		}
+	}
+
+	verification := models.UserVerification{
+		UserId:    userId,
+		Purpose:   purpose,
+		Target:    target,
+		CodeHash:  verificationHash(userId, purpose, code),
+		ExpiredAt: now.Add(constants.OTP_TTL),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.UserVerificationRepository.Create(tx, &verification); err != nil {
+		s.log(util.TxContext(tx)).Error("error creating user verification", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// CheckCodeTx consumes the pending code of the purpose, a wrong code counts as an attempt
+// and the code is locked after OTP_MAX_ATTEMPTS
+func (s service) CheckCodeTx(tx *gorm.DB, userId int, purpose, code string) (models.UserVerification, error) {
+	now := time.Now().In(util.LocationTime)
+	verification, err := s.UserVerificationRepository.FindOneTx(tx, "*", "user_id = ? and purpose = ? and used_at is null", userId, purpose)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.UserVerification{}, constants.VerificationCodeInvalid
+		}
+
+		s.log(util.TxContext(tx)).Error("error get user verification", zap.Error(err))
+		return models.UserVerification{}, err
+	}
+
+	if !verification.ExpiredAt.After(now) {
+		return models.UserVerification{}, constants.VerificationCodeInvalid
+	}
+
+	if verification.Attempts >= constants.OTP_MAX_ATTEMPTS {
+		return models.UserVerification{}, constants.VerificationMaxAttempts
+	}
+
+	if subtle.ConstantTimeCompare([]byte(verificationHash(userId, purpose, code)), []byte(verification.CodeHash)) != 1 {
+		attempt := models.UserVerification{Attempts: verification.Attempts + 1, UpdatedAt: now}
+		if err := s.UserVerificationRepository.UpdateTx(tx, &attempt, "attempts,updated_at", "id = ?", verification.ID); err != nil {
+			s.log(util.TxContext(tx)).Error("error update user verification", zap.Error(err))
+			return models.UserVerification{}, err
+		}
+
+		return models.UserVerification{}, constants.VerificationCodeInvalid
+	}
+
+	used := models.UserVerification{UsedAt: &now, UpdatedAt: now}
+	if err := s.UserVerificationRepository.UpdateTx(tx, &used, "used_at,updated_at", "id = ?", verification.ID); err != nil {
+		s.log(util.TxContext(tx)).Error("error update user verification", zap.Error(err))
+		return models.UserVerification{}, err
+	}
+
+	return verification, nil
+}
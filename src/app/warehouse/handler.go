@@ -47,10 +47,10 @@ func (h *handler) AddWarehouse(g *gin.Context) {
 func (h *handler) TransferProductWarehouse(g *gin.Context) {
 	userClaim := g.Value("userClaim").(dto.UserClaimJwt)
 
-	fromId, err := strconv.Atoi(g.Param("from_id"))
+	fromId, err := strconv.Atoi(g.Param("warehouse_id"))
 	if err != nil {
 		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
-			Error: "product_id is not valid",
+			Error: "warehouse_id is not valid",
 		})
 		return
 	}
@@ -58,12 +58,21 @@ func (h *handler) TransferProductWarehouse(g *gin.Context) {
 	toId, err := strconv.Atoi(g.Param("to_id"))
 	if err != nil {
 		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
-			Error: "product_id is not valid",
+			Error: "to_id is not valid",
 		})
 		return
 	}
 
-	if err := h.service.TransferProductWarehouse(g, userClaim, fromId, toId); err != nil {
+	var payload dto.PayloadTransferProductWarehouse
+	if err := g.ShouldBindJSON(&payload); err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	res, err := h.service.TransferProductWarehouse(g, userClaim, fromId, toId, payload)
+	if err != nil {
 		g.JSON(http.StatusBadRequest, dto.ErrorResponse{
 			Error: err.Error(),
 		})
@@ -71,7 +80,8 @@ func (h *handler) TransferProductWarehouse(g *gin.Context) {
 	}
 
 	g.JSON(http.StatusOK, dto.Response{
-		Message: "success change status warehouse",
+		Message: "success dispatch warehouse transfer",
+		Data:    res,
 	})
 }
 
@@ -124,3 +134,134 @@ func (h *handler) GetWarehouses(g *gin.Context) {
 	})
 	return
 }
+
+func (h *handler) SetReorderThreshold(g *gin.Context) {
+	userClaim := g.Value("userClaim").(dto.UserClaimJwt)
+
+	var payload dto.PayloadReorderThreshold
+	if err := g.ShouldBindJSON(&payload); err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	if err := h.service.SetReorderThreshold(g, userClaim, payload); err != nil {
+		g.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	g.JSON(http.StatusOK, dto.Response{
+		Message: "success set reorder threshold",
+	})
+	return
+}
+
+func (h *handler) GetLowStock(g *gin.Context) {
+	userClaim := g.Value("userClaim").(dto.UserClaimJwt)
+
+	var payload dto.ParameterQueryLowStock
+	if err := g.ShouldBind(&payload); err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	res, err := h.service.GetLowStock(g, userClaim, payload)
+	if err != nil {
+		g.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	g.JSON(http.StatusOK, dto.Response{
+		Message: "success list low stock",
+		Data:    res,
+	})
+	return
+}
+
+func (h *handler) GetWarehouse(g *gin.Context) {
+	userClaim := g.Value("userClaim").(dto.UserClaimJwt)
+
+	warehouseId, err := strconv.Atoi(g.Param("warehouse_id"))
+	if err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: "warehouse_id is not valid",
+		})
+		return
+	}
+
+	res, err := h.service.GetWarehouse(g, userClaim, warehouseId)
+	if err != nil {
+		g.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	g.JSON(http.StatusOK, dto.Response{
+		Message: "success fetch warehouse",
+		Data:    res,
+	})
+}
+
+func (h *handler) UpdateWarehouse(g *gin.Context) {
+	userClaim := g.Value("userClaim").(dto.UserClaimJwt)
+
+	warehouseId, err := strconv.Atoi(g.Param("warehouse_id"))
+	if err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: "warehouse_id is not valid",
+		})
+		return
+	}
+
+	var payload dto.PayloadUpdateWarehouse
+	if err := g.ShouldBindJSON(&payload); err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	res, err := h.service.UpdateWarehouse(g, userClaim, warehouseId, payload)
+	if err != nil {
+		g.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	g.JSON(http.StatusOK, dto.Response{
+		Message: "success update warehouse",
+		Data:    res,
+	})
+}
+
+func (h *handler) DeleteWarehouse(g *gin.Context) {
+	userClaim := g.Value("userClaim").(dto.UserClaimJwt)
+
+	warehouseId, err := strconv.Atoi(g.Param("warehouse_id"))
+	if err != nil {
+		g.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: "warehouse_id is not valid",
+		})
+		return
+	}
+
+	if err := h.service.DeleteWarehouse(g, userClaim, warehouseId); err != nil {
+		g.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	g.JSON(http.StatusOK, dto.Response{
+		Message: "success delete warehouse",
+	})
+}
@@ -2,13 +2,18 @@ package warehouse
 
 import (
 	"github.com/gin-gonic/gin"
+	"test-edot/constants"
 	"test-edot/src/middleware"
 )
 
-func (h *handler) WarehouseBearerShopRouter(g *gin.RouterGroup) {
-	g.Use(middleware.BearerShop())
-	g.POST("", h.AddWarehouse)
-	g.GET("", h.GetWarehouses)
-	g.PUT("status", h.ChangeStatusWarehouse)
-	g.POST("/:from_id/transfer/:to_id", h.TransferProductWarehouse)
+func (h *handler) WarehouseRouter(g *gin.RouterGroup) {
+	g.POST("", middleware.Authorize(constants.PERMISSION_WAREHOUSE_WRITE), h.AddWarehouse)
+	g.GET("", middleware.Authorize(constants.PERMISSION_WAREHOUSE_READ), h.GetWarehouses)
+	g.PUT("status", middleware.Authorize(constants.PERMISSION_WAREHOUSE_WRITE), h.ChangeStatusWarehouse)
+	g.PUT("threshold", middleware.Authorize(constants.PERMISSION_WAREHOUSE_WRITE), h.SetReorderThreshold)
+	g.GET("low-stock", middleware.Authorize(constants.PERMISSION_WAREHOUSE_READ), h.GetLowStock)
+	g.GET("/:warehouse_id", middleware.Authorize(constants.PERMISSION_WAREHOUSE_READ), h.GetWarehouse)
+	g.PUT("/:warehouse_id", middleware.Authorize(constants.PERMISSION_WAREHOUSE_WRITE), h.UpdateWarehouse)
+	g.DELETE("/:warehouse_id", middleware.Authorize(constants.PERMISSION_WAREHOUSE_WRITE), h.DeleteWarehouse)
+	g.POST("/:warehouse_id/transfer/:to_id", middleware.Authorize(constants.PERMISSION_TRANSFER_WRITE), h.TransferProductWarehouse)
 }
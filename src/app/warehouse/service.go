@@ -3,49 +3,92 @@ package warehouse
 import (
 	"context"
 	"errors"
-	"fmt"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"strconv"
 	"sync"
 	"test-edot/constants"
+	"test-edot/metrics"
+	"test-edot/src/app/audit"
+	"test-edot/src/app/inventory"
+	"test-edot/src/app/shop"
+	"test-edot/src/app/transfer"
 	"test-edot/src/dto"
 	"test-edot/src/factory"
 	"test-edot/src/models"
 	"test-edot/src/repository"
+	"test-edot/src/tracing"
 	"test-edot/util"
 	"time"
 )
 
 type Service interface {
 	AddWarehouse(ctx context.Context, payload dto.PayloadAddWarehouse, userClaim dto.UserClaimJwt) (dto.ResponseWarehouse, error)
-	GetWarehouses(ctx context.Context, userClaim dto.UserClaimJwt, payload dto.ParameterQueryWarehouse) (any, error)
+	GetWarehouses(ctx context.Context, userClaim dto.UserClaimJwt, payload dto.ParameterQueryWarehouse) ([]dto.ResponseWarehouseCapacity, error)
 	ChangeStatusWarehouse(ctx context.Context, userClaim dto.UserClaimJwt, payload dto.ParameterChangeStatusWarehouse) error
-	TransferProductWarehouse(ctx context.Context, userClaim dto.UserClaimJwt, fromId, toId int) error
+	TransferProductWarehouse(ctx context.Context, userClaim dto.UserClaimJwt, fromId, toId int, payload dto.PayloadTransferProductWarehouse) (dto.ResponseTransferProductWarehouse, error)
+	SetReorderThreshold(ctx context.Context, userClaim dto.UserClaimJwt, payload dto.PayloadReorderThreshold) error
+	GetLowStock(ctx context.Context, userClaim dto.UserClaimJwt, payload dto.ParameterQueryLowStock) ([]dto.ResponseLowStock, error)
+	GetWarehouse(ctx context.Context, userClaim dto.UserClaimJwt, warehouseId int) (dto.ResponseWarehouseDetail, error)
+	UpdateWarehouse(ctx context.Context, userClaim dto.UserClaimJwt, warehouseId int, payload dto.PayloadUpdateWarehouse) (dto.ResponseWarehouse, error)
+	DeleteWarehouse(ctx context.Context, userClaim dto.UserClaimJwt, warehouseId int) error
 }
 
 type service struct {
-	Log                   *zap.Logger
-	UserRepository        repository.UserRepositoryInterface
-	ShopRepository        repository.ShopRepositoryInterface
-	ProductRepository     repository.ProductRepositoryInterface
-	WarehouseRepository   repository.WarehouseRepositoryInterface
-	StockLevelRepository  repository.StockLevelRepositoryInterface
-	OrderDetailRepository repository.OrderDetailRepositoryInterface
+	Log                         *zap.Logger
+	UserRepository              repository.UserRepositoryInterface
+	ShopService                 shop.Service
+	ProductRepository           repository.ProductRepositoryInterface
+	WarehouseRepository         repository.WarehouseRepositoryInterface
+	StockLevelRepository        repository.StockLevelRepositoryInterface
+	StockTransferRepository     repository.StockTransferRepositoryInterface
+	StockTransferItemRepository repository.StockTransferItemRepositoryInterface
+	LowStockEventRepository     repository.LowStockEventRepositoryInterface
+	InventoryService            inventory.Service
+	TransferService             transfer.Service
+	AuditService                audit.Service
+}
+
+const warehouseFields = "id,name,location,latitude,longitude,address_line,address_city,address_province,address_postal_code,address_country,capacity,shop_id,user_id,is_active"
+
+// shopWarehouses scopes the query to the warehouses of the shops where the user is granted the permission
+func shopWarehouses(userClaim dto.UserClaimJwt, permission string) (string, []any) {
+	shops, args := shop.MemberShops(userClaim, permission)
+	return "shop_id in (" + shops + ")", args
+}
+
+// warehouseOwner is shopWarehouses for a single warehouse
+func warehouseOwner(userClaim dto.UserClaimJwt, warehouseId int, permission string) (string, []any) {
+	query, args := shopWarehouses(userClaim, permission)
+	return "id = ? and " + query, append([]any{warehouseId}, args...)
 }
 
 func NewService(f *factory.Factory) Service {
 	return &service{
-		Log:                   f.Log,
-		UserRepository:        f.UserRepository,
-		ShopRepository:        f.ShopRepository,
-		ProductRepository:     f.ProductRepository,
-		WarehouseRepository:   f.WarehouseRepository,
-		StockLevelRepository:  f.StockLevelRepository,
-		OrderDetailRepository: f.OrderDetailRepository,
+		Log:                         f.Log,
+		UserRepository:              f.UserRepository,
+		ShopService:                 shop.NewService(f),
+		ProductRepository:           f.ProductRepository,
+		WarehouseRepository:         f.WarehouseRepository,
+		StockLevelRepository:        f.StockLevelRepository,
+		StockTransferRepository:     f.StockTransferRepository,
+		StockTransferItemRepository: f.StockTransferItemRepository,
+		LowStockEventRepository:     f.LowStockEventRepository,
+		InventoryService:            inventory.NewService(f),
+		TransferService:             transfer.NewService(f),
+		AuditService:                audit.NewService(f),
 	}
 }
 
+// log is the logger of the request that made the call, see middleware.RequestId
+func (s *service) log(ctx context.Context) *zap.Logger {
+	return util.Logger(ctx, s.Log)
+}
+
 func (s *service) ChangeStatusWarehouse(ctx context.Context, userClaim dto.UserClaimJwt, payload dto.ParameterChangeStatusWarehouse) error {
+	ctx, span := tracing.Start(ctx, "warehouse.ChangeStatusWarehouse")
+	defer span.End()
+
 	warehouse, stock, err := s.InitiateDataWarehouse(ctx, payload, userClaim)
 	if err != nil {
 		return err
@@ -55,7 +98,7 @@ func (s *service) ChangeStatusWarehouse(ctx context.Context, userClaim dto.UserC
 		return constants.StatusNotSamePrevious
 	}
 
-	if stock.StockCount != 0 && stock.ReservedStockCount != 0 {
+	if stock.StockCount != 0 || stock.ReservedStockCount != 0 {
 		return constants.StockMustEmpty
 	}
 
@@ -64,10 +107,26 @@ func (s *service) ChangeStatusWarehouse(ctx context.Context, userClaim dto.UserC
 		return err
 	}
 
+	action := constants.AUDIT_ACTION_WAREHOUSE_DEACTIVATE
+	if payload.IsActive {
+		action = constants.AUDIT_ACTION_WAREHOUSE_ACTIVATE
+	}
+	s.AuditService.Record(ctx, userClaim, dto.AuditEntry{
+		ShopId:       warehouse.ShopId,
+		Action:       action,
+		ResourceType: constants.AUDIT_RESOURCE_WAREHOUSE,
+		ResourceId:   warehouse.ID,
+		Before:       map[string]any{"is_active": warehouse.IsActive},
+		After:        map[string]any{"is_active": payload.IsActive},
+	})
+
 	return nil
 }
 
 func (s *service) InitiateDataWarehouse(ctx context.Context, payload dto.ParameterChangeStatusWarehouse, userClaim dto.UserClaimJwt) (models.Warehouse, models.StockWarehouse, error) {
+	ctx, span := tracing.Start(ctx, "warehouse.InitiateDataWarehouse")
+	defer span.End()
+
 	var (
 		wg             sync.WaitGroup
 		warehouse      models.Warehouse
@@ -84,20 +143,25 @@ func (s *service) InitiateDataWarehouse(ctx context.Context, payload dto.Paramet
 
 	go func() {
 		defer wg.Done()
-		s.Log.Info("get warehouse")
+
+		c, span := tracing.Start(c, "warehouse.InitiateDataWarehouse.warehouse")
+		defer span.End()
+
+		s.log(ctx).Info("get warehouse")
 
 		select {
 		case <-c.Done():
 			return
 		default:
-			res, err := s.WarehouseRepository.FindOne(c, "id,is_active", "id = ? and user_id = ?", payload.WarehouseId, userClaim.UserId)
+			query, args := warehouseOwner(userClaim, payload.WarehouseId, constants.SHOP_PERMISSION_WAREHOUSE_MANAGE)
+			res, err := s.WarehouseRepository.FindOne(c, "id,shop_id,is_active", query, args...)
 			if err != nil {
 				if errors.Is(err, gorm.ErrRecordNotFound) {
 					errChan <- constants.WarehouseNotFound
 					return
 				}
 
-				s.Log.Error("error fetch warehouses", zap.Error(err), zap.Int("user_id", userClaim.UserId))
+				s.log(ctx).Error("error fetch warehouses", zap.Error(err), zap.Int("user_id", userClaim.UserId))
 				errChan <- err
 				return
 			}
@@ -108,7 +172,11 @@ func (s *service) InitiateDataWarehouse(ctx context.Context, payload dto.Paramet
 
 	go func() {
 		defer wg.Done()
-		s.Log.Info("get stock warehouse")
+
+		c, span := tracing.Start(c, "warehouse.InitiateDataWarehouse.stock")
+		defer span.End()
+
+		s.log(ctx).Info("get stock warehouse")
 
 		select {
 		case <-c.Done():
@@ -116,7 +184,7 @@ func (s *service) InitiateDataWarehouse(ctx context.Context, payload dto.Paramet
 		default:
 			res, err := s.StockLevelRepository.SumStockWarehouse(c, "warehouse_id = ?", payload.WarehouseId)
 			if err != nil {
-				s.Log.Error("error fetch warehouses", zap.Error(err), zap.Int("warehouse_id", payload.WarehouseId))
+				s.log(ctx).Error("error fetch warehouses", zap.Error(err), zap.Int("warehouse_id", payload.WarehouseId))
 				errChan <- err
 				return
 			}
@@ -141,29 +209,131 @@ func (s *service) InitiateDataWarehouse(ctx context.Context, payload dto.Paramet
 	return warehouse, stockWarehouse, nil
 }
 
-func (s *service) GetWarehouses(ctx context.Context, userClaim dto.UserClaimJwt, payload dto.ParameterQueryWarehouse) (any, error) {
-	q := "user_id = ?"
+func (s *service) GetWarehouses(ctx context.Context, userClaim dto.UserClaimJwt, payload dto.ParameterQueryWarehouse) ([]dto.ResponseWarehouseCapacity, error) {
+	ctx, span := tracing.Start(ctx, "warehouse.GetWarehouses")
+	defer span.End()
+
+	q, args := shopWarehouses(userClaim, constants.SHOP_PERMISSION_VIEW)
 	if payload.Status != "" {
-		q += fmt.Sprintf(" AND status = %s", payload.Status)
+		isActive, err := strconv.ParseBool(payload.Status)
+		if err != nil {
+			return nil, constants.WarehouseStatusInvalid
+		}
+
+		q += " and is_active = ?"
+		args = append(args, isActive)
+	}
+
+	if payload.ShopId != 0 {
+		q += " and shop_id = ?"
+		args = append(args, payload.ShopId)
 	}
 
-	fields := "id,name,location,is_active,created_at,updated_at"
-	warehouses, err := s.WarehouseRepository.Find(ctx, fields, q, userClaim.UserId)
+	warehouses, err := s.WarehouseRepository.Find(ctx, warehouseFields+",created_at,updated_at", q, args...)
 	if err != nil {
-		s.Log.Error("error fetch warehouses", zap.Error(err), zap.Int("user_id", userClaim.UserId))
+		s.log(ctx).Error("error fetch warehouses", zap.Error(err), zap.Int("user_id", userClaim.UserId))
 		return nil, err
 	}
 
-	return warehouses, err
+	var warehouseIds []int
+	for _, warehouse := range warehouses {
+		warehouseIds = append(warehouseIds, warehouse.ID)
+	}
+
+	used, incoming, err := s.CapacityUsage(ctx, warehouseIds...)
+	if err != nil {
+		return nil, err
+	}
+
+	response := []dto.ResponseWarehouseCapacity{}
+	for _, warehouse := range warehouses {
+		res := dto.ResponseWarehouseCapacity{
+			ResponseWarehouse: ToResponseWarehouse(warehouse),
+			CreatedAt:         warehouse.CreatedAt,
+			UpdatedAt:         warehouse.UpdatedAt,
+			CapacityUsed:      used[warehouse.ID],
+			CapacityIncoming:  incoming[warehouse.ID],
+		}
+
+		if warehouse.Capacity > 0 {
+			available := warehouse.Capacity - res.CapacityUsed - res.CapacityIncoming
+			if available < 0 {
+				available = 0
+			}
+
+			res.CapacityAvailable = &available
+		}
+
+		response = append(response, res)
+	}
+
+	return response, nil
 }
 
-func (s *service) TransferProductWarehouse(ctx context.Context, userClaim dto.UserClaimJwt, fromId, toId int) error {
+// CapacityUsage returns per warehouse the units on hand and the units still in transit to it
+func (s *service) CapacityUsage(ctx context.Context, warehouseIds ...int) (map[int]int, map[int]int, error) {
+	used := make(map[int]int)
+	incoming := make(map[int]int)
+	if len(warehouseIds) == 0 {
+		return used, incoming, nil
+	}
+
+	stocks, err := s.StockLevelRepository.SumStockGroupWarehouse(ctx, "warehouse_id in ?", warehouseIds)
+	if err != nil {
+		s.log(ctx).Error("error sum stock warehouse", zap.Error(err))
+		return nil, nil, err
+	}
+
+	for _, stock := range stocks {
+		used[stock.WarehouseId] = int(stock.StockCount + stock.ReservedStockCount)
+	}
+
+	incomingStocks, err := s.StockTransferItemRepository.SumIncoming(ctx, "stock_transfers.to_warehouse_id in ? and stock_transfers.status in ?", warehouseIds, constants.TRANSFER_STATUS_OPEN)
+	if err != nil {
+		s.log(ctx).Error("error sum incoming stock", zap.Error(err))
+		return nil, nil, err
+	}
+
+	for _, in := range incomingStocks {
+		incoming[in.WarehouseId] = in.IncomingQty
+	}
+
+	return used, incoming, nil
+}
+
+func ToResponseWarehouse(warehouse models.Warehouse) dto.ResponseWarehouse {
+	return dto.ResponseWarehouse{
+		ID:        warehouse.ID,
+		Name:      warehouse.Name,
+		Location:  warehouse.Location,
+		Latitude:  warehouse.Latitude,
+		Longitude: warehouse.Longitude,
+		Address:   warehouse.Address,
+		Capacity:  warehouse.Capacity,
+		ShopId:    warehouse.ShopId,
+		UserId:    warehouse.UserId,
+		IsActive:  warehouse.IsActive,
+	}
+}
+
+func (s *service) TransferProductWarehouse(ctx context.Context, userClaim dto.UserClaimJwt, fromId, toId int, payload dto.PayloadTransferProductWarehouse) (dto.ResponseTransferProductWarehouse, error) {
+	ctx, span := tracing.Start(ctx, "warehouse.TransferProductWarehouse")
+	defer span.End()
+
+	if fromId == toId {
+		return dto.ResponseTransferProductWarehouse{}, constants.TransferSameWarehouse
+	}
+
+	if err := ValidateTransferItems(payload.Items); err != nil {
+		return dto.ResponseTransferProductWarehouse{}, err
+	}
+
 	fromWarehouse, toWarehouse, err := s.InitialTransferProductWarehouse(ctx, userClaim, fromId, toId)
 	if err != nil {
-		return err
+		return dto.ResponseTransferProductWarehouse{}, err
 	}
 
-	tx := s.StockLevelRepository.Begin()
+	tx := s.StockLevelRepository.Begin().WithContext(ctx)
 	defer func() {
 		if r := recover(); r != nil {
 			tx.Rollback()
@@ -171,27 +341,45 @@ func (s *service) TransferProductWarehouse(ctx context.Context, userClaim dto.Us
 	}()
 
 	if err := tx.Error; err != nil {
-		s.Log.Error("error begin transaction", zap.Error(err))
-		return err
+		s.log(ctx).Error("error begin transaction", zap.Error(err))
+		return dto.ResponseTransferProductWarehouse{}, err
 	}
 
-	if err := s.ProcessTransferProductWarehouse(tx, fromWarehouse, toWarehouse); err != nil {
+	res, err := s.ProcessTransferProductWarehouse(tx, userClaim, fromWarehouse, toWarehouse, payload)
+	if err != nil {
 		tx.Rollback()
-		return err
+		return dto.ResponseTransferProductWarehouse{}, err
 	}
 
 	if err := tx.Commit().Error; err != nil {
-		s.Log.Error("error commit transaction", zap.Error(err))
-		return err
+		s.log(ctx).Error("error commit transaction", zap.Error(err))
+		return dto.ResponseTransferProductWarehouse{}, err
 	}
 
-	return nil
+	metrics.StockTransferTotal.WithLabelValues(constants.TRANSFER_STATUS_DISPATCHED).Inc()
+
+	s.AuditService.Record(ctx, userClaim, dto.AuditEntry{
+		ShopId:       fromWarehouse.ShopId,
+		Action:       constants.AUDIT_ACTION_STOCK_TRANSFER,
+		ResourceType: constants.AUDIT_RESOURCE_TRANSFER,
+		ResourceId:   res.TransferId,
+		After:        res,
+	})
+
+	return res, nil
 }
 
 func (s *service) AddWarehouse(ctx context.Context, payload dto.PayloadAddWarehouse, userClaim dto.UserClaimJwt) (dto.ResponseWarehouse, error) {
-	warehouseDt, err := s.WarehouseRepository.FindOne(ctx, "id,name", "name = ? and user_id = ?", payload.Name, userClaim.UserId)
+	ctx, span := tracing.Start(ctx, "warehouse.AddWarehouse")
+	defer span.End()
+
+	if _, err := s.ShopService.Authorize(ctx, userClaim, payload.ShopId, constants.SHOP_PERMISSION_WAREHOUSE_MANAGE); err != nil {
+		return dto.ResponseWarehouse{}, err
+	}
+
+	warehouseDt, err := s.WarehouseRepository.FindOne(ctx, "id,name", "name = ? and shop_id = ?", payload.Name, payload.ShopId)
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-		s.Log.Error("error finding warehouse", zap.Error(err))
+		s.log(ctx).Error("error finding warehouse", zap.Error(err))
 		return dto.ResponseWarehouse{}, err
 	}
 
@@ -202,6 +390,11 @@ func (s *service) AddWarehouse(ctx context.Context, payload dto.PayloadAddWareho
 	warehouse := models.Warehouse{
 		Name:      payload.Name,
 		Location:  payload.Location,
+		Latitude:  payload.Latitude,
+		Longitude: payload.Longitude,
+		Address:   payload.Address,
+		Capacity:  payload.Capacity,
+		ShopId:    payload.ShopId,
 		UserId:    userClaim.UserId,
 		IsActive:  true,
 		CreatedAt: time.Now().In(util.LocationTime),
@@ -209,19 +402,20 @@ func (s *service) AddWarehouse(ctx context.Context, payload dto.PayloadAddWareho
 	}
 
 	if err := s.WarehouseRepository.Create(ctx, &warehouse); err != nil {
-		s.Log.Error("error creating warehouse", zap.Error(err))
+		s.log(ctx).Error("error creating warehouse", zap.Error(err))
 		return dto.ResponseWarehouse{}, err
 	}
 
-	s.Log.Info("success create warehouse", zap.Any("warehouse", warehouse))
+	s.log(ctx).Info("success create warehouse", zap.Any("warehouse", warehouse))
+	s.AuditService.Record(ctx, userClaim, dto.AuditEntry{
+		ShopId:       warehouse.ShopId,
+		Action:       constants.AUDIT_ACTION_WAREHOUSE_CREATE,
+		ResourceType: constants.AUDIT_RESOURCE_WAREHOUSE,
+		ResourceId:   warehouse.ID,
+		After:        warehouse,
+	})
 
-	return dto.ResponseWarehouse{
-		ID:       warehouse.ID,
-		Name:     warehouse.Name,
-		Location: warehouse.Location,
-		UserId:   warehouse.UserId,
-		IsActive: warehouse.IsActive,
-	}, nil
+	return ToResponseWarehouse(warehouse), nil
 }
 
 func (s *service) InitialTransferProductWarehouse(ctx context.Context, userClaim dto.UserClaimJwt, fromId, toId int) (models.Warehouse, models.Warehouse, error) {
@@ -246,7 +440,8 @@ func (s *service) InitialTransferProductWarehouse(ctx context.Context, userClaim
 		case <-c.Done():
 			return
 		default:
-			res, err := s.WarehouseRepository.FindOne(c, "id,name", "id = ? and user_id = ? and is_active = 1", fromId, userClaim.UserId)
+			query, args := warehouseOwner(userClaim, fromId, constants.SHOP_PERMISSION_STOCK_ADJUST)
+			res, err := s.WarehouseRepository.FindOne(c, "id,name,shop_id", query+" and is_active = 1", args...)
 			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 				errChan <- err
 				return
@@ -267,7 +462,8 @@ func (s *service) InitialTransferProductWarehouse(ctx context.Context, userClaim
 		case <-c.Done():
 			return
 		default:
-			res, err := s.WarehouseRepository.FindOne(c, "id,name", "id = ? and user_id = ? and is_active = 1", toId, userClaim.UserId)
+			query, args := warehouseOwner(userClaim, toId, constants.SHOP_PERMISSION_STOCK_ADJUST)
+			res, err := s.WarehouseRepository.FindOne(c, "id,name,shop_id", query+" and is_active = 1", args...)
 			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 				errChan <- err
 				return
@@ -294,80 +490,411 @@ func (s *service) InitialTransferProductWarehouse(ctx context.Context, userClaim
 		return models.Warehouse{}, models.Warehouse{}, err
 	}
 
+	// stock of a shop never leaves its own warehouses
+	if fromWarehouse.ShopId != toWarehouse.ShopId {
+		return models.Warehouse{}, models.Warehouse{}, constants.WarehouseShopMismatch
+	}
+
 	return fromWarehouse, toWarehouse, nil
 }
 
-func (s *service) ProcessTransferProductWarehouse(tx *gorm.DB, fromWarehouse, toWarehouse models.Warehouse) error {
-	stockLevelFrom, err := s.StockLevelRepository.FindTx(tx, "updated_at asc", "warehouse_id = ?", fromWarehouse.ID)
+// ValidateTransferItems makes sure every product only appears once and dispatches at least one unit, the held
+// reservations of a product only go along with its stock
+func ValidateTransferItems(items []dto.PayloadTransferProductWarehouseItem) error {
+	if len(items) == 0 {
+		return constants.TransferItemInvalid
+	}
+
+	mapProduct := make(map[int]bool)
+	for _, item := range items {
+		if item.ProductId == 0 || mapProduct[item.ProductId] {
+			return constants.TransferItemInvalid
+		}
+
+		if item.Qty < 1 {
+			return constants.QtyMustPositive
+		}
+
+		mapProduct[item.ProductId] = true
+	}
+
+	return nil
+}
+
+// ProcessTransferProductWarehouse dispatches the requested qty of each product in one transfer. The held
+// reservations of a product only ship when asked and move to the destination once the transfer is received
+func (s *service) ProcessTransferProductWarehouse(tx *gorm.DB, userClaim dto.UserClaimJwt, fromWarehouse, toWarehouse models.Warehouse, payload dto.PayloadTransferProductWarehouse) (dto.ResponseTransferProductWarehouse, error) {
+	response := dto.ResponseTransferProductWarehouse{
+		FromWarehouseId: fromWarehouse.ID,
+		ToWarehouseId:   toWarehouse.ID,
+	}
+
+	var items []models.StockTransferItem
+	for _, item := range payload.Items {
+		items = append(items, models.StockTransferItem{ProductId: item.ProductId, Qty: item.Qty, IncludeReserved: item.IncludeReserved})
+	}
+
+	stockTransfer := models.StockTransfer{
+		FromWarehouseId: fromWarehouse.ID,
+		ToWarehouseId:   toWarehouse.ID,
+		UserId:          userClaim.UserId,
+	}
+
+	if err := s.TransferService.DispatchTransferTx(tx, &stockTransfer, items); err != nil {
+		return dto.ResponseTransferProductWarehouse{}, err
+	}
+
+	response.TransferId = stockTransfer.ID
+	response.TransferNo = stockTransfer.TransferNo
+
+	for _, item := range items {
+		summary := dto.ResponseTransferProductWarehouseItem{ProductId: item.ProductId, Qty: item.Qty, ReservedStock: item.ReservedQty}
+
+		response.TotalQty += summary.Qty
+		response.TotalReservedStock += summary.ReservedStock
+		response.Items = append(response.Items, summary)
+	}
+
+	return response, nil
+}
+
+func (s *service) SetReorderThreshold(ctx context.Context, userClaim dto.UserClaimJwt, payload dto.PayloadReorderThreshold) error {
+	ctx, span := tracing.Start(ctx, "warehouse.SetReorderThreshold")
+	defer span.End()
+
+	query, args := warehouseOwner(userClaim, payload.WarehouseId, constants.SHOP_PERMISSION_STOCK_ADJUST)
+	warehouse, err := s.WarehouseRepository.FindOne(ctx, "id,shop_id", query, args...)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return constants.WarehouseNotFound
+		}
+
+		s.log(ctx).Error("error fetch warehouse", zap.Error(err), zap.Int("user_id", userClaim.UserId))
 		return err
 	}
 
-	for _, slF := range stockLevelFrom {
-		stockDest, err := s.StockLevelRepository.FindOneTx(tx, "updated_at asc", "warehouse_id = ? and product_id = ?", toWarehouse.ID, slF.ProductId)
+	stock, err := s.StockLevelRepository.FindOne(ctx, "id,reorder_threshold", "warehouse_id = ? and product_id = ?", payload.WarehouseId, payload.ProductId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return constants.StockLevelNotFound
+		}
+
+		s.log(ctx).Error("error fetch stock level", zap.Error(err), zap.Any("payload", payload))
+		return err
+	}
+
+	tx := s.StockLevelRepository.Begin().WithContext(ctx)
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		s.log(ctx).Error("error begin transaction", zap.Error(err))
+		return err
+	}
+
+	updatedStockLevel := models.StockLevel{ReorderThreshold: payload.ReorderThreshold, UpdatedAt: time.Now().In(util.LocationTime)}
+	if err := s.StockLevelRepository.UpdateOneTx(tx, &updatedStockLevel, "reorder_threshold,updated_at", "id = ?", stock.ID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := s.InventoryService.RecordLowStockTx(tx, stock.ID); err != nil {
+		tx.Rollback()
+		s.log(ctx).Error("error record low stock", zap.Error(err))
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		s.log(ctx).Error("error commit transaction", zap.Error(err))
+		return err
+	}
+
+	s.AuditService.Record(ctx, userClaim, dto.AuditEntry{
+		ShopId:       warehouse.ShopId,
+		Action:       constants.AUDIT_ACTION_REORDER_THRESHOLD,
+		ResourceType: constants.AUDIT_RESOURCE_STOCK,
+		ResourceId:   stock.ID,
+		Before:       map[string]any{"reorder_threshold": stock.ReorderThreshold},
+		After:        map[string]any{"reorder_threshold": payload.ReorderThreshold},
+	})
+
+	return nil
+}
+
+func (s *service) GetLowStock(ctx context.Context, userClaim dto.UserClaimJwt, payload dto.ParameterQueryLowStock) ([]dto.ResponseLowStock, error) {
+	ctx, span := tracing.Start(ctx, "warehouse.GetLowStock")
+	defer span.End()
+
+	warehouses, args := shopWarehouses(userClaim, constants.SHOP_PERMISSION_VIEW)
+	q := "warehouse_id in (select id from warehouses where " + warehouses + ")"
+	if payload.WarehouseId != 0 {
+		q += " and warehouse_id = ?"
+		args = append(args, payload.WarehouseId)
+	}
+
+	stocks, err := s.StockLevelRepository.FindLow(ctx, q, args...)
+	if err != nil {
+		s.log(ctx).Error("error fetch low stock", zap.Error(err), zap.Int("user_id", userClaim.UserId))
+		return nil, err
+	}
+
+	res := []dto.ResponseLowStock{}
+	for _, stock := range stocks {
+		res = append(res, dto.ResponseLowStock{
+			StockId:          stock.ID,
+			ProductId:        stock.ProductId,
+			ProductName:      stock.Product.Name,
+			Sku:              stock.Product.Sku,
+			WarehouseId:      stock.WarehouseId,
+			WarehouseName:    stock.Warehouse.Name,
+			Stock:            stock.Stock,
+			ReservedStock:    stock.ReservedStock,
+			ReorderThreshold: stock.ReorderThreshold,
+			RestockQty:       stock.ReorderThreshold - stock.Stock,
+		})
+	}
+
+	return res, nil
+}
+
+func (s *service) FindWarehouse(ctx context.Context, userClaim dto.UserClaimJwt, warehouseId int, permission string) (models.Warehouse, error) {
+	query, args := warehouseOwner(userClaim, warehouseId, permission)
+	warehouse, err := s.WarehouseRepository.FindOne(ctx, warehouseFields, query, args...)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.Warehouse{}, constants.WarehouseNotFound
+		}
+
+		s.log(ctx).Error("error fetch warehouse", zap.Error(err), zap.Int("user_id", userClaim.UserId))
+		return models.Warehouse{}, err
+	}
+
+	return warehouse, nil
+}
+
+func (s *service) GetWarehouse(ctx context.Context, userClaim dto.UserClaimJwt, warehouseId int) (dto.ResponseWarehouseDetail, error) {
+	ctx, span := tracing.Start(ctx, "warehouse.GetWarehouse")
+	defer span.End()
+
+	warehouse, err := s.FindWarehouse(ctx, userClaim, warehouseId, constants.SHOP_PERMISSION_VIEW)
+	if err != nil {
+		return dto.ResponseWarehouseDetail{}, err
+	}
+
+	stocks, err := s.StockLevelRepository.FindProduct(ctx, "warehouse_id = ?", warehouse.ID)
+	if err != nil {
+		s.log(ctx).Error("error fetch stock level", zap.Error(err), zap.Int("warehouse_id", warehouse.ID))
+		return dto.ResponseWarehouseDetail{}, err
+	}
+
+	response := dto.ResponseWarehouseDetail{
+		ID:        warehouse.ID,
+		ShopId:    warehouse.ShopId,
+		Name:      warehouse.Name,
+		Location:  warehouse.Location,
+		Latitude:  warehouse.Latitude,
+		Longitude: warehouse.Longitude,
+		Address:   warehouse.Address,
+		Capacity:  warehouse.Capacity,
+		IsActive:  warehouse.IsActive,
+		Products:  []dto.ResponseWarehouseProduct{},
+	}
+
+	for _, stock := range stocks {
+		response.Stock += stock.Stock
+		response.ReservedStock += stock.ReservedStock
+		response.Products = append(response.Products, dto.ResponseWarehouseProduct{
+			StockId:          stock.ID,
+			ProductId:        stock.ProductId,
+			ProductName:      stock.Product.Name,
+			Sku:              stock.Product.Sku,
+			Stock:            stock.Stock,
+			ReservedStock:    stock.ReservedStock,
+			ReorderThreshold: stock.ReorderThreshold,
+		})
+	}
+
+	return response, nil
+}
+
+func (s *service) UpdateWarehouse(ctx context.Context, userClaim dto.UserClaimJwt, warehouseId int, payload dto.PayloadUpdateWarehouse) (dto.ResponseWarehouse, error) {
+	ctx, span := tracing.Start(ctx, "warehouse.UpdateWarehouse")
+	defer span.End()
+
+	if payload == (dto.PayloadUpdateWarehouse{}) {
+		return dto.ResponseWarehouse{}, constants.WarehouseUpdateEmpty
+	}
+
+	warehouse, err := s.FindWarehouse(ctx, userClaim, warehouseId, constants.SHOP_PERMISSION_WAREHOUSE_MANAGE)
+	if err != nil {
+		return dto.ResponseWarehouse{}, err
+	}
+
+	before := warehouse
+	if payload.Name != "" && payload.Name != warehouse.Name {
+		existed, err := s.WarehouseRepository.FindOne(ctx, "id", "name = ? and shop_id = ? and id <> ?", payload.Name, warehouse.ShopId, warehouse.ID)
 		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-			return err
+			s.log(ctx).Error("error finding warehouse", zap.Error(err))
+			return dto.ResponseWarehouse{}, err
 		}
 
-		if stockDest == (models.StockLevelProduct{}) {
-			stockLevel := models.StockLevel{
-				ProductId:     slF.ProductId,
-				WarehouseId:   toWarehouse.ID,
-				Stock:         slF.Stock,
-				ReservedStock: slF.ReservedStock,
-				CreatedAt:     time.Now().In(util.LocationTime),
-				UpdatedAt:     time.Now().In(util.LocationTime),
-			}
-			if err := s.StockLevelRepository.Create(tx, &stockLevel); err != nil {
-				s.Log.Error("error creating stock level", zap.Error(err))
-				return err
-			}
+		if existed != (models.Warehouse{}) {
+			return dto.ResponseWarehouse{}, constants.WarehouseAlreadyExisted
+		}
 
-			if err := s.EmptyWarehouse(tx, slF); err != nil {
-				return err
-			}
+		warehouse.Name = payload.Name
+	}
 
-			s.Log.Info("success process stock level", zap.Any("stockLevelId", stockLevel.ID))
-		} else {
-			updatedStockLevel := models.StockLevel{
-				Stock:         slF.Stock + stockDest.Stock,
-				ReservedStock: slF.ReservedStock + stockDest.ReservedStock,
-				UpdatedAt:     time.Now().In(util.LocationTime),
-			}
+	if payload.Location != "" {
+		warehouse.Location = payload.Location
+	}
 
-			if err := s.StockLevelRepository.UpdateOneTx(tx, &updatedStockLevel, "stock,reserved_stock", "warehouse_id = ? and product_id = ?", stockDest.WarehouseId, slF.ProductId); err != nil {
-				return err
-			}
+	if payload.Latitude != nil {
+		warehouse.Latitude = payload.Latitude
+	}
+
+	if payload.Longitude != nil {
+		warehouse.Longitude = payload.Longitude
+	}
 
-			if err := s.HandlingReservedStock(tx, slF.ID, stockDest.ID); err != nil {
-				return err
+	if payload.Address != nil {
+		warehouse.Address = *payload.Address
+	}
+
+	if payload.Capacity != nil && *payload.Capacity != warehouse.Capacity {
+		if *payload.Capacity > 0 {
+			used, incoming, err := s.CapacityUsage(ctx, warehouse.ID)
+			if err != nil {
+				return dto.ResponseWarehouse{}, err
 			}
 
-			if err := s.EmptyWarehouse(tx, slF); err != nil {
-				return err
+			if used[warehouse.ID]+incoming[warehouse.ID] > *payload.Capacity {
+				return dto.ResponseWarehouse{}, constants.WarehouseCapacityTooLow
 			}
 		}
+
+		warehouse.Capacity = *payload.Capacity
 	}
 
-	return nil
+	updatedField := warehouse
+	updatedField.UpdatedAt = time.Now().In(util.LocationTime)
+	fields := "name,location,latitude,longitude,address_line,address_city,address_province,address_postal_code,address_country,capacity,updated_at"
+	if err := s.WarehouseRepository.Update(ctx, updatedField, fields, "id = ?", warehouse.ID); err != nil {
+		s.log(ctx).Error("error update warehouse", zap.Error(err), zap.Int("warehouse_id", warehouse.ID))
+		return dto.ResponseWarehouse{}, err
+	}
+
+	s.log(ctx).Info("success update warehouse", zap.Int("warehouse_id", warehouse.ID))
+	s.AuditService.Record(ctx, userClaim, dto.AuditEntry{
+		ShopId:       warehouse.ShopId,
+		Action:       constants.AUDIT_ACTION_WAREHOUSE_UPDATE,
+		ResourceType: constants.AUDIT_RESOURCE_WAREHOUSE,
+		ResourceId:   warehouse.ID,
+		Before:       before,
+		After:        warehouse,
+	})
+
+	return ToResponseWarehouse(warehouse), nil
 }
 
-func (s *service) EmptyWarehouse(tx *gorm.DB, stockLevel models.StockLevelProduct) error {
-	stockLevelUpdate := models.StockLevel{Stock: 0, ReservedStock: 0, UpdatedAt: time.Now().In(util.LocationTime)}
-	if err := s.StockLevelRepository.UpdateOneTx(tx, &stockLevelUpdate, "stock,reserved_stock", "warehouse_id = ?", stockLevel.WarehouseId); err != nil {
+// DeleteWarehouse soft deletes an empty warehouse, its stock levels stay for the history of orders and
+// transfers but no longer take part in the low stock check
+func (s *service) DeleteWarehouse(ctx context.Context, userClaim dto.UserClaimJwt, warehouseId int) error {
+	ctx, span := tracing.Start(ctx, "warehouse.DeleteWarehouse")
+	defer span.End()
+
+	warehouse, err := s.FindWarehouse(ctx, userClaim, warehouseId, constants.SHOP_PERMISSION_WAREHOUSE_MANAGE)
+	if err != nil {
+		return err
+	}
+
+	tx := s.StockLevelRepository.Begin().WithContext(ctx)
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		s.log(ctx).Error("error begin transaction", zap.Error(err))
+		return err
+	}
+
+	if err := s.CheckWarehouseEmptyTx(tx, warehouse.ID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	now := time.Now().In(util.LocationTime)
+	updatedStockLevel := models.StockLevel{ReorderThreshold: 0, UpdatedAt: now}
+	if err := s.StockLevelRepository.UpdateOneTx(tx, &updatedStockLevel, "reorder_threshold,updated_at", "warehouse_id = ?", warehouse.ID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	resolvedEvent := models.LowStockEvent{IsResolved: true, UpdatedAt: now}
+	if err := s.LowStockEventRepository.UpdateOneTx(tx, &resolvedEvent, "is_resolved,updated_at", "warehouse_id = ? and is_resolved = 0", warehouse.ID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := s.WarehouseRepository.DeleteTx(tx, "id = ?", warehouse.ID); err != nil {
+		tx.Rollback()
+		s.log(ctx).Error("error delete warehouse", zap.Error(err), zap.Int("warehouse_id", warehouse.ID))
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		s.log(ctx).Error("error commit transaction", zap.Error(err))
 		return err
 	}
 
-	s.Log.Info("success empty warehouse", zap.Int("warehouseId", stockLevel.WarehouseId))
+	s.log(ctx).Info("success delete warehouse", zap.Int("warehouse_id", warehouse.ID))
+	s.AuditService.Record(ctx, userClaim, dto.AuditEntry{
+		ShopId:       warehouse.ShopId,
+		Action:       constants.AUDIT_ACTION_WAREHOUSE_DELETE,
+		ResourceType: constants.AUDIT_RESOURCE_WAREHOUSE,
+		ResourceId:   warehouse.ID,
+		Before:       warehouse,
+	})
 	return nil
 }
 
-func (s *service) HandlingReservedStock(tx *gorm.DB, stockIdFrom, stockIdDest int) error {
-	orderDetail := models.OrderDetail{StockId: stockIdDest, UpdatedAt: time.Now().In(util.LocationTime)}
-	if err := s.OrderDetailRepository.UpdateOneTx(tx, &orderDetail, "stock_id,updated_at", "stock_id = ? and expired_at > ?", stockIdFrom, time.Now().Format("2006-01-02 15:04:05")); err != nil {
+// CheckWarehouseEmptyTx locks the warehouse row before the stock and the open transfers are read,
+// so a delete waits for the transactions holding the warehouse and the checks see their result
+func (s *service) CheckWarehouseEmptyTx(tx *gorm.DB, warehouseId int) error {
+	if _, err := s.WarehouseRepository.FindOneTx(tx, "id", "id = ?", warehouseId); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return constants.WarehouseNotFound
+		}
+
+		s.log(util.TxContext(tx)).Error("error fetch warehouse", zap.Error(err), zap.Int("warehouse_id", warehouseId))
+		return err
+	}
+
+	stock, err := s.StockLevelRepository.SumStockWarehouseTx(tx, "warehouse_id = ?", warehouseId)
+	if err != nil {
+		s.log(util.TxContext(tx)).Error("error fetch stock warehouse", zap.Error(err), zap.Int("warehouse_id", warehouseId))
+		return err
+	}
+
+	if stock.StockCount != 0 || stock.ReservedStockCount != 0 {
+		return constants.WarehouseMustEmpty
+	}
+
+	q := "(from_warehouse_id = ? or to_warehouse_id = ?) and status in ?"
+	transfers, err := s.StockTransferRepository.FindTx(tx, "id", q, warehouseId, warehouseId, constants.TRANSFER_STATUS_OPEN)
+	if err != nil {
+		s.log(util.TxContext(tx)).Error("error fetch stock transfers", zap.Error(err), zap.Int("warehouse_id", warehouseId))
 		return err
 	}
 
-	s.Log.Info("success update stock order", zap.Int("stockId", stockIdFrom))
+	if len(transfers) > 0 {
+		return constants.WarehouseOpenTransfer
+	}
+
 	return nil
 }
@@ -0,0 +1,471 @@
+package warehouse
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"strings"
+	"test-edot/constants"
+	"test-edot/src/app/audit"
+	"test-edot/src/dto"
+	"test-edot/src/factory"
+	"test-edot/src/models"
+	"test-edot/src/repository/mocks"
+	"testing"
+)
+
+type (
+	TestValidateTransferItemsData struct {
+		name      string
+		items     []dto.PayloadTransferProductWarehouseItem
+		expectErr error
+	}
+
+	TestChangeStatusWarehouseData struct {
+		name      string
+		isActive  bool
+		stock     models.StockWarehouse
+		expectErr error
+	}
+
+	TestInitialTransferData struct {
+		name      string
+		fromId    int
+		toId      int
+		expectErr error
+	}
+
+	TestGetLowStockData struct {
+		name          string
+		warehouseId   int
+		expectRestock int
+	}
+
+	TestGetWarehouseData struct {
+		name                string
+		findErr             error
+		stocks              []models.StockLevelProduct
+		expectErr           error
+		expectStock         int
+		expectReservedStock int
+	}
+
+	TestUpdateWarehouseData struct {
+		name         string
+		payload      dto.PayloadUpdateWarehouse
+		existed      models.Warehouse
+		used         int64
+		expectErr    error
+		expectUpdate bool
+	}
+
+	TestCheckWarehouseEmptyData struct {
+		name         string
+		findErr      error
+		lockErr      error
+		stock        models.StockWarehouse
+		transfers    []models.StockTransfer
+		expectErr    error
+		expectDelete bool
+	}
+)
+
+func TestValidateTransferItems(t *testing.T) {
+
+	tableTests := []TestValidateTransferItemsData{
+		{
+			name: "test valid items",
+			items: []dto.PayloadTransferProductWarehouseItem{
+				{ProductId: 1, Qty: 5},
+				{ProductId: 2, Qty: 3, IncludeReserved: true},
+			},
+		},
+		{
+			name:      "test empty items",
+			items:     []dto.PayloadTransferProductWarehouseItem{},
+			expectErr: constants.TransferItemInvalid,
+		},
+		{
+			name: "test duplicate product",
+			items: []dto.PayloadTransferProductWarehouseItem{
+				{ProductId: 1, Qty: 5},
+				{ProductId: 1, Qty: 2},
+			},
+			expectErr: constants.TransferItemInvalid,
+		},
+		{
+			name: "test nothing to move",
+			items: []dto.PayloadTransferProductWarehouseItem{
+				{ProductId: 1, Qty: 0},
+			},
+			expectErr: constants.QtyMustPositive,
+		},
+		{
+			name: "test only reservations to move",
+			items: []dto.PayloadTransferProductWarehouseItem{
+				{ProductId: 1, Qty: 0, IncludeReserved: true},
+			},
+			expectErr: constants.QtyMustPositive,
+		},
+		{
+			name: "test negative qty",
+			items: []dto.PayloadTransferProductWarehouseItem{
+				{ProductId: 1, Qty: -1, IncludeReserved: true},
+			},
+			expectErr: constants.QtyMustPositive,
+		},
+	}
+
+	for _, test := range tableTests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateTransferItems(test.items)
+			if test.expectErr != nil {
+				assert.ErrorIs(t, err, test.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestChangeStatusWarehouse(t *testing.T) {
+
+	tableTests := []TestChangeStatusWarehouseData{
+		{
+			name:     "test deactivate empty warehouse",
+			isActive: false,
+		},
+		{
+			name:      "test deactivate warehouse with stock",
+			isActive:  false,
+			stock:     models.StockWarehouse{StockCount: 5},
+			expectErr: constants.StockMustEmpty,
+		},
+		{
+			name:      "test deactivate warehouse with reserved stock",
+			isActive:  false,
+			stock:     models.StockWarehouse{ReservedStockCount: 2},
+			expectErr: constants.StockMustEmpty,
+		},
+		{
+			name:      "test deactivate warehouse with stock and reserved stock",
+			isActive:  false,
+			stock:     models.StockWarehouse{StockCount: 5, ReservedStockCount: 2},
+			expectErr: constants.StockMustEmpty,
+		},
+		{
+			name:      "test same status",
+			isActive:  true,
+			expectErr: constants.StatusNotSamePrevious,
+		},
+	}
+
+	for _, test := range tableTests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			mockWarehouseRepo := new(mocks.WarehouseRepositoryInterface)
+			mockStockRepo := new(mocks.StockLevelRepositoryInterface)
+
+			mockWarehouseRepo.On("FindOne", mock.Anything, "id,shop_id,is_active", mock.Anything, 1, 1, mock.Anything).
+				Return(models.Warehouse{ID: 1, ShopId: 1, IsActive: true}, nil)
+			mockWarehouseRepo.On("Update", ctx, mock.AnythingOfType("models.Warehouse"), "is_active,updated_at", "id = ?", 1).Return(nil)
+			mockStockRepo.On("SumStockWarehouse", mock.Anything, "warehouse_id = ?", 1).Return(test.stock, nil)
+
+			mockAuditRepo := new(mocks.AuditEventRepositoryInterface)
+			mockAuditRepo.On("Create", ctx, mock.Anything).Return(nil)
+			f := factory.Factory{Log: zap.NewNop(), AuditEventRepository: mockAuditRepo}
+
+			s := service{Log: zap.NewNop(), WarehouseRepository: mockWarehouseRepo, StockLevelRepository: mockStockRepo, AuditService: audit.NewService(&f)}
+
+			err := s.ChangeStatusWarehouse(ctx, dto.UserClaimJwt{UserId: 1}, dto.ParameterChangeStatusWarehouse{WarehouseId: 1, IsActive: test.isActive})
+			if test.expectErr != nil {
+				assert.ErrorIs(t, err, test.expectErr)
+				mockWarehouseRepo.AssertNotCalled(t, "Update", ctx, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			} else {
+				assert.NoError(t, err)
+				mockWarehouseRepo.AssertNumberOfCalls(t, "Update", 1)
+			}
+		})
+	}
+}
+
+func TestInitialTransferProductWarehouse(t *testing.T) {
+	// warehouse 3 belongs to a shop the user is not a member of, warehouse 4 to another shop of the user
+	mapWarehouse := map[int]models.Warehouse{
+		1: {ID: 1, Name: "Gudang A", ShopId: 1},
+		2: {ID: 2, Name: "Gudang B", ShopId: 1},
+		4: {ID: 4, Name: "Gudang D", ShopId: 3},
+	}
+
+	tableTests := []TestInitialTransferData{
+		{
+			name:   "test transfer between warehouses of the shop",
+			fromId: 1,
+			toId:   2,
+		},
+		{
+			name:      "test transfer to a warehouse of another shop",
+			fromId:    1,
+			toId:      3,
+			expectErr: constants.ToWarehouseNotFound,
+		},
+		{
+			name:      "test transfer from a warehouse of another shop",
+			fromId:    3,
+			toId:      1,
+			expectErr: constants.FromWarehouseNotFound,
+		},
+		{
+			name:      "test transfer between two shops of the user",
+			fromId:    1,
+			toId:      4,
+			expectErr: constants.WarehouseShopMismatch,
+		},
+	}
+
+	for _, test := range tableTests {
+		t.Run(test.name, func(t *testing.T) {
+			mockWarehouseRepo := new(mocks.WarehouseRepositoryInterface)
+			for _, id := range []int{test.fromId, test.toId} {
+				var findErr error
+				if _, ok := mapWarehouse[id]; !ok {
+					findErr = gorm.ErrRecordNotFound
+				}
+				mockWarehouseRepo.On("FindOne", mock.Anything, "id,name,shop_id", mock.Anything, id, 1, mock.Anything).Return(mapWarehouse[id], findErr)
+			}
+
+			s := service{Log: zap.NewNop(), WarehouseRepository: mockWarehouseRepo}
+
+			from, to, err := s.InitialTransferProductWarehouse(context.Background(), dto.UserClaimJwt{UserId: 1}, test.fromId, test.toId)
+			if test.expectErr != nil {
+				assert.ErrorIs(t, err, test.expectErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.fromId, from.ID)
+			assert.Equal(t, test.toId, to.ID)
+		})
+	}
+}
+
+func TestGetLowStock(t *testing.T) {
+
+	tableTests := []TestGetLowStockData{
+		{
+			name:          "test low stock of every warehouse of the user",
+			expectRestock: 7,
+		},
+		{
+			name:          "test low stock of one warehouse",
+			warehouseId:   3,
+			expectRestock: 7,
+		},
+	}
+
+	for _, test := range tableTests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			mockStockRepo := new(mocks.StockLevelRepositoryInterface)
+
+			stocks := []models.StockLevelLow{{ID: 1, ProductId: 1, WarehouseId: 3, Stock: 3, ReorderThreshold: 10}}
+			if test.warehouseId == 0 {
+				mockStockRepo.On("FindLow", ctx, mock.Anything, 1, mock.Anything).Return(stocks, nil)
+			} else {
+				mockStockRepo.On("FindLow", ctx, mock.MatchedBy(func(q string) bool { return strings.HasSuffix(q, " and warehouse_id = ?") }), 1, mock.Anything, test.warehouseId).
+					Return(stocks, nil)
+			}
+
+			s := service{Log: zap.NewNop(), StockLevelRepository: mockStockRepo}
+
+			res, err := s.GetLowStock(ctx, dto.UserClaimJwt{UserId: 1}, dto.ParameterQueryLowStock{WarehouseId: test.warehouseId})
+			assert.NoError(t, err)
+			assert.Len(t, res, 1)
+			assert.Equal(t, test.expectRestock, res[0].RestockQty)
+		})
+	}
+}
+
+func TestGetWarehouse(t *testing.T) {
+
+	tableTests := []TestGetWarehouseData{
+		{
+			name: "test warehouse detail sums the stock of its products",
+			stocks: []models.StockLevelProduct{
+				{ID: 1, ProductId: 1, Stock: 5, ReservedStock: 1, Product: models.Product{Name: "Kopi", Sku: "KP-1"}},
+				{ID: 2, ProductId: 2, Stock: 3, Product: models.Product{Name: "Teh", Sku: "TH-1"}},
+			},
+			expectStock:         8,
+			expectReservedStock: 1,
+		},
+		{
+			name:      "test warehouse of another shop",
+			findErr:   gorm.ErrRecordNotFound,
+			expectErr: constants.WarehouseNotFound,
+		},
+	}
+
+	for _, test := range tableTests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			mockWarehouseRepo := new(mocks.WarehouseRepositoryInterface)
+			mockStockRepo := new(mocks.StockLevelRepositoryInterface)
+
+			mockWarehouseRepo.On("FindOne", ctx, warehouseFields, mock.Anything, 1, 1, mock.Anything).
+				Return(models.Warehouse{ID: 1, ShopId: 1, Name: "Gudang A", IsActive: true}, test.findErr)
+			mockStockRepo.On("FindProduct", ctx, "warehouse_id = ?", 1).Return(test.stocks, nil)
+
+			s := service{Log: zap.NewNop(), WarehouseRepository: mockWarehouseRepo, StockLevelRepository: mockStockRepo}
+
+			res, err := s.GetWarehouse(ctx, dto.UserClaimJwt{UserId: 1}, 1)
+			if test.expectErr != nil {
+				assert.ErrorIs(t, err, test.expectErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectStock, res.Stock)
+			assert.Equal(t, test.expectReservedStock, res.ReservedStock)
+			assert.Len(t, res.Products, len(test.stocks))
+		})
+	}
+}
+
+func TestUpdateWarehouse(t *testing.T) {
+	capacity := 10
+
+	tableTests := []TestUpdateWarehouseData{
+		{
+			name:      "test empty payload",
+			expectErr: constants.WarehouseUpdateEmpty,
+		},
+		{
+			name:      "test name of another warehouse of the shop",
+			payload:   dto.PayloadUpdateWarehouse{Name: "Gudang B"},
+			existed:   models.Warehouse{ID: 2},
+			expectErr: constants.WarehouseAlreadyExisted,
+		},
+		{
+			name:      "test capacity below the stock on hand",
+			payload:   dto.PayloadUpdateWarehouse{Capacity: &capacity},
+			used:      12,
+			expectErr: constants.WarehouseCapacityTooLow,
+		},
+		{
+			name:         "test rename warehouse",
+			payload:      dto.PayloadUpdateWarehouse{Name: "Gudang B", Location: "Bandung"},
+			expectUpdate: true,
+		},
+	}
+
+	for _, test := range tableTests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			mockWarehouseRepo := new(mocks.WarehouseRepositoryInterface)
+			mockStockRepo := new(mocks.StockLevelRepositoryInterface)
+			mockTransferItemRepo := new(mocks.StockTransferItemRepositoryInterface)
+
+			var findErr error
+			if test.existed.ID == 0 {
+				findErr = gorm.ErrRecordNotFound
+			}
+			mockWarehouseRepo.On("FindOne", ctx, warehouseFields, mock.Anything, 1, 1, mock.Anything).
+				Return(models.Warehouse{ID: 1, ShopId: 1, Name: "Gudang A", Location: "Jakarta", IsActive: true}, nil)
+			mockWarehouseRepo.On("FindOne", ctx, "id", "name = ? and shop_id = ? and id <> ?", "Gudang B", 1, 1).Return(test.existed, findErr)
+			mockWarehouseRepo.On("Update", ctx, mock.AnythingOfType("models.Warehouse"), mock.Anything, "id = ?", 1).Return(nil)
+			mockStockRepo.On("SumStockGroupWarehouse", ctx, "warehouse_id in ?", []int{1}).
+				Return([]models.StockWarehouse{{WarehouseId: 1, StockCount: test.used}}, nil)
+			mockTransferItemRepo.On("SumIncoming", ctx, mock.Anything, []int{1}, constants.TRANSFER_STATUS_OPEN).Return([]models.StockIncoming{}, nil)
+
+			mockAuditRepo := new(mocks.AuditEventRepositoryInterface)
+			mockAuditRepo.On("Create", ctx, mock.Anything).Return(nil)
+			f := factory.Factory{Log: zap.NewNop(), AuditEventRepository: mockAuditRepo}
+
+			s := service{Log: zap.NewNop(), WarehouseRepository: mockWarehouseRepo, StockLevelRepository: mockStockRepo, StockTransferItemRepository: mockTransferItemRepo, AuditService: audit.NewService(&f)}
+
+			res, err := s.UpdateWarehouse(ctx, dto.UserClaimJwt{UserId: 1}, 1, test.payload)
+			if test.expectErr != nil {
+				assert.ErrorIs(t, err, test.expectErr)
+				mockWarehouseRepo.AssertNotCalled(t, "Update", ctx, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.payload.Name, res.Name)
+			assert.Equal(t, test.payload.Location, res.Location)
+			mockWarehouseRepo.AssertNumberOfCalls(t, "Update", 1)
+		})
+	}
+}
+
+func TestDeleteWarehouseNotOwned(t *testing.T) {
+	ctx := context.Background()
+	mockWarehouseRepo := new(mocks.WarehouseRepositoryInterface)
+	mockStockRepo := new(mocks.StockLevelRepositoryInterface)
+
+	mockWarehouseRepo.On("FindOne", ctx, warehouseFields, mock.Anything, 1, 2, mock.Anything).Return(models.Warehouse{}, gorm.ErrRecordNotFound)
+
+	s := service{Log: zap.NewNop(), WarehouseRepository: mockWarehouseRepo, StockLevelRepository: mockStockRepo}
+
+	err := s.DeleteWarehouse(ctx, dto.UserClaimJwt{UserId: 2}, 1)
+	assert.ErrorIs(t, err, constants.WarehouseNotFound)
+	mockStockRepo.AssertNotCalled(t, "Begin")
+}
+
+func TestCheckWarehouseEmptyTx(t *testing.T) {
+
+	tableTests := []TestCheckWarehouseEmptyData{
+		{
+			name: "test empty warehouse without transfer",
+		},
+		{
+			name:      "test warehouse with stock",
+			stock:     models.StockWarehouse{StockCount: 5},
+			expectErr: constants.WarehouseMustEmpty,
+		},
+		{
+			name:      "test warehouse with reserved stock",
+			stock:     models.StockWarehouse{ReservedStockCount: 2},
+			expectErr: constants.WarehouseMustEmpty,
+		},
+		{
+			name:      "test warehouse with open transfer",
+			transfers: []models.StockTransfer{{ID: 3}},
+			expectErr: constants.WarehouseOpenTransfer,
+		},
+		{
+			name:      "test warehouse deleted meanwhile",
+			lockErr:   gorm.ErrRecordNotFound,
+			expectErr: constants.WarehouseNotFound,
+		},
+	}
+
+	for _, test := range tableTests {
+		t.Run(test.name, func(t *testing.T) {
+			tx := gorm.DB{}
+			mockWarehouseRepo := new(mocks.WarehouseRepositoryInterface)
+			mockStockRepo := new(mocks.StockLevelRepositoryInterface)
+			mockTransferRepo := new(mocks.StockTransferRepositoryInterface)
+
+			mockWarehouseRepo.On("FindOneTx", &tx, "id", "id = ?", 1).Return(models.Warehouse{ID: 1}, test.lockErr)
+			mockStockRepo.On("SumStockWarehouseTx", &tx, "warehouse_id = ?", 1).Return(test.stock, nil)
+			mockTransferRepo.On("FindTx", &tx, "id", mock.Anything, 1, 1, constants.TRANSFER_STATUS_OPEN).Return(test.transfers, nil)
+
+			s := service{Log: zap.NewNop(), WarehouseRepository: mockWarehouseRepo, StockLevelRepository: mockStockRepo, StockTransferRepository: mockTransferRepo}
+
+			err := s.CheckWarehouseEmptyTx(&tx, 1)
+			if test.expectErr != nil {
+				assert.ErrorIs(t, err, test.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			// the warehouse row is locked before anything is read
+			if test.lockErr != nil {
+				mockStockRepo.AssertNotCalled(t, "SumStockWarehouseTx", &tx, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package dto
+
+type (
+	PayloadAddress struct {
+		Label         string   `json:"label" binding:"max=50"`
+		RecipientName string   `json:"recipient_name" binding:"required,max=100"`
+		Phone         string   `json:"phone" binding:"required,numeric,min=8,max=15"`
+		Line          string   `json:"line" binding:"required,max=255"`
+		City          string   `json:"city" binding:"required,max=100"`
+		Province      string   `json:"province" binding:"required,max=100"`
+		PostalCode    string   `json:"postal_code" binding:"required,max=20"`
+		Country       string   `json:"country" binding:"required,max=100"`
+		Latitude      *float64 `json:"latitude" binding:"omitempty,min=-90,max=90"`
+		Longitude     *float64 `json:"longitude" binding:"omitempty,min=-180,max=180"`
+		IsDefault     bool     `json:"is_default"`
+	}
+)
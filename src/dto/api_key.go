@@ -0,0 +1,24 @@
+package dto
+
+import "time"
+
+type (
+	PayloadCreateApiKey struct {
+		Name          string   `json:"name" binding:"required"`
+		Permissions   []string `json:"permissions" binding:"required"`
+		ExpiresInDays int      `json:"expires_in_days" binding:"required"`
+	}
+
+	ResponseApiKey struct {
+		Id          int        `json:"id"`
+		ShopId      int        `json:"shop_id"`
+		Name        string     `json:"name"`
+		Prefix      string     `json:"prefix"`
+		Permissions []string   `json:"permissions"`
+		ExpiredAt   time.Time  `json:"expired_at"`
+		LastUsedAt  *time.Time `json:"last_used_at"`
+		RevokedAt   *time.Time `json:"revoked_at"`
+		CreatedAt   time.Time  `json:"created_at"`
+		Key         string     `json:"key,omitempty"`
+	}
+)
@@ -0,0 +1,46 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type (
+	// AuditEntry is what a service records about a change, Before and After are stored as json
+	AuditEntry struct {
+		ShopId       int
+		Action       string
+		ResourceType string
+		ResourceId   int
+		Before       any
+		After        any
+	}
+
+	ParameterQueryAudit struct {
+		ShopId       int    `form:"shop_id"`
+		ActorId      int    `form:"actor_id"`
+		Action       string `form:"action"`
+		ResourceType string `form:"resource_type"`
+		ResourceId   string `form:"resource_id"`
+		From         string `form:"from"`
+		To           string `form:"to"`
+		Offset       int    `form:"offset"`
+		Limit        int    `form:"limit"`
+	}
+
+	ResponseAuditEvent struct {
+		Id           int             `json:"id"`
+		ShopId       *int            `json:"shop_id"`
+		ActorId      *int            `json:"actor_id"`
+		ActorRole    string          `json:"actor_role"`
+		ApiKeyId     *int            `json:"api_key_id"`
+		Action       string          `json:"action"`
+		ResourceType string          `json:"resource_type"`
+		ResourceId   string          `json:"resource_id"`
+		Before       json.RawMessage `json:"before"`
+		After        json.RawMessage `json:"after"`
+		Ip           string          `json:"ip"`
+		RequestId    string          `json:"request_id"`
+		CreatedAt    time.Time       `json:"created_at"`
+	}
+)
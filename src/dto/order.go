@@ -2,7 +2,8 @@ package dto
 
 type (
 	PayloadCreateOrder struct {
-		Items []PayloadCreateOrderItems `json:"items"`
+		AddressId int                       `json:"address_id"`
+		Items     []PayloadCreateOrderItems `json:"items"`
 	}
 
 	PayloadCreateOrderItems struct {
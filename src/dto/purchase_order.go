@@ -0,0 +1,33 @@
+package dto
+
+type (
+	PayloadCreatePurchaseOrder struct {
+		ShopId      int                              `json:"shop_id" binding:"required"`
+		SupplierId  int                              `json:"supplier_id" binding:"required"`
+		WarehouseId int                              `json:"warehouse_id" binding:"required"`
+		Note        string                           `json:"note"`
+		Items       []PayloadCreatePurchaseOrderItem `json:"items"`
+	}
+
+	PayloadCreatePurchaseOrderItem struct {
+		ProductId int     `json:"product_id"`
+		Qty       int     `json:"qty"`
+		Price     float64 `json:"price"`
+	}
+
+	PayloadReceivePurchaseOrder struct {
+		Items []PayloadReceivePurchaseOrderItem `json:"items"`
+	}
+
+	PayloadReceivePurchaseOrderItem struct {
+		ItemId int `json:"item_id"`
+		Qty    int `json:"qty"`
+	}
+
+	ParameterQueryPurchaseOrder struct {
+		ShopId      int    `form:"shop_id"`
+		SupplierId  int    `form:"supplier_id"`
+		WarehouseId int    `form:"warehouse_id"`
+		Status      string `form:"status"`
+	}
+)
@@ -6,7 +6,20 @@ type (
 	}
 
 	ResponseToken struct {
-		Token string `json:"token"`
+		Token             string `json:"token,omitempty"`
+		RefreshToken      string `json:"refresh_token,omitempty"`
+		ExpiresIn         int    `json:"expires_in,omitempty"`
+		TwoFactorRequired bool   `json:"two_factor_required,omitempty"`
+		ChallengeToken    string `json:"challenge_token,omitempty"`
+	}
+
+	ResponseTwoFactorEnroll struct {
+		Secret string `json:"secret"`
+		Uri    string `json:"uri"`
+	}
+
+	ResponseRecoveryCodes struct {
+		RecoveryCodes []string `json:"recovery_codes"`
 	}
 
 	Response struct {
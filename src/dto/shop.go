@@ -11,4 +11,25 @@ type (
 		Name     string `json:"name" gorm:"column:name"`
 		Location string `json:"location" gorm:"column:location"`
 	}
+
+	PayloadAddShopMember struct {
+		Email string `json:"email" binding:"required"`
+		Role  string `json:"role" binding:"required"`
+	}
+
+	PayloadUpdateShopMember struct {
+		Role string `json:"role" binding:"required"`
+	}
+
+	PayloadUpdateShop struct {
+		Name     *string `json:"name"`
+		Location *string `json:"location"`
+	}
+
+	ResponseShopProfile struct {
+		Id       int               `json:"id"`
+		Name     string            `json:"name"`
+		Location string            `json:"location"`
+		Products []ProductResponse `json:"products"`
+	}
 )
@@ -0,0 +1,11 @@
+package dto
+
+type (
+	PayloadSupplier struct {
+		Name     string `json:"name" binding:"required"`
+		Email    string `json:"email"`
+		Phone    string `json:"phone"`
+		Address  string `json:"address"`
+		IsActive *bool  `json:"is_active"`
+	}
+)
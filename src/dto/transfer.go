@@ -0,0 +1,18 @@
+package dto
+
+type (
+	PayloadReceiveTransfer struct {
+		Items []PayloadReceiveTransferItem `json:"items"`
+	}
+
+	PayloadReceiveTransferItem struct {
+		ItemId int `json:"item_id"`
+		Qty    int `json:"qty"`
+	}
+
+	ParameterQueryTransfer struct {
+		FromWarehouseId int    `form:"from_warehouse_id"`
+		ToWarehouseId   int    `form:"to_warehouse_id"`
+		Status          string `form:"status"`
+	}
+)
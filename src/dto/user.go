@@ -1,5 +1,7 @@
 package dto
 
+import "time"
+
 type (
 	RegisterUser struct {
 		FullName string `json:"full_name" binding:"required"`
@@ -9,13 +11,69 @@ type (
 		Password string `json:"password" binding:"required"`
 	}
 
+	PayloadUpdateProfile struct {
+		FullName string `json:"full_name" binding:"required"`
+		Phone    string `json:"phone" binding:"required"`
+	}
+
+	PayloadChangePassword struct {
+		CurrentPassword string `json:"current_password" binding:"required"`
+		NewPassword     string `json:"new_password" binding:"required"`
+	}
+
+	PayloadRequestVerification struct {
+		Channel string `json:"channel" binding:"required"`
+	}
+
+	PayloadConfirmVerification struct {
+		Channel string `json:"channel" binding:"required"`
+		Code    string `json:"code" binding:"required"`
+	}
+
+	PayloadForgotPassword struct {
+		Email string `json:"email" binding:"required"`
+	}
+
+	PayloadResetPassword struct {
+		Email    string `json:"email" binding:"required"`
+		Code     string `json:"code" binding:"required"`
+		Password string `json:"password" binding:"required"`
+	}
+
 	LoginUser struct {
 		Email    string `json:"email" binding:"required"`
 		Password string `json:"password" binding:"required"`
 	}
 
+	PayloadLoginTwoFactor struct {
+		ChallengeToken string `json:"challenge_token" binding:"required"`
+		Code           string `json:"code" binding:"required"`
+	}
+
+	PayloadTwoFactorCode struct {
+		Code string `json:"code" binding:"required"`
+	}
+
+	PayloadDisableTwoFactor struct {
+		Password string `json:"password" binding:"required"`
+		Code     string `json:"code" binding:"required"`
+	}
+
 	UserClaimJwt struct {
-		UserId int    `json:"user_id"`
-		Role   string `json:"role"`
+		UserId         int       `json:"user_id"`
+		Role           string    `json:"role"`
+		TokenId        string    `json:"-"`
+		TokenExpiredAt time.Time `json:"-"`
+		ApiKeyId       int       `json:"-"`
+		ShopId         int       `json:"-"`
+		Permissions    []string  `json:"-"`
+	}
+
+	PayloadRefreshToken struct {
+		RefreshToken string `json:"refresh_token" binding:"required"`
+	}
+
+	PayloadLogout struct {
+		RefreshToken string `json:"refresh_token"`
 	}
 )
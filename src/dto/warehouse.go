@@ -1,13 +1,24 @@
 package dto
 
+import (
+	"test-edot/src/models"
+	"time"
+)
+
 type (
 	PayloadAddWarehouse struct {
-		Name     string `json:"name"`
-		Location string `json:"location"`
+		ShopId    int                     `json:"shop_id" binding:"required"`
+		Name      string                  `json:"name"`
+		Location  string                  `json:"location"`
+		Latitude  *float64                `json:"latitude" binding:"omitempty,min=-90,max=90"`
+		Longitude *float64                `json:"longitude" binding:"omitempty,min=-180,max=180"`
+		Address   models.WarehouseAddress `json:"address"`
+		Capacity  int                     `json:"capacity" binding:"min=0"`
 	}
 
 	ParameterQueryWarehouse struct {
 		Status string `form:"status"`
+		ShopId int    `form:"shop_id"`
 	}
 
 	ParameterChangeStatusWarehouse struct {
@@ -16,10 +27,107 @@ type (
 	}
 
 	ResponseWarehouse struct {
-		ID       int    `json:"id" gorm:"primary_key,column:id"`
-		Name     string `json:"name" gorm:"column:name"`
-		Location string `json:"location" gorm:"column:location"`
-		UserId   int    `json:"user_id,omitempty" gorm:"column:user_id"`
-		IsActive bool   `json:"is_active" gorm:"column:is_active"`
+		ID        int                     `json:"id" gorm:"primary_key,column:id"`
+		Name      string                  `json:"name" gorm:"column:name"`
+		Location  string                  `json:"location" gorm:"column:location"`
+		Latitude  *float64                `json:"latitude" gorm:"column:latitude"`
+		Longitude *float64                `json:"longitude" gorm:"column:longitude"`
+		Address   models.WarehouseAddress `json:"address" gorm:"embedded;embeddedPrefix:address_"`
+		Capacity  int                     `json:"capacity" gorm:"column:capacity"`
+		ShopId    int                     `json:"shop_id" gorm:"column:shop_id"`
+		UserId    int                     `json:"user_id,omitempty" gorm:"column:user_id"`
+		IsActive  bool                    `json:"is_active" gorm:"column:is_active"`
+	}
+
+	ResponseWarehouseCapacity struct {
+		ResponseWarehouse
+		CreatedAt         time.Time `json:"created_at"`
+		UpdatedAt         time.Time `json:"updated_at"`
+		CapacityUsed      int       `json:"capacity_used"`
+		CapacityIncoming  int       `json:"capacity_incoming"`
+		CapacityAvailable *int      `json:"capacity_available"`
+	}
+
+	PayloadReorderThreshold struct {
+		WarehouseId      int `json:"warehouse_id" binding:"required"`
+		ProductId        int `json:"product_id" binding:"required"`
+		ReorderThreshold int `json:"reorder_threshold" binding:"min=0"`
+	}
+
+	ParameterQueryLowStock struct {
+		WarehouseId int `form:"warehouse_id"`
+	}
+
+	ResponseLowStock struct {
+		StockId          int    `json:"stock_id"`
+		ProductId        int    `json:"product_id"`
+		ProductName      string `json:"product_name"`
+		Sku              string `json:"sku"`
+		WarehouseId      int    `json:"warehouse_id"`
+		WarehouseName    string `json:"warehouse_name"`
+		Stock            int    `json:"stock"`
+		ReservedStock    int    `json:"reserved_stock"`
+		ReorderThreshold int    `json:"reorder_threshold"`
+		RestockQty       int    `json:"restock_qty"`
+	}
+
+	PayloadTransferProductWarehouse struct {
+		Items []PayloadTransferProductWarehouseItem `json:"items"`
+	}
+
+	PayloadTransferProductWarehouseItem struct {
+		ProductId       int  `json:"product_id"`
+		Qty             int  `json:"qty"`
+		IncludeReserved bool `json:"include_reserved"`
+	}
+
+	ResponseTransferProductWarehouse struct {
+		TransferId         int                                    `json:"transfer_id,omitempty"`
+		TransferNo         string                                 `json:"transfer_no,omitempty"`
+		FromWarehouseId    int                                    `json:"from_warehouse_id"`
+		ToWarehouseId      int                                    `json:"to_warehouse_id"`
+		Items              []ResponseTransferProductWarehouseItem `json:"items"`
+		TotalQty           int                                    `json:"total_qty"`
+		TotalReservedStock int                                    `json:"total_reserved_stock"`
+	}
+
+	ResponseTransferProductWarehouseItem struct {
+		ProductId     int `json:"product_id"`
+		Qty           int `json:"qty"`
+		ReservedStock int `json:"reserved_stock"`
+	}
+
+	PayloadUpdateWarehouse struct {
+		Name      string                   `json:"name"`
+		Location  string                   `json:"location"`
+		Latitude  *float64                 `json:"latitude" binding:"omitempty,min=-90,max=90"`
+		Longitude *float64                 `json:"longitude" binding:"omitempty,min=-180,max=180"`
+		Address   *models.WarehouseAddress `json:"address"`
+		Capacity  *int                     `json:"capacity" binding:"omitempty,min=0"`
+	}
+
+	ResponseWarehouseDetail struct {
+		ID            int                        `json:"id"`
+		ShopId        int                        `json:"shop_id"`
+		Name          string                     `json:"name"`
+		Location      string                     `json:"location"`
+		Latitude      *float64                   `json:"latitude"`
+		Longitude     *float64                   `json:"longitude"`
+		Address       models.WarehouseAddress    `json:"address"`
+		Capacity      int                        `json:"capacity"`
+		IsActive      bool                       `json:"is_active"`
+		Stock         int                        `json:"stock"`
+		ReservedStock int                        `json:"reserved_stock"`
+		Products      []ResponseWarehouseProduct `json:"products"`
+	}
+
+	ResponseWarehouseProduct struct {
+		StockId          int    `json:"stock_id"`
+		ProductId        int    `json:"product_id"`
+		ProductName      string `json:"product_name"`
+		Sku              string `json:"sku"`
+		Stock            int    `json:"stock"`
+		ReservedStock    int    `json:"reserved_stock"`
+		ReorderThreshold int    `json:"reorder_threshold"`
 	}
 )
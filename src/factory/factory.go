@@ -3,21 +3,41 @@ package factory
 import (
 	"go.uber.org/zap"
 	"test-edot/database"
+	"test-edot/src/notifier"
 	"test-edot/src/repository"
 )
 
 type Factory struct {
-	Log                   *zap.Logger
-	PostRepository        repository.PostRepositoryInterface
-	TagRepository         repository.TagRepositoryInterface
-	PostTagRepository     repository.PostTagRepositoryInterface
-	UserRepository        repository.UserRepositoryInterface
-	ShopRepository        repository.ShopRepositoryInterface
-	ProductRepository     repository.ProductRepositoryInterface
-	WarehouseRepository   repository.WarehouseRepositoryInterface
-	StockLevelRepository  repository.StockLevelRepositoryInterface
-	OrderRepository       repository.OrderRepositoryInterface
-	OrderDetailRepository repository.OrderDetailRepositoryInterface
+	Log                         *zap.Logger
+	Notifier                    notifier.Notifier
+	PostRepository              repository.PostRepositoryInterface
+	TagRepository               repository.TagRepositoryInterface
+	PostTagRepository           repository.PostTagRepositoryInterface
+	UserRepository              repository.UserRepositoryInterface
+	ShopRepository              repository.ShopRepositoryInterface
+	ShopMemberRepository        repository.ShopMemberRepositoryInterface
+	ProductRepository           repository.ProductRepositoryInterface
+	WarehouseRepository         repository.WarehouseRepositoryInterface
+	StockLevelRepository        repository.StockLevelRepositoryInterface
+	OrderRepository             repository.OrderRepositoryInterface
+	OrderDetailRepository       repository.OrderDetailRepositoryInterface
+	LowStockEventRepository     repository.LowStockEventRepositoryInterface
+	SupplierRepository          repository.SupplierRepositoryInterface
+	PurchaseOrderRepository     repository.PurchaseOrderRepositoryInterface
+	PurchaseOrderItemRepository repository.PurchaseOrderItemRepositoryInterface
+	StockTransferRepository     repository.StockTransferRepositoryInterface
+	StockTransferItemRepository repository.StockTransferItemRepositoryInterface
+	StockReservationRepository  repository.StockReservationRepositoryInterface
+	RolePermissionRepository    repository.RolePermissionRepositoryInterface
+	RefreshTokenRepository      repository.RefreshTokenRepositoryInterface
+	RevokedTokenRepository      repository.RevokedTokenRepositoryInterface
+	UserVerificationRepository  repository.UserVerificationRepositoryInterface
+	LoginFailureRepository      repository.LoginFailureRepositoryInterface
+	AuditEventRepository        repository.AuditEventRepositoryInterface
+	UserAddressRepository       repository.UserAddressRepositoryInterface
+	UserRecoveryCodeRepository  repository.UserRecoveryCodeRepositoryInterface
+	LoginChallengeRepository    repository.LoginChallengeRepositoryInterface
+	ApiKeyRepository            repository.ApiKeyRepositoryInterface
 }
 
 func NewFactory() *Factory {
@@ -26,16 +46,35 @@ func NewFactory() *Factory {
 	defer logger.Sync()
 
 	return &Factory{
-		Log:                   logger,
-		PostRepository:        repository.NewPostRepository(db),
-		TagRepository:         repository.NewTagRepository(db),
-		PostTagRepository:     repository.NewPostTagRepository(db),
-		UserRepository:        repository.NewUserRepository(db),
-		ShopRepository:        repository.NewShopRepository(db),
-		ProductRepository:     repository.NewProductRepository(db),
-		WarehouseRepository:   repository.NewWarehouseRepository(db),
-		StockLevelRepository:  repository.NewStockLevelRepository(db),
-		OrderRepository:       repository.NewOrderRepository(db),
-		OrderDetailRepository: repository.NewOrderDetailRepository(db),
+		Log:                         logger,
+		Notifier:                    notifier.NewNotifier(logger),
+		PostRepository:              repository.NewPostRepository(db),
+		TagRepository:               repository.NewTagRepository(db),
+		PostTagRepository:           repository.NewPostTagRepository(db),
+		UserRepository:              repository.NewUserRepository(db),
+		ShopRepository:              repository.NewShopRepository(db),
+		ShopMemberRepository:        repository.NewShopMemberRepository(db),
+		ProductRepository:           repository.NewProductRepository(db),
+		WarehouseRepository:         repository.NewWarehouseRepository(db),
+		StockLevelRepository:        repository.NewStockLevelRepository(db),
+		OrderRepository:             repository.NewOrderRepository(db),
+		OrderDetailRepository:       repository.NewOrderDetailRepository(db),
+		LowStockEventRepository:     repository.NewLowStockEventRepository(db),
+		SupplierRepository:          repository.NewSupplierRepository(db),
+		PurchaseOrderRepository:     repository.NewPurchaseOrderRepository(db),
+		PurchaseOrderItemRepository: repository.NewPurchaseOrderItemRepository(db),
+		StockTransferRepository:     repository.NewStockTransferRepository(db),
+		StockTransferItemRepository: repository.NewStockTransferItemRepository(db),
+		StockReservationRepository:  repository.NewStockReservationRepository(db),
+		RolePermissionRepository:    repository.NewRolePermissionRepository(db),
+		RefreshTokenRepository:      repository.NewRefreshTokenRepository(db),
+		RevokedTokenRepository:      repository.NewRevokedTokenRepository(db),
+		UserVerificationRepository:  repository.NewUserVerificationRepository(db),
+		LoginFailureRepository:      repository.NewLoginFailureRepository(db),
+		AuditEventRepository:        repository.NewAuditEventRepository(db),
+		UserAddressRepository:       repository.NewUserAddressRepository(db),
+		UserRecoveryCodeRepository:  repository.NewUserRecoveryCodeRepository(db),
+		LoginChallengeRepository:    repository.NewLoginChallengeRepository(db),
+		ApiKeyRepository:            repository.NewApiKeyRepository(db),
 	}
 }
@@ -1,47 +1,88 @@
 package http
 
 import (
+	"context"
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.uber.org/zap"
+	"test-edot/constants"
 	"test-edot/metrics"
+	"test-edot/ratelimit"
+	"test-edot/src/app/address"
+	"test-edot/src/app/apikey"
+	"test-edot/src/app/audit"
 	"test-edot/src/app/order"
 	"test-edot/src/app/product"
+	"test-edot/src/app/purchase"
 	"test-edot/src/app/shop"
+	"test-edot/src/app/supplier"
+	"test-edot/src/app/transfer"
 	"test-edot/src/app/user"
 	"test-edot/src/app/warehouse"
 	"test-edot/src/factory"
 	"test-edot/src/middleware"
+	"test-edot/src/policy"
+	"test-edot/src/tracing"
+	"test-edot/util"
 )
 
 func NewHttp(g *gin.Engine, f *factory.Factory) {
+	if err := util.InitJWTKeys(); err != nil {
+		f.Log.Fatal("error load jwt keys", zap.Error(err))
+	}
+	// without trusted proxies X-Forwarded-For is ignored, a client could otherwise pick the ip its login
+	// failures, rate limit and audit events are counted on
+	if err := g.SetTrustedProxies(util.TrustedProxies()); err != nil {
+		f.Log.Fatal("error set trusted proxies", zap.Error(err))
+	}
+	policy.Load(context.Background(), f.Log, f.RolePermissionRepository)
+	middleware.SetRevocationList(f.RevokedTokenRepository)
+	middleware.SetApiKeyStore(f.ApiKeyRepository)
+	middleware.SetRateLimitStore(ratelimit.NewMemoryStore())
+
+	// the handlers pass the gin context to the services, with the fallback it reaches the span and the
+	// cancellation of the request
+	g.ContextWithFallback = true
+
 	g.Use(middleware.CORSMiddleware())
-	g.Use(gin.Logger(), gin.Recovery())
+	g.Use(otelgin.Middleware(tracing.TracerName))
+	g.Use(middleware.RequestId(f.Log), middleware.AccessLog(f.Log), middleware.Metrics(), gin.Recovery())
 
 	g.GET("/test-edot-metrics", metrics.PrometheusHandler())
 
+	// public keys to verify access tokens
+	user.NewHandler(f).JwksRouter(g.Group("/.well-known"))
+
 	// Here we define a router group
-	api := g.Group("/api")
+	api := g.Group("/api", middleware.RateLimit(constants.RATE_LIMIT_GROUP_DEFAULT))
 
+	// product section
 	product.NewHandler(f).ProductRouter(api.Group("/products"))
+	product.NewHandler(f).ProductShopRouter(api.Group("products"))
 
 	// user section
-	userGroup := api.Group("users")
-	user.NewHandler(f).UserRouter(userGroup)
-
-	userGroup.Use(middleware.Bearer())
-	user.NewHandler(f).UserBearerRouter(userGroup)
+	user.NewHandler(f).UserRouter(api.Group("users"))
+	address.NewHandler(f).AddressRouter(api.Group("users/me/addresses"))
 
 	// shop section
 	shopsGroup := api.Group("shops")
-	shopsGroup.Use(middleware.BearerShop())
-
+	shop.NewHandler(f).ShopPublicRouter(shopsGroup)
 	shop.NewHandler(f).ShopRouter(shopsGroup)
+	supplier.NewHandler(f).SupplierRouter(shopsGroup.Group("/:shop_id/suppliers"))
+	apikey.NewHandler(f).ApiKeyRouter(shopsGroup.Group("/:shop_id/api-keys"))
 
-	// product section
-	product.NewHandler(f).ProductBearerShopRouter(api.Group("products"))
+	// warehouse section
+	warehouse.NewHandler(f).WarehouseRouter(api.Group("warehouses"))
 
-	// product section
-	warehouse.NewHandler(f).WarehouseBearerShopRouter(api.Group("warehouses"))
+	// purchase order section
+	purchase.NewHandler(f).PurchaseOrderRouter(api.Group("purchase-orders"))
+
+	// stock transfer section
+	transfer.NewHandler(f).TransferRouter(api.Group("transfers"))
 
 	// order section
-	order.NewHandler(f).OrderBearerRouter(api.Group("orders"))
+	order.NewHandler(f).OrderRouter(api.Group("orders"))
+
+	// audit section
+	audit.NewHandler(f).AuditRouter(api.Group("audit"))
 }
@@ -3,127 +3,193 @@ package middleware
 import (
 	"errors"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 	"net/http"
 	"strings"
 	"test-edot/constants"
 	"test-edot/src/dto"
+	"test-edot/src/models"
+	"test-edot/src/policy"
+	"test-edot/src/repository"
 	"test-edot/util"
+	"time"
 )
 
-func Bearer() gin.HandlerFunc {
-	return func(c *gin.Context) {
+// queryActiveApiKey matches a key that is not revoked and whose creator is still active
+const queryActiveApiKey = "key_hash = ? and revoked_at is null and created_by in (select id from users where is_active = 1)"
 
-		bearerStr := c.GetHeader("Authorization")
-		if bearerStr == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
-				Error: "No Authorization header",
-			})
+var (
+	revokedTokenRepository repository.RevokedTokenRepositoryInterface
+	apiKeyRepository       repository.ApiKeyRepositoryInterface
+)
+
+// SetRevocationList makes Authorize reject access tokens whose jti has been revoked
+func SetRevocationList(repo repository.RevokedTokenRepositoryInterface) {
+	revokedTokenRepository = repo
+}
+
+// SetApiKeyStore makes Authorize accept shop api keys sent in the X-API-Key header
+func SetApiKeyStore(repo repository.ApiKeyRepositoryInterface) {
+	apiKeyRepository = repo
+}
+
+// Authorize validates the bearer token and allows the request only when the role of the token is
+// granted the permission by the policy
+func Authorize(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userClaim, ok := authenticate(c)
+		if !ok {
 			return
 		}
 
-		splits := strings.SplitN(bearerStr, " ", 2)
-		claims, err := util.ValidateJWT(splits[1])
-		if err != nil {
-			if errors.Is(err, constants.BearerExpired) {
-				c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
-					Error: err.Error(),
-				})
-				return
-			}
-
-			c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
-				Error: "Unauthorized",
+		if !policy.Allowed(userClaim.Role, permission) || !apiKeyAllowed(userClaim, permission) {
+			c.AbortWithStatusJSON(http.StatusForbidden, dto.ErrorResponse{
+				Error: "role not allowed",
 			})
 			return
 		}
 
-		c.Set("userClaim", claims["userClaim"])
-
 		c.Next()
 		return
 	}
 }
 
-func BearerShop() gin.HandlerFunc {
-	return func(c *gin.Context) {
+func authenticate(c *gin.Context) (dto.UserClaimJwt, bool) {
+	if apiKey := c.GetHeader(constants.API_KEY_HEADER); apiKey != "" {
+		return authenticateApiKey(c, apiKey)
+	}
 
-		bearerStr := c.GetHeader("Authorization")
-		if bearerStr == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
-				Error: "No Authorization header",
-			})
-			return
-		}
+	bearerStr := c.GetHeader("Authorization")
+	if bearerStr == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error: "No Authorization header",
+		})
+		return dto.UserClaimJwt{}, false
+	}
 
-		splits := strings.SplitN(bearerStr, " ", 2)
-		claims, err := util.ValidateJWT(splits[1])
-		if err != nil {
-			if errors.Is(err, constants.BearerExpired) {
-				c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
-					Error: err.Error(),
-				})
-				return
-			}
+	splits := strings.SplitN(bearerStr, " ", 2)
+	if len(splits) != 2 || !strings.EqualFold(splits[0], "Bearer") {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error: "Unauthorized",
+		})
+		return dto.UserClaimJwt{}, false
+	}
 
+	claims, err := util.ValidateJWT(splits[1])
+	if err != nil {
+		if errors.Is(err, constants.BearerExpired) {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
-				Error: "Unauthorized",
+				Error: err.Error(),
 			})
-			return
+			return dto.UserClaimJwt{}, false
 		}
 
-		userClaim := util.GetClaim(claims["userClaim"].(map[string]interface{}))
-		c.Set("userClaim", userClaim)
+		c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error: "Unauthorized",
+		})
+		return dto.UserClaimJwt{}, false
+	}
+
+	rawClaim, ok := claims["userClaim"].(map[string]interface{})
+	jti, _ := claims["jti"].(string)
+	exp, err := claims.GetExpirationTime()
+	if !ok || jti == "" || err != nil || exp == nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error: "Unauthorized",
+		})
+		return dto.UserClaimJwt{}, false
+	}
 
-		if userClaim.Role != constants.ROLE_ADMIN_SHOP {
+	if revokedTokenRepository != nil {
+		_, err := revokedTokenRepository.FindOne(c, "id", "jti = ?", jti)
+		if err == nil {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
-				Error: "role not allowed",
+				Error: constants.BearerRevoked.Error(),
 			})
-			return
+			return dto.UserClaimJwt{}, false
 		}
 
-		c.Next()
-		return
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Error: err.Error(),
+			})
+			return dto.UserClaimJwt{}, false
+		}
 	}
+
+	userClaim := util.GetClaim(rawClaim)
+	userClaim.TokenId = jti
+	userClaim.TokenExpiredAt = exp.Time
+	c.Set("userClaim", userClaim)
+
+	return userClaim, true
 }
 
-func BearerUser() gin.HandlerFunc {
-	return func(c *gin.Context) {
+// authenticateApiKey the key acts as its creator limited to the shop and the permissions of the key,
+// a key of a deactivated creator is rejected
+func authenticateApiKey(c *gin.Context, rawKey string) (dto.UserClaimJwt, bool) {
+	if apiKeyRepository == nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error: constants.ApiKeyInvalid.Error(),
+		})
+		return dto.UserClaimJwt{}, false
+	}
 
-		bearerStr := c.GetHeader("Authorization")
-		if bearerStr == "" {
+	apiKey, err := apiKeyRepository.FindOne(c, "id,shop_id,created_by,permissions,expired_at,last_used_at",
+		queryActiveApiKey, util.HashToken(rawKey))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
-				Error: "No Authorization header",
+				Error: constants.ApiKeyInvalid.Error(),
 			})
-			return
+			return dto.UserClaimJwt{}, false
 		}
 
-		splits := strings.SplitN(bearerStr, " ", 2)
-		claims, err := util.ValidateJWT(splits[1])
-		if err != nil {
-			if errors.Is(err, constants.BearerExpired) {
-				c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
-					Error: err.Error(),
-				})
-				return
-			}
+		c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return dto.UserClaimJwt{}, false
+	}
 
-			c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
-				Error: "Unauthorized",
-			})
-			return
+	now := time.Now().In(util.LocationTime)
+	if !apiKey.ExpiredAt.After(now) {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error: constants.ApiKeyExpired.Error(),
+		})
+		return dto.UserClaimJwt{}, false
+	}
+
+	// last_used_at is only written once per interval so a busy integration does not update the row on every call
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) >= constants.API_KEY_LAST_USED_INTERVAL {
+		used := models.ApiKey{LastUsedAt: &now}
+		if err := apiKeyRepository.Update(c, &used, "last_used_at", "id = ?", apiKey.ID); err != nil {
+			_ = c.Error(err)
 		}
+	}
 
-		userClaim := util.GetClaim(claims["userClaim"].(map[string]interface{}))
-		c.Set("userClaim", userClaim)
+	userClaim := dto.UserClaimJwt{
+		UserId:      apiKey.CreatedBy,
+		Role:        constants.ROLE_ADMIN_SHOP,
+		ApiKeyId:    apiKey.ID,
+		ShopId:      apiKey.ShopId,
+		Permissions: strings.Split(apiKey.Permissions, ","),
+	}
+	c.Set("userClaim", userClaim)
 
-		if userClaim.Role != constants.ROLE_USER {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
-				Error: "role not allowed",
-			})
-			return
-		}
+	return userClaim, true
+}
 
-		c.Next()
-		return
+// apiKeyAllowed a user token is only checked by the policy, an api key also needs the permission granted to the key
+func apiKeyAllowed(userClaim dto.UserClaimJwt, permission string) bool {
+	if userClaim.ApiKeyId == 0 {
+		return true
 	}
+
+	for _, granted := range userClaim.Permissions {
+		if granted == permission {
+			return true
+		}
+	}
+
+	return false
 }
@@ -0,0 +1,182 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+	"net/http"
+	"net/http/httptest"
+	"test-edot/constants"
+	"test-edot/src/dto"
+	"test-edot/src/models"
+	"test-edot/src/repository/mocks"
+	"test-edot/util"
+	"testing"
+	"time"
+)
+
+type (
+	TestAuthorizeData struct {
+		name         string
+		role         string
+		permission   string
+		withToken    bool
+		expectStatus int
+	}
+)
+
+func TestAuthorize(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("JWT_SECRET_KEY", "secret-test")
+
+	tableTests := []TestAuthorizeData{
+		{
+			name:         "test user create order",
+			role:         constants.ROLE_USER,
+			permission:   constants.PERMISSION_ORDER_CREATE,
+			withToken:    true,
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "test user write warehouse",
+			role:         constants.ROLE_USER,
+			permission:   constants.PERMISSION_WAREHOUSE_WRITE,
+			withToken:    true,
+			expectStatus: http.StatusForbidden,
+		},
+		{
+			name:         "test admin shop write warehouse",
+			role:         constants.ROLE_ADMIN_SHOP,
+			permission:   constants.PERMISSION_WAREHOUSE_WRITE,
+			withToken:    true,
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "test super admin create order",
+			role:         constants.ROLE_SUPER_ADMIN,
+			permission:   constants.PERMISSION_ORDER_CREATE,
+			withToken:    true,
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "test unknown role",
+			role:         "guest",
+			permission:   constants.PERMISSION_PROFILE_READ,
+			withToken:    true,
+			expectStatus: http.StatusForbidden,
+		},
+		{
+			name:         "test without token",
+			permission:   constants.PERMISSION_PROFILE_READ,
+			expectStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, test := range tableTests {
+		t.Run(test.name, func(t *testing.T) {
+			g := gin.New()
+			g.GET("/", Authorize(test.permission), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if test.withToken {
+				token, err := util.GenerateJWT(models.User{Id: 1, Role: test.role}, "jti-test")
+				assert.NoError(t, err)
+				req.Header.Set("Authorization", "Bearer "+token)
+			}
+
+			w := httptest.NewRecorder()
+			g.ServeHTTP(w, req)
+
+			assert.Equal(t, test.expectStatus, w.Code)
+		})
+	}
+}
+
+type (
+	TestAuthorizeApiKeyData struct {
+		name         string
+		apiKey       models.ApiKey
+		findErr      error
+		permission   string
+		expectStatus int
+		expectUsed   bool
+	}
+)
+
+func TestAuthorizeApiKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	now := time.Now().In(util.LocationTime)
+	recentlyUsed := now.Add(-time.Second)
+
+	tableTests := []TestAuthorizeApiKeyData{
+		{
+			name:         "test key with the permission",
+			apiKey:       models.ApiKey{ID: 1, ShopId: 3, CreatedBy: 2, Permissions: "product:read,product:write", ExpiredAt: now.Add(time.Hour)},
+			permission:   constants.PERMISSION_PRODUCT_WRITE,
+			expectStatus: http.StatusOK,
+			expectUsed:   true,
+		},
+		{
+			name:         "test key used recently is not written again",
+			apiKey:       models.ApiKey{ID: 1, ShopId: 3, CreatedBy: 2, Permissions: "product:read", ExpiredAt: now.Add(time.Hour), LastUsedAt: &recentlyUsed},
+			permission:   constants.PERMISSION_PRODUCT_READ,
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "test key without the permission",
+			apiKey:       models.ApiKey{ID: 1, ShopId: 3, CreatedBy: 2, Permissions: "product:read", ExpiredAt: now.Add(time.Hour)},
+			permission:   constants.PERMISSION_WAREHOUSE_WRITE,
+			expectStatus: http.StatusForbidden,
+			expectUsed:   true,
+		},
+		{
+			name:         "test expired key",
+			apiKey:       models.ApiKey{ID: 1, ShopId: 3, CreatedBy: 2, Permissions: "product:read", ExpiredAt: now.Add(-time.Hour)},
+			permission:   constants.PERMISSION_PRODUCT_READ,
+			expectStatus: http.StatusUnauthorized,
+		},
+		{
+			name:         "test unknown or revoked key",
+			findErr:      gorm.ErrRecordNotFound,
+			permission:   constants.PERMISSION_PRODUCT_READ,
+			expectStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, test := range tableTests {
+		t.Run(test.name, func(t *testing.T) {
+			var claim dto.UserClaimJwt
+			mockApiKeyRepo := new(mocks.ApiKeyRepositoryInterface)
+			mockApiKeyRepo.On("FindOne", mock.Anything, mock.Anything, mock.Anything, util.HashToken("edk_test")).Return(test.apiKey, test.findErr)
+			mockApiKeyRepo.On("Update", mock.Anything, mock.AnythingOfType("*models.ApiKey"), "last_used_at", "id = ?", 1).Return(nil)
+
+			SetApiKeyStore(mockApiKeyRepo)
+			defer SetApiKeyStore(nil)
+
+			g := gin.New()
+			g.GET("/", Authorize(test.permission), func(c *gin.Context) {
+				claim = c.Value("userClaim").(dto.UserClaimJwt)
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(constants.API_KEY_HEADER, "edk_test")
+
+			w := httptest.NewRecorder()
+			g.ServeHTTP(w, req)
+
+			assert.Equal(t, test.expectStatus, w.Code)
+			if test.expectStatus == http.StatusOK {
+				assert.Equal(t, test.apiKey.CreatedBy, claim.UserId)
+				assert.Equal(t, test.apiKey.ShopId, claim.ShopId)
+			}
+
+			if test.expectUsed {
+				mockApiKeyRepo.AssertCalled(t, "Update", mock.Anything, mock.AnythingOfType("*models.ApiKey"), "last_used_at", "id = ?", 1)
+			} else {
+				mockApiKeyRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
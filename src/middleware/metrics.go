@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"test-edot/metrics"
+	"time"
+)
+
+const unmatchedRoute = "unmatched"
+
+// Metrics counts every request and observes its latency by method, route template and status, the template
+// keeps path params out of the labels and paths matching no route share one label
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		route := c.FullPath()
+		method := c.Request.Method
+		if route == "" {
+			route = unmatchedRoute
+			if !knownMethod(method) {
+				method = unmatchedRoute
+			}
+		}
+
+		status := strconv.Itoa(c.Writer.Status())
+		metrics.HttpRequestTotal.WithLabelValues(method, route, status).Inc()
+		metrics.HttpRequestDuration.WithLabelValues(method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
+
+// knownMethod stops clients from creating series with made up methods
+func knownMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodOptions:
+		return true
+	}
+
+	return false
+}
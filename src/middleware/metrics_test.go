@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	prometheusModel "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"test-edot/metrics"
+	"testing"
+)
+
+type (
+	TestMetricsData struct {
+		name         string
+		method       string
+		path         string
+		expectMethod string
+		expectRoute  string
+		expectStatus string
+	}
+)
+
+func TestMetrics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tableTests := []TestMetricsData{
+		{
+			name:         "test label by route template",
+			method:       http.MethodGet,
+			path:         "/product/12",
+			expectMethod: http.MethodGet,
+			expectRoute:  "/product/:id",
+			expectStatus: "200",
+		},
+		{
+			name:         "test label failed request",
+			method:       http.MethodPost,
+			path:         "/product/12",
+			expectMethod: http.MethodPost,
+			expectRoute:  "/product/:id",
+			expectStatus: "400",
+		},
+		{
+			name:         "test label unmatched path",
+			method:       http.MethodGet,
+			path:         "/unknown/12",
+			expectMethod: http.MethodGet,
+			expectRoute:  unmatchedRoute,
+			expectStatus: "404",
+		},
+		{
+			name:         "test label unknown method",
+			method:       "FOO",
+			path:         "/unknown/12",
+			expectMethod: unmatchedRoute,
+			expectRoute:  unmatchedRoute,
+			expectStatus: "404",
+		},
+	}
+
+	for _, test := range tableTests {
+		t.Run(test.name, func(t *testing.T) {
+			g := gin.New()
+			g.Use(Metrics())
+			g.GET("/product/:id", func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+			g.POST("/product/:id", func(c *gin.Context) {
+				c.Status(http.StatusBadRequest)
+			})
+
+			counter := metrics.HttpRequestTotal.WithLabelValues(test.expectMethod, test.expectRoute, test.expectStatus)
+			histogram := metrics.HttpRequestDuration.WithLabelValues(test.expectMethod, test.expectRoute, test.expectStatus)
+			before := testutil.ToFloat64(counter)
+			beforeSamples := sampleCount(t, histogram)
+
+			g.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(test.method, test.path, nil))
+
+			assert.Equal(t, before+1, testutil.ToFloat64(counter))
+			assert.Equal(t, beforeSamples+1, sampleCount(t, histogram))
+		})
+	}
+}
+
+func sampleCount(t *testing.T, observer prometheus.Observer) uint64 {
+	var m prometheusModel.Metric
+	assert.NoError(t, observer.(prometheus.Metric).Write(&m))
+
+	return m.GetHistogram().GetSampleCount()
+}
@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"test-edot/constants"
+	"test-edot/ratelimit"
+	"test-edot/src/dto"
+	"test-edot/util"
+	"time"
+)
+
+var rateLimitStore ratelimit.Store
+
+// SetRateLimitStore turns on RateLimit, without a store every request is let through
+func SetRateLimitStore(store ratelimit.Store) {
+	rateLimitStore = store
+}
+
+// RateLimit takes a token from the bucket of the client for the route group, the groups have their own
+// buckets so a client throttled on login can still use the rest of the api
+func RateLimit(group string) gin.HandlerFunc {
+	quota := constants.MapRateLimitQuota[group]
+	policy := fmt.Sprintf("%d;w=%d", quota.Limit, int(quota.Period.Seconds()))
+
+	return func(c *gin.Context) {
+		if rateLimitStore == nil {
+			c.Next()
+			return
+		}
+
+		res, err := rateLimitStore.Take(c, group+":"+rateLimitKey(c, quota.Key), quota.Limit, quota.Period)
+		if err != nil {
+			// a broken store must not take the api down with it
+			_ = c.Error(err)
+			c.Next()
+			return
+		}
+
+		c.Header(constants.RATE_LIMIT_HEADER_LIMIT, strconv.Itoa(res.Limit))
+		c.Header(constants.RATE_LIMIT_HEADER_REMAINING, strconv.Itoa(res.Remaining))
+		c.Header(constants.RATE_LIMIT_HEADER_RESET, strconv.Itoa(seconds(res.Reset)))
+		c.Header(constants.RATE_LIMIT_HEADER_POLICY, policy)
+
+		if !res.Allowed {
+			c.Header(constants.RETRY_AFTER_HEADER, strconv.Itoa(seconds(res.RetryAfter)))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, dto.ErrorResponse{
+				Error: constants.RateLimitExceeded.Error(),
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// rateLimitKey runs before Authorize, the api key and the bearer token are only read for their id and a key
+// or token that is not valid falls back to the ip, a made up key per request must not get a fresh bucket
+func rateLimitKey(c *gin.Context, key string) string {
+	if key == constants.RATE_LIMIT_KEY_CLIENT {
+		if apiKeyId := validApiKeyId(c, c.GetHeader(constants.API_KEY_HEADER)); apiKeyId != 0 {
+			return "key:" + strconv.Itoa(apiKeyId)
+		}
+
+		splits := strings.SplitN(c.GetHeader("Authorization"), " ", 2)
+		if len(splits) == 2 && strings.EqualFold(splits[0], "Bearer") {
+			if claims, err := util.ValidateJWT(splits[1]); err == nil {
+				if rawClaim, ok := claims["userClaim"].(map[string]interface{}); ok {
+					if userId := util.GetClaim(rawClaim).UserId; userId != 0 {
+						return "user:" + strconv.Itoa(userId)
+					}
+				}
+			}
+		}
+	}
+
+	return "ip:" + c.ClientIP()
+}
+
+func validApiKeyId(c *gin.Context, rawKey string) int {
+	if rawKey == "" || apiKeyRepository == nil {
+		return 0
+	}
+
+	apiKey, err := apiKeyRepository.FindOne(c, "id,expired_at",
+		queryActiveApiKey, util.HashToken(rawKey))
+	if err != nil || !apiKey.ExpiredAt.After(time.Now().In(util.LocationTime)) {
+		return 0
+	}
+
+	return apiKey.ID
+}
+
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,203 @@
+package middleware
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+	"net/http"
+	"net/http/httptest"
+	"test-edot/constants"
+	"test-edot/ratelimit"
+	"test-edot/src/models"
+	"test-edot/src/repository/mocks"
+	"test-edot/util"
+	"testing"
+	"time"
+)
+
+type (
+	TestRateLimitData struct {
+		name            string
+		group           string
+		userId          int
+		remoteAddr      string
+		expectStatus    int
+		expectRemaining string
+	}
+
+	TestRateLimitApiKeyData struct {
+		name            string
+		apiKey          string
+		remoteAddr      string
+		expectStatus    int
+		expectRemaining string
+	}
+)
+
+func TestRateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("JWT_SECRET_KEY", "secret-test")
+
+	SetRateLimitStore(ratelimit.NewMemoryStore())
+	defer SetRateLimitStore(nil)
+
+	g := gin.New()
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	g.POST("/login", RateLimit(constants.RATE_LIMIT_GROUP_LOGIN), ok)
+	g.POST("/orders", RateLimit(constants.RATE_LIMIT_GROUP_ORDER), ok)
+
+	send := func(test TestRateLimitData) *httptest.ResponseRecorder {
+		path := "/login"
+		if test.group == constants.RATE_LIMIT_GROUP_ORDER {
+			path = "/orders"
+		}
+
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		req.RemoteAddr = test.remoteAddr
+		if test.userId != 0 {
+			token, err := util.GenerateJWT(models.User{Id: test.userId, Role: constants.ROLE_USER}, "jti-test")
+			assert.NoError(t, err)
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		w := httptest.NewRecorder()
+		g.ServeHTTP(w, req)
+		return w
+	}
+
+	// use up the login quota of the first ip and the order quota of the first user
+	login := constants.MapRateLimitQuota[constants.RATE_LIMIT_GROUP_LOGIN]
+	for i := 0; i < login.Limit; i++ {
+		send(TestRateLimitData{group: constants.RATE_LIMIT_GROUP_LOGIN, remoteAddr: "10.0.0.1:1234"})
+	}
+
+	order := constants.MapRateLimitQuota[constants.RATE_LIMIT_GROUP_ORDER]
+	for i := 0; i < order.Limit; i++ {
+		send(TestRateLimitData{group: constants.RATE_LIMIT_GROUP_ORDER, userId: 1, remoteAddr: "10.0.0.3:1234"})
+	}
+
+	tableTests := []TestRateLimitData{
+		{
+			name:         "test login over quota",
+			group:        constants.RATE_LIMIT_GROUP_LOGIN,
+			remoteAddr:   "10.0.0.1:1234",
+			expectStatus: http.StatusTooManyRequests,
+		},
+		{
+			name:            "test login from another ip",
+			group:           constants.RATE_LIMIT_GROUP_LOGIN,
+			remoteAddr:      "10.0.0.2:1234",
+			expectStatus:    http.StatusOK,
+			expectRemaining: "9",
+		},
+		{
+			name:         "test order over quota",
+			group:        constants.RATE_LIMIT_GROUP_ORDER,
+			userId:       1,
+			remoteAddr:   "10.0.0.4:1234",
+			expectStatus: http.StatusTooManyRequests,
+		},
+		{
+			name:            "test order of another user on the same ip",
+			group:           constants.RATE_LIMIT_GROUP_ORDER,
+			userId:          2,
+			remoteAddr:      "10.0.0.3:1234",
+			expectStatus:    http.StatusOK,
+			expectRemaining: "9",
+		},
+		{
+			name:            "test order quota apart from login quota",
+			group:           constants.RATE_LIMIT_GROUP_ORDER,
+			remoteAddr:      "10.0.0.1:1234",
+			expectStatus:    http.StatusOK,
+			expectRemaining: "9",
+		},
+	}
+
+	for _, test := range tableTests {
+		t.Run(test.name, func(t *testing.T) {
+			w := send(test)
+
+			assert.Equal(t, test.expectStatus, w.Code)
+			assert.Equal(t, "10", w.Header().Get(constants.RATE_LIMIT_HEADER_LIMIT))
+			assert.Equal(t, "10;w=60", w.Header().Get(constants.RATE_LIMIT_HEADER_POLICY))
+			if test.expectStatus == http.StatusTooManyRequests {
+				assert.Equal(t, "0", w.Header().Get(constants.RATE_LIMIT_HEADER_REMAINING))
+				assert.Equal(t, "6", w.Header().Get(constants.RETRY_AFTER_HEADER))
+			} else {
+				assert.Equal(t, test.expectRemaining, w.Header().Get(constants.RATE_LIMIT_HEADER_REMAINING))
+				assert.Empty(t, w.Header().Get(constants.RETRY_AFTER_HEADER))
+			}
+		})
+	}
+}
+
+func TestRateLimitApiKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	SetRateLimitStore(ratelimit.NewMemoryStore())
+	defer SetRateLimitStore(nil)
+
+	mockApiKeyRepo := new(mocks.ApiKeyRepositoryInterface)
+	mockApiKeyRepo.On("FindOne", mock.Anything, mock.Anything, mock.Anything, util.HashToken("edk_valid")).
+		Return(models.ApiKey{ID: 7, ExpiredAt: time.Now().Add(time.Hour)}, nil)
+	mockApiKeyRepo.On("FindOne", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(models.ApiKey{}, gorm.ErrRecordNotFound)
+	SetApiKeyStore(mockApiKeyRepo)
+	defer SetApiKeyStore(nil)
+
+	g := gin.New()
+	g.POST("/orders", RateLimit(constants.RATE_LIMIT_GROUP_ORDER), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	send := func(test TestRateLimitApiKeyData) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/orders", nil)
+		req.RemoteAddr = test.remoteAddr
+		req.Header.Set(constants.API_KEY_HEADER, test.apiKey)
+
+		w := httptest.NewRecorder()
+		g.ServeHTTP(w, req)
+		return w
+	}
+
+	// every request of the first ip comes with a different made up key
+	order := constants.MapRateLimitQuota[constants.RATE_LIMIT_GROUP_ORDER]
+	for i := 0; i < order.Limit; i++ {
+		send(TestRateLimitApiKeyData{apiKey: fmt.Sprintf("edk_bogus_%d", i), remoteAddr: "10.0.1.1:1234"})
+	}
+
+	tableTests := []TestRateLimitApiKeyData{
+		{
+			name:         "test bogus keys of the same ip share one bucket",
+			apiKey:       "edk_bogus_new",
+			remoteAddr:   "10.0.1.1:1234",
+			expectStatus: http.StatusTooManyRequests,
+		},
+		{
+			name:            "test valid key on the same ip has its own bucket",
+			apiKey:          "edk_valid",
+			remoteAddr:      "10.0.1.1:1234",
+			expectStatus:    http.StatusOK,
+			expectRemaining: "9",
+		},
+		{
+			name:            "test bogus key from another ip",
+			apiKey:          "edk_bogus_0",
+			remoteAddr:      "10.0.1.2:1234",
+			expectStatus:    http.StatusOK,
+			expectRemaining: "9",
+		},
+	}
+
+	for _, test := range tableTests {
+		t.Run(test.name, func(t *testing.T) {
+			w := send(test)
+
+			assert.Equal(t, test.expectStatus, w.Code)
+			if test.expectStatus == http.StatusOK {
+				assert.Equal(t, test.expectRemaining, w.Header().Get(constants.RATE_LIMIT_HEADER_REMAINING))
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"test-edot/constants"
+	"test-edot/src/dto"
+	"test-edot/util"
+	"time"
+)
+
+// RequestId keeps the X-Request-Id sent by the client or creates one, the id is sent back in the response and
+// the logger of the request carries it so every log line of the request can be found by the id
+func RequestId(log *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestId := c.GetHeader(constants.REQUEST_ID_HEADER)
+		if !validRequestId(requestId) {
+			id, err := util.GenerateRandomToken(constants.REQUEST_ID_BYTES)
+			if err != nil {
+				_ = c.Error(err)
+			}
+			requestId = id
+		}
+
+		c.Set(constants.REQUEST_ID_KEY, requestId)
+		c.Header(constants.REQUEST_ID_HEADER, requestId)
+
+		requestLog := log.With(zap.String("request_id", requestId))
+		if span := trace.SpanContextFromContext(c.Request.Context()); span.IsValid() {
+			requestLog = requestLog.With(zap.String("trace_id", span.TraceID().String()))
+		}
+		c.Request = c.Request.WithContext(util.ContextWithLogger(c.Request.Context(), requestLog))
+
+		c.Next()
+	}
+}
+
+// validRequestId only takes ids that are safe to log and send back in a header
+func validRequestId(requestId string) bool {
+	if requestId == "" || len(requestId) > constants.REQUEST_ID_MAX_LENGTH {
+		return false
+	}
+
+	for _, r := range requestId {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// AccessLog writes one structured line per request once it is served, it runs after RequestId so the line
+// has the request id
+func AccessLog(log *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		path := c.Request.URL.Path
+
+		c.Next()
+
+		status := c.Writer.Status()
+		fields := []zap.Field{
+			zap.String("method", c.Request.Method),
+			zap.String("path", path),
+			zap.String("route", c.FullPath()),
+			zap.Int("status", status),
+			zap.Duration("latency", time.Since(start)),
+			zap.String("ip", c.ClientIP()),
+			zap.Int("size", c.Writer.Size()),
+		}
+
+		if userClaim, ok := c.Value("userClaim").(dto.UserClaimJwt); ok {
+			fields = append(fields, zap.Int("user_id", userClaim.UserId))
+			if userClaim.ApiKeyId != 0 {
+				fields = append(fields, zap.Int("api_key_id", userClaim.ApiKeyId))
+			}
+		}
+
+		if errs := c.Errors.ByType(gin.ErrorTypeAny).String(); errs != "" {
+			fields = append(fields, zap.String("errors", errs))
+		}
+
+		requestLog := util.Logger(c, log)
+		switch {
+		case status >= 500:
+			requestLog.Error("request", fields...)
+		case status >= 400:
+			requestLog.Warn("request", fields...)
+		default:
+			requestLog.Info("request", fields...)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"test-edot/constants"
+	"test-edot/src/dto"
+	"test-edot/util"
+	"testing"
+)
+
+type (
+	TestRequestLogData struct {
+		name            string
+		requestId       string
+		userId          int
+		status          int
+		expectRequestId string
+		expectLevel     zapcore.Level
+	}
+)
+
+func TestRequestLog(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tableTests := []TestRequestLogData{
+		{
+			name:            "test keep request id of the client",
+			requestId:       "order-123",
+			userId:          1,
+			status:          http.StatusOK,
+			expectRequestId: "order-123",
+			expectLevel:     zapcore.InfoLevel,
+		},
+		{
+			name:        "test create request id",
+			status:      http.StatusBadRequest,
+			expectLevel: zapcore.WarnLevel,
+		},
+		{
+			name:        "test replace unsafe request id",
+			requestId:   "order 123\n",
+			status:      http.StatusInternalServerError,
+			expectLevel: zapcore.ErrorLevel,
+		},
+		{
+			name:        "test replace too long request id",
+			requestId:   strings.Repeat("a", constants.REQUEST_ID_MAX_LENGTH+1),
+			status:      http.StatusOK,
+			expectLevel: zapcore.InfoLevel,
+		},
+	}
+
+	for _, test := range tableTests {
+		t.Run(test.name, func(t *testing.T) {
+			core, logs := observer.New(zapcore.DebugLevel)
+			log := zap.New(core)
+
+			g := gin.New()
+			g.Use(RequestId(log), AccessLog(log))
+			g.GET("/", func(c *gin.Context) {
+				if test.userId != 0 {
+					c.Set("userClaim", dto.UserClaimJwt{UserId: test.userId})
+				}
+
+				// what a service logs through the context it gets from the handler
+				util.Logger(c, zap.NewNop()).Info("service")
+				c.Status(test.status)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if test.requestId != "" {
+				req.Header.Set(constants.REQUEST_ID_HEADER, test.requestId)
+			}
+
+			w := httptest.NewRecorder()
+			g.ServeHTTP(w, req)
+
+			requestId := w.Header().Get(constants.REQUEST_ID_HEADER)
+			if test.expectRequestId != "" {
+				assert.Equal(t, test.expectRequestId, requestId)
+			} else {
+				assert.Len(t, requestId, constants.REQUEST_ID_BYTES*2)
+			}
+
+			entries := logs.AllUntimed()
+			assert.Len(t, entries, 2)
+			for _, entry := range entries {
+				assert.Equal(t, requestId, entry.ContextMap()["request_id"])
+			}
+
+			access := entries[1]
+			assert.Equal(t, "request", access.Message)
+			assert.Equal(t, test.expectLevel, access.Level)
+			assert.Equal(t, int64(test.status), access.ContextMap()["status"])
+			if test.userId != 0 {
+				assert.Equal(t, int64(test.userId), access.ContextMap()["user_id"])
+			} else {
+				assert.NotContains(t, access.ContextMap(), "user_id")
+			}
+		})
+	}
+}
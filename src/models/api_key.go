@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+type (
+	ApiKey struct {
+		ID          int        `json:"id" gorm:"primary_key,column:id"`
+		ShopId      int        `json:"shop_id" gorm:"column:shop_id"`
+		CreatedBy   int        `json:"created_by" gorm:"column:created_by"`
+		Name        string     `json:"name" gorm:"column:name"`
+		Prefix      string     `json:"prefix" gorm:"column:prefix"`
+		KeyHash     string     `json:"-" gorm:"column:key_hash"`
+		Permissions string     `json:"permissions" gorm:"column:permissions"`
+		ExpiredAt   time.Time  `json:"expired_at" gorm:"column:expired_at"`
+		LastUsedAt  *time.Time `json:"last_used_at" gorm:"column:last_used_at"`
+		RevokedAt   *time.Time `json:"revoked_at" gorm:"column:revoked_at"`
+		CreatedAt   time.Time  `json:"created_at" gorm:"column:created_at"`
+		UpdatedAt   time.Time  `json:"updated_at" gorm:"column:updated_at"`
+	}
+)
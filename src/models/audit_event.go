@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+type (
+	AuditEvent struct {
+		ID           int       `json:"id" gorm:"primary_key,column:id"`
+		ShopId       *int      `json:"shop_id" gorm:"column:shop_id"`
+		ActorId      *int      `json:"actor_id" gorm:"column:actor_id"`
+		ActorRole    string    `json:"actor_role" gorm:"column:actor_role"`
+		ApiKeyId     *int      `json:"api_key_id" gorm:"column:api_key_id"`
+		Action       string    `json:"action" gorm:"column:action"`
+		ResourceType string    `json:"resource_type" gorm:"column:resource_type"`
+		ResourceId   string    `json:"resource_id" gorm:"column:resource_id"`
+		BeforeValue  *string   `json:"before_value" gorm:"column:before_value"`
+		AfterValue   *string   `json:"after_value" gorm:"column:after_value"`
+		Ip           string    `json:"ip" gorm:"column:ip"`
+		RequestId    string    `json:"request_id" gorm:"column:request_id"`
+		CreatedAt    time.Time `json:"created_at" gorm:"column:created_at"`
+	}
+)
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+type (
+	LoginFailure struct {
+		ID           int        `json:"id" gorm:"primary_key,column:id"`
+		Scope        string     `json:"scope" gorm:"column:scope"`
+		Identifier   string     `json:"identifier" gorm:"column:identifier"`
+		FailedCount  int        `json:"failed_count" gorm:"column:failed_count"`
+		LastFailedAt *time.Time `json:"last_failed_at" gorm:"column:last_failed_at"`
+		LockedUntil  *time.Time `json:"locked_until" gorm:"column:locked_until"`
+		CreatedAt    time.Time  `json:"created_at" gorm:"column:created_at"`
+		UpdatedAt    time.Time  `json:"updated_at" gorm:"column:updated_at"`
+	}
+)
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+type (
+	LowStockEvent struct {
+		ID               int       `json:"id" gorm:"primaryKey;column:id"`
+		StockId          int       `json:"stock_id" gorm:"column:stock_id"`
+		ProductId        int       `json:"product_id" gorm:"column:product_id"`
+		WarehouseId      int       `json:"warehouse_id" gorm:"column:warehouse_id"`
+		Stock            int       `json:"stock" gorm:"column:stock"`
+		ReorderThreshold int       `json:"reorder_threshold" gorm:"column:reorder_threshold"`
+		IsNotified       bool      `json:"is_notified" gorm:"column:is_notified"`
+		IsResolved       bool      `json:"is_resolved" gorm:"column:is_resolved"`
+		CreatedAt        time.Time `json:"created_at" gorm:"column:created_at"`
+		UpdatedAt        time.Time `json:"updated_at" gorm:"column:updated_at"`
+	}
+)
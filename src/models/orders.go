@@ -4,15 +4,17 @@ import "time"
 
 type (
 	Order struct {
-		Id        int       `json:"id" gorm:"primaryKey;column:id"`
-		OrderNo   string    `json:"order_no" gorm:"column:order_no"`
-		UserId    int       `json:"user_id" gorm:"column:user_id"`
-		IsPayment bool      `json:"is_payment" gorm:"column:is_payment"`
-		IsRelease bool      `json:"is_release" gorm:"column:is_release"`
-		Total     float64   `json:"total" gorm:"column:total"`
-		ExpiredAt time.Time `json:"expired_at" gorm:"column:expired_at"`
-		CreatedAt time.Time `json:"created_at" gorm:"column:created_at"`
-		UpdatedAt time.Time `json:"updated_at" gorm:"column:updated_at"`
+		Id              int             `json:"id" gorm:"primaryKey;column:id"`
+		OrderNo         string          `json:"order_no" gorm:"column:order_no"`
+		UserId          int             `json:"user_id" gorm:"column:user_id"`
+		AddressId       *int            `json:"address_id" gorm:"column:address_id"`
+		IsPayment       bool            `json:"is_payment" gorm:"column:is_payment"`
+		IsRelease       bool            `json:"is_release" gorm:"column:is_release"`
+		Total           float64         `json:"total" gorm:"column:total"`
+		ShippingAddress ShippingAddress `json:"shipping_address" gorm:"embedded;embeddedPrefix:shipping_"`
+		ExpiredAt       time.Time       `json:"expired_at" gorm:"column:expired_at"`
+		CreatedAt       time.Time       `json:"created_at" gorm:"column:created_at"`
+		UpdatedAt       time.Time       `json:"updated_at" gorm:"column:updated_at"`
 	}
 
 	OrderDetail struct {
@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+type (
+	PurchaseOrder struct {
+		ID          int       `json:"id" gorm:"primaryKey;column:id"`
+		PoNo        string    `json:"po_no" gorm:"column:po_no"`
+		ShopId      int       `json:"shop_id" gorm:"column:shop_id"`
+		SupplierId  int       `json:"supplier_id" gorm:"column:supplier_id"`
+		WarehouseId int       `json:"warehouse_id" gorm:"column:warehouse_id"`
+		UserId      int       `json:"user_id" gorm:"column:user_id"`
+		Status      string    `json:"status" gorm:"column:status"`
+		Note        string    `json:"note" gorm:"column:note"`
+		Total       float64   `json:"total" gorm:"column:total"`
+		CreatedAt   time.Time `json:"created_at" gorm:"column:created_at"`
+		UpdatedAt   time.Time `json:"updated_at" gorm:"column:updated_at"`
+	}
+
+	PurchaseOrderItem struct {
+		ID              int       `json:"id" gorm:"primaryKey;column:id"`
+		PurchaseOrderId int       `json:"purchase_order_id" gorm:"column:purchase_order_id"`
+		ProductId       int       `json:"product_id" gorm:"column:product_id"`
+		Qty             int       `json:"qty" gorm:"column:qty"`
+		ReceivedQty     int       `json:"received_qty" gorm:"column:received_qty"`
+		Price           float64   `json:"price" gorm:"column:price"`
+		CreatedAt       time.Time `json:"created_at" gorm:"column:created_at"`
+		UpdatedAt       time.Time `json:"updated_at" gorm:"column:updated_at"`
+	}
+
+	PurchaseOrderReceipt struct {
+		ID                  int       `json:"id" gorm:"primaryKey;column:id"`
+		PurchaseOrderId     int       `json:"purchase_order_id" gorm:"column:purchase_order_id"`
+		PurchaseOrderItemId int       `json:"purchase_order_item_id" gorm:"column:purchase_order_item_id"`
+		StockId             int       `json:"stock_id" gorm:"column:stock_id"`
+		Qty                 int       `json:"qty" gorm:"column:qty"`
+		UserId              int       `json:"user_id" gorm:"column:user_id"`
+		CreatedAt           time.Time `json:"created_at" gorm:"column:created_at"`
+	}
+
+	PurchaseOrderDetail struct {
+		ID          int                 `json:"id" gorm:"primaryKey;column:id"`
+		PoNo        string              `json:"po_no" gorm:"column:po_no"`
+		ShopId      int                 `json:"shop_id" gorm:"column:shop_id"`
+		SupplierId  int                 `json:"supplier_id" gorm:"column:supplier_id"`
+		Supplier    Supplier            `json:"supplier" gorm:"foreignKey:supplier_id"`
+		WarehouseId int                 `json:"warehouse_id" gorm:"column:warehouse_id"`
+		Warehouse   Warehouse           `json:"warehouse" gorm:"foreignKey:warehouse_id"`
+		UserId      int                 `json:"user_id" gorm:"column:user_id"`
+		Status      string              `json:"status" gorm:"column:status"`
+		Note        string              `json:"note" gorm:"column:note"`
+		Total       float64             `json:"total" gorm:"column:total"`
+		Items       []PurchaseOrderItem `json:"items" gorm:"foreignKey:purchase_order_id;references:ID"`
+		CreatedAt   time.Time           `json:"created_at" gorm:"column:created_at"`
+		UpdatedAt   time.Time           `json:"updated_at" gorm:"column:updated_at"`
+	}
+)
+
+func (PurchaseOrderDetail) TableName() string {
+	return "purchase_orders"
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+type (
+	RolePermission struct {
+		ID         int       `json:"id" gorm:"primary_key,column:id"`
+		Role       string    `json:"role" gorm:"column:role"`
+		Permission string    `json:"permission" gorm:"column:permission"`
+		CreatedAt  time.Time `json:"created_at" gorm:"column:created_at"`
+		UpdatedAt  time.Time `json:"updated_at" gorm:"column:updated_at"`
+	}
+)
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+type (
+	ShopMember struct {
+		ID        int       `json:"id" gorm:"primary_key,column:id"`
+		ShopId    int       `json:"shop_id" gorm:"column:shop_id"`
+		UserId    int       `json:"user_id" gorm:"column:user_id"`
+		Role      string    `json:"role" gorm:"column:role"`
+		CreatedAt time.Time `json:"created_at" gorm:"column:created_at"`
+		UpdatedAt time.Time `json:"updated_at" gorm:"column:updated_at"`
+	}
+
+	ShopMemberUser struct {
+		ShopMember
+		FullName string `json:"full_name" gorm:"column:full_name"`
+		Email    string `json:"email" gorm:"column:email"`
+	}
+)
@@ -4,32 +4,65 @@ import "time"
 
 type (
 	StockLevel struct {
-		ID            int       `gorm:"primaryKey" json:"id"`
-		ProductId     int       `json:"product_id"  gorm:"column:product_id"`
-		WarehouseId   int       `json:"warehouse_id" gorm:"column:warehouse_id"`
-		Stock         int       `json:"stock"  gorm:"column:stock"`
-		ReservedStock int       `json:"reserved_stock"  gorm:"column:reserved_stock"`
-		CreatedAt     time.Time `json:"created_at"`
-		UpdatedAt     time.Time `json:"updated_at"`
+		ID               int       `gorm:"primaryKey" json:"id"`
+		ProductId        int       `json:"product_id"  gorm:"column:product_id"`
+		WarehouseId      int       `json:"warehouse_id" gorm:"column:warehouse_id"`
+		Stock            int       `json:"stock"  gorm:"column:stock"`
+		ReservedStock    int       `json:"reserved_stock"  gorm:"column:reserved_stock"`
+		ReorderThreshold int       `json:"reorder_threshold"  gorm:"column:reorder_threshold"`
+		CreatedAt        time.Time `json:"created_at"`
+		UpdatedAt        time.Time `json:"updated_at"`
 	}
 
 	StockLevelProduct struct {
-		ID            int       `gorm:"primaryKey" json:"id"`
-		ProductId     int       `json:"product_id"  gorm:"column:product_id"`
-		WarehouseId   int       `json:"warehouse_id" gorm:"column:warehouse_id"`
-		Stock         int       `json:"stock"  gorm:"column:stock"`
-		Product       Product   `json:"product"  gorm:"column:product;foreignKey:product_id"`
-		ReservedStock int       `json:"reserved_stock"  gorm:"column:reserved_stock"`
-		CreatedAt     time.Time `json:"created_at"`
-		UpdatedAt     time.Time `json:"updated_at"`
+		ID               int       `gorm:"primaryKey" json:"id"`
+		ProductId        int       `json:"product_id"  gorm:"column:product_id"`
+		WarehouseId      int       `json:"warehouse_id" gorm:"column:warehouse_id"`
+		Stock            int       `json:"stock"  gorm:"column:stock"`
+		Product          Product   `json:"product"  gorm:"column:product;foreignKey:product_id"`
+		ReservedStock    int       `json:"reserved_stock"  gorm:"column:reserved_stock"`
+		ReorderThreshold int       `json:"reorder_threshold"  gorm:"column:reorder_threshold"`
+		CreatedAt        time.Time `json:"created_at"`
+		UpdatedAt        time.Time `json:"updated_at"`
 	}
 
 	StockWarehouse struct {
+		WarehouseId        int   `json:"warehouse_id,omitempty"  gorm:"column:warehouse_id"`
 		StockCount         int64 `json:"stock_count"  gorm:"column:stock_count"`
 		ReservedStockCount int64 `json:"reserved_stock_count"  gorm:"column:reserved_stock_count"`
 	}
+
+	StockLevelLow struct {
+		ID               int       `gorm:"primaryKey" json:"id"`
+		ProductId        int       `json:"product_id"  gorm:"column:product_id"`
+		WarehouseId      int       `json:"warehouse_id" gorm:"column:warehouse_id"`
+		Stock            int       `json:"stock"  gorm:"column:stock"`
+		ReservedStock    int       `json:"reserved_stock"  gorm:"column:reserved_stock"`
+		ReorderThreshold int       `json:"reorder_threshold"  gorm:"column:reorder_threshold"`
+		Product          Product   `json:"product"  gorm:"foreignKey:product_id"`
+		Warehouse        Warehouse `json:"warehouse"  gorm:"foreignKey:warehouse_id"`
+	}
+
+	StockReserved struct {
+		StockId     int `json:"stock_id" gorm:"column:stock_id"`
+		ReservedQty int `json:"reserved_qty" gorm:"column:reserved_qty"`
+	}
+
+	StockDrift struct {
+		StockId               int `json:"stock_id"`
+		ProductId             int `json:"product_id"`
+		WarehouseId           int `json:"warehouse_id"`
+		Stock                 int `json:"stock"`
+		ReservedStock         int `json:"reserved_stock"`
+		ExpectedStock         int `json:"expected_stock"`
+		ExpectedReservedStock int `json:"expected_reserved_stock"`
+	}
 )
 
 func (StockLevelProduct) TableName() string {
 	return "stock_levels"
 }
+
+func (StockLevelLow) TableName() string {
+	return "stock_levels"
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+type (
+	StockReservation struct {
+		ID              int       `json:"id" gorm:"primaryKey;column:id"`
+		StockId         int       `json:"stock_id" gorm:"column:stock_id"`
+		ProductId       int       `json:"product_id" gorm:"column:product_id"`
+		WarehouseId     int       `json:"warehouse_id" gorm:"column:warehouse_id"`
+		StockTransferId *int      `json:"stock_transfer_id" gorm:"column:stock_transfer_id"`
+		Qty             int       `json:"qty" gorm:"column:qty"`
+		OwnerType       string    `json:"owner_type" gorm:"column:owner_type"`
+		OwnerId         int       `json:"owner_id" gorm:"column:owner_id"`
+		State           string    `json:"state" gorm:"column:state"`
+		ExpiredAt       time.Time `json:"expired_at" gorm:"column:expired_at"`
+		CreatedAt       time.Time `json:"created_at" gorm:"column:created_at"`
+		UpdatedAt       time.Time `json:"updated_at" gorm:"column:updated_at"`
+	}
+)
@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+type (
+	StockTransfer struct {
+		ID              int        `json:"id" gorm:"primaryKey;column:id"`
+		TransferNo      string     `json:"transfer_no" gorm:"column:transfer_no"`
+		FromWarehouseId int        `json:"from_warehouse_id" gorm:"column:from_warehouse_id"`
+		ToWarehouseId   int        `json:"to_warehouse_id" gorm:"column:to_warehouse_id"`
+		UserId          int        `json:"user_id" gorm:"column:user_id"`
+		Status          string     `json:"status" gorm:"column:status"`
+		Note            string     `json:"note" gorm:"column:note"`
+		DispatchedAt    *time.Time `json:"dispatched_at" gorm:"column:dispatched_at"`
+		ReceivedAt      *time.Time `json:"received_at" gorm:"column:received_at"`
+		CreatedAt       time.Time  `json:"created_at" gorm:"column:created_at"`
+		UpdatedAt       time.Time  `json:"updated_at" gorm:"column:updated_at"`
+	}
+
+	StockIncoming struct {
+		WarehouseId int `json:"warehouse_id" gorm:"column:warehouse_id"`
+		IncomingQty int `json:"incoming_qty" gorm:"column:incoming_qty"`
+	}
+
+	StockTransferItem struct {
+		ID              int       `json:"id" gorm:"primaryKey;column:id"`
+		StockTransferId int       `json:"stock_transfer_id" gorm:"column:stock_transfer_id"`
+		ProductId       int       `json:"product_id" gorm:"column:product_id"`
+		Qty             int       `json:"qty" gorm:"column:qty"`
+		ReceivedQty     int       `json:"received_qty" gorm:"column:received_qty"`
+		ReservedQty     int       `json:"reserved_qty" gorm:"column:reserved_qty"`
+		IncludeReserved bool      `json:"-" gorm:"-"`
+		CreatedAt       time.Time `json:"created_at" gorm:"column:created_at"`
+		UpdatedAt       time.Time `json:"updated_at" gorm:"column:updated_at"`
+	}
+
+	StockTransferDetail struct {
+		ID              int                 `json:"id" gorm:"primaryKey;column:id"`
+		TransferNo      string              `json:"transfer_no" gorm:"column:transfer_no"`
+		FromWarehouseId int                 `json:"from_warehouse_id" gorm:"column:from_warehouse_id"`
+		FromWarehouse   Warehouse           `json:"from_warehouse" gorm:"foreignKey:from_warehouse_id"`
+		ToWarehouseId   int                 `json:"to_warehouse_id" gorm:"column:to_warehouse_id"`
+		ToWarehouse     Warehouse           `json:"to_warehouse" gorm:"foreignKey:to_warehouse_id"`
+		UserId          int                 `json:"user_id" gorm:"column:user_id"`
+		Status          string              `json:"status" gorm:"column:status"`
+		Note            string              `json:"note" gorm:"column:note"`
+		Items           []StockTransferItem `json:"items" gorm:"foreignKey:stock_transfer_id;references:ID"`
+		DispatchedAt    *time.Time          `json:"dispatched_at" gorm:"column:dispatched_at"`
+		ReceivedAt      *time.Time          `json:"received_at" gorm:"column:received_at"`
+		CreatedAt       time.Time           `json:"created_at" gorm:"column:created_at"`
+		UpdatedAt       time.Time           `json:"updated_at" gorm:"column:updated_at"`
+	}
+)
+
+func (StockTransferDetail) TableName() string {
+	return "stock_transfers"
+}
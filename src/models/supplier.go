@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+type (
+	Supplier struct {
+		ID        int       `json:"id" gorm:"primaryKey;column:id"`
+		ShopId    int       `json:"shop_id" gorm:"column:shop_id"`
+		Name      string    `json:"name" gorm:"column:name"`
+		Email     string    `json:"email" gorm:"column:email"`
+		Phone     string    `json:"phone" gorm:"column:phone"`
+		Address   string    `json:"address" gorm:"column:address"`
+		IsActive  bool      `json:"is_active" gorm:"column:is_active"`
+		CreatedAt time.Time `json:"created_at" gorm:"column:created_at"`
+		UpdatedAt time.Time `json:"updated_at" gorm:"column:updated_at"`
+	}
+)
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+type (
+	RefreshToken struct {
+		ID        int        `json:"id" gorm:"primary_key,column:id"`
+		UserId    int        `json:"user_id" gorm:"column:user_id"`
+		FamilyId  string     `json:"family_id" gorm:"column:family_id"`
+		TokenHash string     `json:"-" gorm:"column:token_hash"`
+		AccessJti string     `json:"access_jti" gorm:"column:access_jti"`
+		ExpiredAt time.Time  `json:"expired_at" gorm:"column:expired_at"`
+		RotatedAt *time.Time `json:"rotated_at" gorm:"column:rotated_at"`
+		RevokedAt *time.Time `json:"revoked_at" gorm:"column:revoked_at"`
+		CreatedAt time.Time  `json:"created_at" gorm:"column:created_at"`
+		UpdatedAt time.Time  `json:"updated_at" gorm:"column:updated_at"`
+	}
+
+	RevokedToken struct {
+		ID        int       `json:"id" gorm:"primary_key,column:id"`
+		Jti       string    `json:"jti" gorm:"column:jti"`
+		UserId    int       `json:"user_id" gorm:"column:user_id"`
+		ExpiredAt time.Time `json:"expired_at" gorm:"column:expired_at"`
+		CreatedAt time.Time `json:"created_at" gorm:"column:created_at"`
+	}
+)
@@ -4,14 +4,52 @@ import "time"
 
 type (
 	User struct {
-		Id        int       `json:"id"`
-		FullName  string    `json:"full_name" gorm:"column:full_name"`
-		Password  string    `json:"password" gorm:"column:password"`
-		Role      string    `json:"role" gorm:"column:role"`
-		Email     string    `json:"email" gorm:"column:email"`
-		Phone     string    `json:"phone" gorm:"column:phone"`
-		CreatedAt time.Time `json:"created_at" gorm:"column:created_at"`
-		UpdatedAt time.Time `json:"updated_at" gorm:"column:updated_at"`
+		Id              int        `json:"id"`
+		FullName        string     `json:"full_name" gorm:"column:full_name"`
+		Password        string     `json:"-" gorm:"column:password"`
+		Role            string     `json:"role" gorm:"column:role"`
+		IsActive        bool       `json:"is_active" gorm:"column:is_active"`
+		DeactivatedAt   *time.Time `json:"deactivated_at" gorm:"column:deactivated_at"`
+		Email           string     `json:"email" gorm:"column:email"`
+		Phone           string     `json:"phone" gorm:"column:phone"`
+		CreatedAt       time.Time  `json:"created_at" gorm:"column:created_at"`
+		UpdatedAt       time.Time  `json:"updated_at" gorm:"column:updated_at"`
+		EmailVerifiedAt *time.Time `json:"email_verified_at" gorm:"column:email_verified_at"`
+		PhoneVerifiedAt *time.Time `json:"phone_verified_at" gorm:"column:phone_verified_at"`
+		TotpSecret      *string    `json:"-" gorm:"column:totp_secret"`
+		TotpEnabledAt   *time.Time `json:"totp_enabled_at" gorm:"column:totp_enabled_at"`
+		TotpLastStep    int64      `json:"-" gorm:"column:totp_last_step"`
+	}
+
+	UserVerification struct {
+		ID        int        `json:"id" gorm:"primary_key,column:id"`
+		UserId    int        `json:"user_id" gorm:"column:user_id"`
+		Purpose   string     `json:"purpose" gorm:"column:purpose"`
+		Target    string     `json:"target" gorm:"column:target"`
+		CodeHash  string     `json:"-" gorm:"column:code_hash"`
+		Attempts  int        `json:"attempts" gorm:"column:attempts"`
+		ExpiredAt time.Time  `json:"expired_at" gorm:"column:expired_at"`
+		UsedAt    *time.Time `json:"used_at" gorm:"column:used_at"`
+		CreatedAt time.Time  `json:"created_at" gorm:"column:created_at"`
+		UpdatedAt time.Time  `json:"updated_at" gorm:"column:updated_at"`
+	}
+
+	UserRecoveryCode struct {
+		ID        int        `json:"id" gorm:"primary_key,column:id"`
+		UserId    int        `json:"user_id" gorm:"column:user_id"`
+		CodeHash  string     `json:"-" gorm:"column:code_hash"`
+		UsedAt    *time.Time `json:"used_at" gorm:"column:used_at"`
+		CreatedAt time.Time  `json:"created_at" gorm:"column:created_at"`
+	}
+
+	LoginChallenge struct {
+		ID        int        `json:"id" gorm:"primary_key,column:id"`
+		UserId    int        `json:"user_id" gorm:"column:user_id"`
+		TokenHash string     `json:"-" gorm:"column:token_hash"`
+		Attempts  int        `json:"attempts" gorm:"column:attempts"`
+		ExpiredAt time.Time  `json:"expired_at" gorm:"column:expired_at"`
+		UsedAt    *time.Time `json:"used_at" gorm:"column:used_at"`
+		CreatedAt time.Time  `json:"created_at" gorm:"column:created_at"`
 	}
 
 	UserWithJwt struct {
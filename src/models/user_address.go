@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+type (
+	UserAddress struct {
+		ID        int             `json:"id" gorm:"primary_key,column:id"`
+		UserId    int             `json:"user_id" gorm:"column:user_id"`
+		Label     string          `json:"label" gorm:"column:label"`
+		Address   ShippingAddress `json:"address" gorm:"embedded"`
+		IsDefault bool            `json:"is_default" gorm:"column:is_default"`
+		CreatedAt time.Time       `json:"created_at" gorm:"column:created_at"`
+		UpdatedAt time.Time       `json:"updated_at" gorm:"column:updated_at"`
+	}
+
+	ShippingAddress struct {
+		RecipientName string   `json:"recipient_name" gorm:"column:recipient_name"`
+		Phone         string   `json:"phone" gorm:"column:phone"`
+		Line          string   `json:"line" gorm:"column:line"`
+		City          string   `json:"city" gorm:"column:city"`
+		Province      string   `json:"province" gorm:"column:province"`
+		PostalCode    string   `json:"postal_code" gorm:"column:postal_code"`
+		Country       string   `json:"country" gorm:"column:country"`
+		Latitude      *float64 `json:"latitude" gorm:"column:latitude"`
+		Longitude     *float64 `json:"longitude" gorm:"column:longitude"`
+	}
+)
@@ -1,13 +1,32 @@
 package models
 
-import "time"
+import (
+	"gorm.io/gorm"
+	"time"
+)
 
-type Warehouse struct {
-	ID        int       `json:"id" gorm:"primary_key,column:id"`
-	Name      string    `json:"name" gorm:"column:name"`
-	Location  string    `json:"location" gorm:"column:location"`
-	UserId    int       `json:"user_id,omitempty" gorm:"column:user_id"`
-	IsActive  bool      `json:"is_active" gorm:"column:is_active"`
-	CreatedAt time.Time `json:"created_at" gorm:"column:created_at"`
-	UpdatedAt time.Time `json:"updated_at" gorm:"column:updated_at"`
-}
+type (
+	Warehouse struct {
+		ID        int              `json:"id" gorm:"primary_key,column:id"`
+		Name      string           `json:"name" gorm:"column:name"`
+		Location  string           `json:"location" gorm:"column:location"`
+		Latitude  *float64         `json:"latitude" gorm:"column:latitude"`
+		Longitude *float64         `json:"longitude" gorm:"column:longitude"`
+		Address   WarehouseAddress `json:"address" gorm:"embedded;embeddedPrefix:address_"`
+		Capacity  int              `json:"capacity" gorm:"column:capacity"`
+		ShopId    int              `json:"shop_id" gorm:"column:shop_id"`
+		UserId    int              `json:"user_id,omitempty" gorm:"column:user_id"`
+		IsActive  bool             `json:"is_active" gorm:"column:is_active"`
+		CreatedAt time.Time        `json:"created_at" gorm:"column:created_at"`
+		UpdatedAt time.Time        `json:"updated_at" gorm:"column:updated_at"`
+		DeletedAt gorm.DeletedAt   `json:"-" gorm:"column:deleted_at"`
+	}
+
+	WarehouseAddress struct {
+		Line       string `json:"line" gorm:"column:line"`
+		City       string `json:"city" gorm:"column:city"`
+		Province   string `json:"province" gorm:"column:province"`
+		PostalCode string `json:"postal_code" gorm:"column:postal_code"`
+		Country    string `json:"country" gorm:"column:country"`
+	}
+)
@@ -0,0 +1,85 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"go.uber.org/zap"
+	"os"
+	"sync"
+	"test-edot/util"
+	"time"
+)
+
+type (
+	// Message is sent to the email or phone of a user, Channel is constants.VERIFICATION_EMAIL or VERIFICATION_PHONE
+	Message struct {
+		Channel string    `json:"channel"`
+		To      string    `json:"to"`
+		Subject string    `json:"subject"`
+		Body    string    `json:"body"`
+		SentAt  time.Time `json:"sent_at"`
+	}
+
+	// Notifier delivers a message to the user, the real email or sms provider implements it in production
+	Notifier interface {
+		Send(ctx context.Context, message Message) error
+	}
+
+	// LogNotifier writes the message into the application log, used for local development
+	LogNotifier struct {
+		Log *zap.Logger
+	}
+
+	// FileNotifier appends every message as a json line, so tests can read the code that was sent
+	FileNotifier struct {
+		Path string
+		mu   sync.Mutex
+	}
+)
+
+// NewNotifier selects the notifier from NOTIFIER_DRIVER, "file" writes to NOTIFIER_FILE and anything else logs
+func NewNotifier(log *zap.Logger) Notifier {
+	switch util.GetEnv("NOTIFIER_DRIVER", "log") {
+	case "file":
+		return &FileNotifier{Path: util.GetEnv("NOTIFIER_FILE", "notifications.log")}
+	default:
+		return &LogNotifier{Log: log}
+	}
+}
+
+func (n *LogNotifier) Send(ctx context.Context, message Message) error {
+	n.Log.Info("notification sent",
+		zap.String("channel", message.Channel),
+		zap.String("to", message.To),
+		zap.String("subject", message.Subject),
+		zap.String("body", message.Body),
+	)
+
+	return nil
+}
+
+func (n *FileNotifier) Send(ctx context.Context, message Message) error {
+	if message.SentAt.IsZero() {
+		message.SentAt = time.Now().In(util.LocationTime)
+	}
+
+	line, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	file, err := os.OpenFile(n.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+
+	return nil
+}
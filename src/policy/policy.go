@@ -0,0 +1,73 @@
+package policy
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"sync"
+	"test-edot/constants"
+	"test-edot/src/repository"
+)
+
+// Policy maps every platform role to the permissions it is granted, the Authorize middleware checks it
+// per route and the services check it with Allowed when they grant access outside of a route
+type Policy struct {
+	mu          sync.RWMutex
+	permissions map[string]map[string]bool
+}
+
+var current = New(constants.MapRolePermission)
+
+func New(rolePermissions map[string][]string) *Policy {
+	p := &Policy{}
+	p.Set(rolePermissions)
+	return p
+}
+
+func (p *Policy) Set(rolePermissions map[string][]string) {
+	permissions := make(map[string]map[string]bool, len(rolePermissions))
+	for role, list := range rolePermissions {
+		permissions[role] = make(map[string]bool, len(list))
+		for _, permission := range list {
+			permissions[role][permission] = true
+		}
+	}
+
+	p.mu.Lock()
+	p.permissions = permissions
+	p.mu.Unlock()
+}
+
+func (p *Policy) Allowed(role, permission string) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.permissions[role][permission]
+}
+
+// Allowed checks the permission of a role against the current policy
+func Allowed(role, permission string) bool {
+	return current.Allowed(role, permission)
+}
+
+// Load replaces the default policy with the role_permissions table, the default is kept
+// when the table is empty or cannot be read
+func Load(ctx context.Context, log *zap.Logger, repo repository.RolePermissionRepositoryInterface) {
+	rows, err := repo.Find(ctx, "role,permission", "1 = 1")
+	if err != nil {
+		log.Error("error load role permission, using default policy", zap.Error(err))
+		return
+	}
+
+	if len(rows) == 0 {
+		log.Info("role permission is empty, using default policy")
+		return
+	}
+
+	rolePermissions := make(map[string][]string)
+	for _, row := range rows {
+		rolePermissions[row.Role] = append(rolePermissions[row.Role], row.Permission)
+	}
+
+	current.Set(rolePermissions)
+	log.Info("role permission loaded", zap.Int("roles", len(rolePermissions)), zap.Int("permissions", len(rows)))
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"strings"
+	"test-edot/src/models"
+)
+
+type ApiKeyRepositoryInterface interface {
+	Create(ctx context.Context, apiKey *models.ApiKey) error
+	FindOne(ctx context.Context, selectField, query string, args ...any) (models.ApiKey, error)
+	Find(ctx context.Context, selectField, query string, args ...any) ([]models.ApiKey, error)
+	Update(ctx context.Context, updatedField *models.ApiKey, selectFields, query string, args ...any) error
+}
+
+type ApiKeyRepository struct {
+	Database *gorm.DB
+	Tx       *gorm.DB
+}
+
+func NewApiKeyRepository(db *gorm.DB) *ApiKeyRepository {
+	return &ApiKeyRepository{
+		Database: db,
+	}
+}
+
+func (r *ApiKeyRepository) Create(ctx context.Context, apiKey *models.ApiKey) error {
+	if err := r.Database.WithContext(ctx).Model(models.ApiKey{}).Create(apiKey).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *ApiKeyRepository) FindOne(ctx context.Context, selectField, query string, args ...any) (models.ApiKey, error) {
+	var apiKey models.ApiKey
+	dbCon := r.Database.WithContext(ctx).Model(models.ApiKey{})
+
+	if selectField != "*" {
+		dbCon = dbCon.Select(selectField)
+	}
+
+	if err := dbCon.Where(query, args...).Take(&apiKey).Error; err != nil {
+		return models.ApiKey{}, err
+	}
+
+	return apiKey, nil
+}
+
+func (r *ApiKeyRepository) Find(ctx context.Context, selectField, query string, args ...any) ([]models.ApiKey, error) {
+	var apiKeys []models.ApiKey
+	dbCon := r.Database.WithContext(ctx).Model(models.ApiKey{})
+
+	if selectField != "*" {
+		dbCon = dbCon.Select(selectField)
+	}
+
+	if err := dbCon.Where(query, args...).Order("id desc").Find(&apiKeys).Error; err != nil {
+		return nil, err
+	}
+
+	return apiKeys, nil
+}
+
+func (r *ApiKeyRepository) Update(ctx context.Context, updatedField *models.ApiKey, selectFields, query string, args ...any) error {
+	dbConn := r.Database.WithContext(ctx).Model(models.ApiKey{})
+
+	if selectFields != "*" {
+		dbConn = dbConn.Select(strings.Split(selectFields, ","))
+	}
+
+	if err := dbConn.Where(query, args...).Updates(updatedField).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"test-edot/src/models"
+)
+
+type AuditEventRepositoryInterface interface {
+	Create(ctx context.Context, auditEvent *models.AuditEvent) error
+	Find(ctx context.Context, offset, limit int, selectField, query string, args ...any) ([]models.AuditEvent, error)
+}
+
+type AuditEventRepository struct {
+	Database *gorm.DB
+	Tx       *gorm.DB
+}
+
+func NewAuditEventRepository(db *gorm.DB) *AuditEventRepository {
+	return &AuditEventRepository{
+		Database: db,
+	}
+}
+
+func (r *AuditEventRepository) Create(ctx context.Context, auditEvent *models.AuditEvent) error {
+	if err := r.Database.WithContext(ctx).Model(models.AuditEvent{}).Create(auditEvent).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *AuditEventRepository) Find(ctx context.Context, offset, limit int, selectField, query string, args ...any) ([]models.AuditEvent, error) {
+	var auditEvents []models.AuditEvent
+	dbCon := r.Database.WithContext(ctx).Model(models.AuditEvent{})
+
+	if selectField != "*" {
+		dbCon = dbCon.Select(selectField)
+	}
+
+	if err := dbCon.Where(query, args...).Order("id desc").Offset(offset).Limit(limit).Find(&auditEvents).Error; err != nil {
+		return nil, err
+	}
+
+	return auditEvents, nil
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"strings"
+	"test-edot/src/models"
+)
+
+type LoginChallengeRepositoryInterface interface {
+	Begin() *gorm.DB
+	Create(ctx context.Context, challenge *models.LoginChallenge) error
+	FindOneTx(tx *gorm.DB, selectField, query string, args ...any) (models.LoginChallenge, error)
+	UpdateTx(tx *gorm.DB, updatedField *models.LoginChallenge, selectFields, query string, args ...any) error
+	Delete(ctx context.Context, query string, args ...any) error
+}
+
+type LoginChallengeRepository struct {
+	Database *gorm.DB
+	Tx       *gorm.DB
+}
+
+func NewLoginChallengeRepository(db *gorm.DB) *LoginChallengeRepository {
+	return &LoginChallengeRepository{
+		Database: db,
+	}
+}
+
+func (r *LoginChallengeRepository) Begin() *gorm.DB {
+	return r.Database.Begin()
+}
+
+func (r *LoginChallengeRepository) Create(ctx context.Context, challenge *models.LoginChallenge) error {
+	if err := r.Database.WithContext(ctx).Model(models.LoginChallenge{}).Create(challenge).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *LoginChallengeRepository) FindOneTx(tx *gorm.DB, selectField, query string, args ...any) (models.LoginChallenge, error) {
+	var challenge models.LoginChallenge
+	dbCon := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Model(models.LoginChallenge{})
+
+	if selectField != "*" {
+		dbCon = dbCon.Select(selectField)
+	}
+
+	if err := dbCon.Where(query, args...).Take(&challenge).Error; err != nil {
+		return models.LoginChallenge{}, err
+	}
+
+	return challenge, nil
+}
+
+func (r *LoginChallengeRepository) UpdateTx(tx *gorm.DB, updatedField *models.LoginChallenge, selectFields, query string, args ...any) error {
+	dbConn := tx.Model(models.LoginChallenge{})
+
+	if selectFields != "*" {
+		dbConn = dbConn.Select(strings.Split(selectFields, ","))
+	}
+
+	if err := dbConn.Where(query, args...).Updates(updatedField).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *LoginChallengeRepository) Delete(ctx context.Context, query string, args ...any) error {
+	if err := r.Database.WithContext(ctx).Where(query, args...).Delete(&models.LoginChallenge{}).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
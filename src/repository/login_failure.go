@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"strings"
+	"test-edot/src/models"
+)
+
+type LoginFailureRepositoryInterface interface {
+	Begin() *gorm.DB
+	CreateTx(tx *gorm.DB, loginFailure *models.LoginFailure) error
+	FindOne(ctx context.Context, selectField, query string, args ...any) (models.LoginFailure, error)
+	FindOneTx(tx *gorm.DB, selectField, query string, args ...any) (models.LoginFailure, error)
+	UpdateTx(tx *gorm.DB, updatedField *models.LoginFailure, selectFields, query string, args ...any) error
+	Delete(ctx context.Context, query string, args ...any) error
+}
+
+type LoginFailureRepository struct {
+	Database *gorm.DB
+	Tx       *gorm.DB
+}
+
+func NewLoginFailureRepository(db *gorm.DB) *LoginFailureRepository {
+	return &LoginFailureRepository{
+		Database: db,
+	}
+}
+
+func (r *LoginFailureRepository) Begin() *gorm.DB {
+	return r.Database.Begin()
+}
+
+// CreateTx keeps the existing row of the scope and identifier, concurrent failures all end on the same row
+func (r *LoginFailureRepository) CreateTx(tx *gorm.DB, loginFailure *models.LoginFailure) error {
+	if err := tx.Model(models.LoginFailure{}).Clauses(clause.OnConflict{DoNothing: true}).Create(loginFailure).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *LoginFailureRepository) FindOne(ctx context.Context, selectField, query string, args ...any) (models.LoginFailure, error) {
+	var loginFailure models.LoginFailure
+	dbCon := r.Database.WithContext(ctx).Model(models.LoginFailure{})
+
+	if selectField != "*" {
+		dbCon = dbCon.Select(selectField)
+	}
+
+	if err := dbCon.Where(query, args...).Take(&loginFailure).Error; err != nil {
+		return models.LoginFailure{}, err
+	}
+
+	return loginFailure, nil
+}
+
+func (r *LoginFailureRepository) FindOneTx(tx *gorm.DB, selectField, query string, args ...any) (models.LoginFailure, error) {
+	var loginFailure models.LoginFailure
+	dbCon := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Model(models.LoginFailure{})
+
+	if selectField != "*" {
+		dbCon = dbCon.Select(selectField)
+	}
+
+	if err := dbCon.Where(query, args...).Take(&loginFailure).Error; err != nil {
+		return models.LoginFailure{}, err
+	}
+
+	return loginFailure, nil
+}
+
+func (r *LoginFailureRepository) UpdateTx(tx *gorm.DB, updatedField *models.LoginFailure, selectFields, query string, args ...any) error {
+	dbConn := tx.Model(models.LoginFailure{})
+
+	if selectFields != "*" {
+		dbConn = dbConn.Select(strings.Split(selectFields, ","))
+	}
+
+	if err := dbConn.Where(query, args...).Updates(updatedField).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *LoginFailureRepository) Delete(ctx context.Context, query string, args ...any) error {
+	if err := r.Database.WithContext(ctx).Where(query, args...).Delete(&models.LoginFailure{}).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
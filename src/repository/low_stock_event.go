@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"strings"
+	"test-edot/src/models"
+)
+
+type LowStockEventRepositoryInterface interface {
+	Begin() *gorm.DB
+	Create(tx *gorm.DB, lowStockEvent *models.LowStockEvent) error
+	Find(ctx context.Context, selectField, query string, args ...any) ([]models.LowStockEvent, error)
+	FindTx(tx *gorm.DB, selectField, query string, args ...any) ([]models.LowStockEvent, error)
+	Update(ctx context.Context, updatedField models.LowStockEvent, selectFields, query string, args ...any) error
+	UpdateOneTx(tx *gorm.DB, updateLowStockEvent *models.LowStockEvent, selectFields, query string, args ...interface{}) error
+}
+
+type LowStockEventRepository struct {
+	Database *gorm.DB
+	Tx       *gorm.DB
+}
+
+func NewLowStockEventRepository(db *gorm.DB) *LowStockEventRepository {
+	return &LowStockEventRepository{
+		Database: db,
+	}
+}
+
+func (r *LowStockEventRepository) Begin() *gorm.DB {
+	return r.Database.Begin()
+}
+
+func (r *LowStockEventRepository) Create(tx *gorm.DB, lowStockEvent *models.LowStockEvent) error {
+	if err := tx.Model(models.LowStockEvent{}).Create(lowStockEvent).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *LowStockEventRepository) Find(ctx context.Context, selectField, query string, args ...any) ([]models.LowStockEvent, error) {
+	var lowStockEvents []models.LowStockEvent
+	dbCon := r.Database.WithContext(ctx).Model(models.LowStockEvent{})
+
+	if selectField != "*" {
+		dbCon = dbCon.Select(selectField)
+	}
+
+	if err := dbCon.Where(query, args...).Find(&lowStockEvents).Error; err != nil {
+		return []models.LowStockEvent{}, err
+	}
+
+	return lowStockEvents, nil
+}
+
+func (r *LowStockEventRepository) FindTx(tx *gorm.DB, selectField, query string, args ...any) ([]models.LowStockEvent, error) {
+	var lowStockEvents []models.LowStockEvent
+	db := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Model(models.LowStockEvent{})
+
+	if selectField != "*" {
+		db = db.Select(selectField)
+	}
+
+	if err := db.Where(query, args...).Find(&lowStockEvents).Error; err != nil {
+		return []models.LowStockEvent{}, err
+	}
+
+	return lowStockEvents, nil
+}
+
+func (r *LowStockEventRepository) Update(ctx context.Context, updatedField models.LowStockEvent, selectFields, query string, args ...any) error {
+	dbConn := r.Database.WithContext(ctx).Model(models.LowStockEvent{})
+
+	if selectFields != "*" {
+		dbConn = dbConn.Select(strings.Split(selectFields, ","))
+	}
+
+	if err := dbConn.Where(query, args...).Updates(&updatedField).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *LowStockEventRepository) UpdateOneTx(tx *gorm.DB, updateLowStockEvent *models.LowStockEvent, selectFields, query string, args ...interface{}) error {
+	dbConn := tx.Model(models.LowStockEvent{})
+
+	if selectFields != "*" {
+		dbConn = dbConn.Select(strings.Split(selectFields, ","))
+	}
+
+	if err := dbConn.Where(query, args...).Updates(updateLowStockEvent).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
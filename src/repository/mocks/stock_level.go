@@ -55,6 +55,72 @@ func (_m *StockLevelRepositoryInterface) Create(tx *gorm.DB, stockLevel *models.
 	return r0
 }
 
+// Find provides a mock function with given fields: ctx, selectField, query, args
+func (_m *StockLevelRepositoryInterface) Find(ctx context.Context, selectField string, query string, args ...any) ([]models.StockLevel, error) {
+	var _ca []interface{}
+	_ca = append(_ca, ctx, selectField, query)
+	_ca = append(_ca, args...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Find")
+	}
+
+	var r0 []models.StockLevel
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, ...any) ([]models.StockLevel, error)); ok {
+		return rf(ctx, selectField, query, args...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, ...any) []models.StockLevel); ok {
+		r0 = rf(ctx, selectField, query, args...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.StockLevel)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, ...any) error); ok {
+		r1 = rf(ctx, selectField, query, args...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindLow provides a mock function with given fields: ctx, query, args
+func (_m *StockLevelRepositoryInterface) FindLow(ctx context.Context, query string, args ...interface{}) ([]models.StockLevelLow, error) {
+	var _ca []interface{}
+	_ca = append(_ca, ctx, query)
+	_ca = append(_ca, args...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindLow")
+	}
+
+	var r0 []models.StockLevelLow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...interface{}) ([]models.StockLevelLow, error)); ok {
+		return rf(ctx, query, args...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...interface{}) []models.StockLevelLow); ok {
+		r0 = rf(ctx, query, args...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.StockLevelLow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ...interface{}) error); ok {
+		r1 = rf(ctx, query, args...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindOne provides a mock function with given fields: ctx, selectField, query, args
 func (_m *StockLevelRepositoryInterface) FindOne(ctx context.Context, selectField string, query string, args ...any) (models.StockLevel, error) {
 	var _ca []interface{}
@@ -117,6 +183,39 @@ func (_m *StockLevelRepositoryInterface) FindOneTx(tx *gorm.DB, order string, qu
 	return r0, r1
 }
 
+// FindProduct provides a mock function with given fields: ctx, query, args
+func (_m *StockLevelRepositoryInterface) FindProduct(ctx context.Context, query string, args ...interface{}) ([]models.StockLevelProduct, error) {
+	var _ca []interface{}
+	_ca = append(_ca, ctx, query)
+	_ca = append(_ca, args...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindProduct")
+	}
+
+	var r0 []models.StockLevelProduct
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...interface{}) ([]models.StockLevelProduct, error)); ok {
+		return rf(ctx, query, args...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...interface{}) []models.StockLevelProduct); ok {
+		r0 = rf(ctx, query, args...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.StockLevelProduct)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ...interface{}) error); ok {
+		r1 = rf(ctx, query, args...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindTx provides a mock function with given fields: tx, order, query, args
 func (_m *StockLevelRepositoryInterface) FindTx(tx *gorm.DB, order string, query string, args ...interface{}) ([]models.StockLevelProduct, error) {
 	var _ca []interface{}
@@ -150,6 +249,39 @@ func (_m *StockLevelRepositoryInterface) FindTx(tx *gorm.DB, order string, query
 	return r0, r1
 }
 
+// SumStockGroupWarehouse provides a mock function with given fields: ctx, query, args
+func (_m *StockLevelRepositoryInterface) SumStockGroupWarehouse(ctx context.Context, query string, args ...interface{}) ([]models.StockWarehouse, error) {
+	var _ca []interface{}
+	_ca = append(_ca, ctx, query)
+	_ca = append(_ca, args...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SumStockGroupWarehouse")
+	}
+
+	var r0 []models.StockWarehouse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...interface{}) ([]models.StockWarehouse, error)); ok {
+		return rf(ctx, query, args...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...interface{}) []models.StockWarehouse); ok {
+		r0 = rf(ctx, query, args...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.StockWarehouse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ...interface{}) error); ok {
+		r1 = rf(ctx, query, args...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SumStockWarehouse provides a mock function with given fields: ctx, query, args
 func (_m *StockLevelRepositoryInterface) SumStockWarehouse(ctx context.Context, query string, args ...any) (models.StockWarehouse, error) {
 	var _ca []interface{}
@@ -181,6 +313,37 @@ func (_m *StockLevelRepositoryInterface) SumStockWarehouse(ctx context.Context,
 	return r0, r1
 }
 
+// SumStockWarehouseTx provides a mock function with given fields: tx, query, args
+func (_m *StockLevelRepositoryInterface) SumStockWarehouseTx(tx *gorm.DB, query string, args ...interface{}) (models.StockWarehouse, error) {
+	var _ca []interface{}
+	_ca = append(_ca, tx, query)
+	_ca = append(_ca, args...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SumStockWarehouseTx")
+	}
+
+	var r0 models.StockWarehouse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*gorm.DB, string, ...interface{}) (models.StockWarehouse, error)); ok {
+		return rf(tx, query, args...)
+	}
+	if rf, ok := ret.Get(0).(func(*gorm.DB, string, ...interface{}) models.StockWarehouse); ok {
+		r0 = rf(tx, query, args...)
+	} else {
+		r0 = ret.Get(0).(models.StockWarehouse)
+	}
+
+	if rf, ok := ret.Get(1).(func(*gorm.DB, string, ...interface{}) error); ok {
+		r1 = rf(tx, query, args...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateOneTx provides a mock function with given fields: tx, updateStockLevel, selectFields, query, args
 func (_m *StockLevelRepositoryInterface) UpdateOneTx(tx *gorm.DB, updateStockLevel *models.StockLevel, selectFields string, query string, args ...interface{}) error {
 	var _ca []interface{}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"strings"
+	"test-edot/src/models"
+)
+
+type PurchaseOrderRepositoryInterface interface {
+	Begin() *gorm.DB
+	Create(tx *gorm.DB, purchaseOrder *models.PurchaseOrder) error
+	FindOneTx(tx *gorm.DB, fields, query string, args ...interface{}) (models.PurchaseOrder, error)
+	Find(ctx context.Context, selectField, query string, args ...any) ([]models.PurchaseOrder, error)
+	GetDetail(ctx context.Context, query string, args ...any) (models.PurchaseOrderDetail, error)
+	UpdateOneTx(tx *gorm.DB, updatePurchaseOrder *models.PurchaseOrder, selectFields, query string, args ...interface{}) error
+}
+
+type PurchaseOrderRepository struct {
+	Database *gorm.DB
+	Tx       *gorm.DB
+}
+
+func NewPurchaseOrderRepository(db *gorm.DB) *PurchaseOrderRepository {
+	return &PurchaseOrderRepository{
+		Database: db,
+	}
+}
+
+func (r *PurchaseOrderRepository) Begin() *gorm.DB {
+	return r.Database.Begin()
+}
+
+func (r *PurchaseOrderRepository) Create(tx *gorm.DB, purchaseOrder *models.PurchaseOrder) error {
+	if err := tx.Model(models.PurchaseOrder{}).Create(purchaseOrder).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *PurchaseOrderRepository) FindOneTx(tx *gorm.DB, fields, query string, args ...interface{}) (models.PurchaseOrder, error) {
+	var purchaseOrder models.PurchaseOrder
+	db := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Model(models.PurchaseOrder{})
+
+	if fields != "*" {
+		db = db.Select(fields)
+	}
+
+	if err := db.Where(query, args...).Take(&purchaseOrder).Error; err != nil {
+		return models.PurchaseOrder{}, err
+	}
+
+	return purchaseOrder, nil
+}
+
+func (r *PurchaseOrderRepository) Find(ctx context.Context, selectField, query string, args ...any) ([]models.PurchaseOrder, error) {
+	var purchaseOrders []models.PurchaseOrder
+	dbCon := r.Database.WithContext(ctx).Model(models.PurchaseOrder{})
+
+	if selectField != "*" {
+		dbCon = dbCon.Select(selectField)
+	}
+
+	if err := dbCon.Where(query, args...).Order("id desc").Find(&purchaseOrders).Error; err != nil {
+		return []models.PurchaseOrder{}, err
+	}
+
+	return purchaseOrders, nil
+}
+
+func (r *PurchaseOrderRepository) GetDetail(ctx context.Context, query string, args ...any) (models.PurchaseOrderDetail, error) {
+	var purchaseOrder models.PurchaseOrderDetail
+	err := r.Database.WithContext(ctx).Model(models.PurchaseOrderDetail{}).
+		Preload("Supplier", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id,name,email,phone")
+		}).
+		Preload("Warehouse", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id,name,location")
+		}).
+		Preload("Items").
+		Where(query, args...).Take(&purchaseOrder).Error
+	if err != nil {
+		return models.PurchaseOrderDetail{}, err
+	}
+
+	return purchaseOrder, nil
+}
+
+func (r *PurchaseOrderRepository) UpdateOneTx(tx *gorm.DB, updatePurchaseOrder *models.PurchaseOrder, selectFields, query string, args ...interface{}) error {
+	dbConn := tx.Model(models.PurchaseOrder{})
+
+	if selectFields != "*" {
+		dbConn = dbConn.Select(strings.Split(selectFields, ","))
+	}
+
+	if err := dbConn.Where(query, args...).Updates(updatePurchaseOrder).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
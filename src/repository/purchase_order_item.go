@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"strings"
+	"test-edot/src/models"
+)
+
+type PurchaseOrderItemRepositoryInterface interface {
+	Create(tx *gorm.DB, purchaseOrderItem *models.PurchaseOrderItem) error
+	FindTx(tx *gorm.DB, selectField, query string, args ...any) ([]models.PurchaseOrderItem, error)
+	UpdateOneTx(tx *gorm.DB, updatePurchaseOrderItem *models.PurchaseOrderItem, selectFields, query string, args ...interface{}) error
+	CreateReceipt(tx *gorm.DB, receipt *models.PurchaseOrderReceipt) error
+}
+
+type PurchaseOrderItemRepository struct {
+	Database *gorm.DB
+	Tx       *gorm.DB
+}
+
+func NewPurchaseOrderItemRepository(db *gorm.DB) *PurchaseOrderItemRepository {
+	return &PurchaseOrderItemRepository{
+		Database: db,
+	}
+}
+
+func (r *PurchaseOrderItemRepository) Create(tx *gorm.DB, purchaseOrderItem *models.PurchaseOrderItem) error {
+	if err := tx.Model(models.PurchaseOrderItem{}).Create(purchaseOrderItem).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *PurchaseOrderItemRepository) FindTx(tx *gorm.DB, selectField, query string, args ...any) ([]models.PurchaseOrderItem, error) {
+	var purchaseOrderItems []models.PurchaseOrderItem
+	db := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Model(models.PurchaseOrderItem{})
+
+	if selectField != "*" {
+		db = db.Select(selectField)
+	}
+
+	if err := db.Where(query, args...).Find(&purchaseOrderItems).Error; err != nil {
+		return []models.PurchaseOrderItem{}, err
+	}
+
+	return purchaseOrderItems, nil
+}
+
+func (r *PurchaseOrderItemRepository) UpdateOneTx(tx *gorm.DB, updatePurchaseOrderItem *models.PurchaseOrderItem, selectFields, query string, args ...interface{}) error {
+	dbConn := tx.Model(models.PurchaseOrderItem{})
+
+	if selectFields != "*" {
+		dbConn = dbConn.Select(strings.Split(selectFields, ","))
+	}
+
+	if err := dbConn.Where(query, args...).Updates(updatePurchaseOrderItem).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *PurchaseOrderItemRepository) CreateReceipt(tx *gorm.DB, receipt *models.PurchaseOrderReceipt) error {
+	if err := tx.Model(models.PurchaseOrderReceipt{}).Create(receipt).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"strings"
+	"test-edot/src/models"
+)
+
+type RefreshTokenRepositoryInterface interface {
+	Begin() *gorm.DB
+	Create(tx *gorm.DB, refreshToken *models.RefreshToken) error
+	FindOneTx(tx *gorm.DB, selectField, query string, args ...any) (models.RefreshToken, error)
+	FindTx(tx *gorm.DB, selectField, query string, args ...any) ([]models.RefreshToken, error)
+	UpdateTx(tx *gorm.DB, updatedField *models.RefreshToken, selectFields, query string, args ...any) error
+	Delete(ctx context.Context, query string, args ...any) error
+}
+
+type RefreshTokenRepository struct {
+	Database *gorm.DB
+	Tx       *gorm.DB
+}
+
+func NewRefreshTokenRepository(db *gorm.DB) *RefreshTokenRepository {
+	return &RefreshTokenRepository{
+		Database: db,
+	}
+}
+
+func (r *RefreshTokenRepository) Begin() *gorm.DB {
+	return r.Database.Begin()
+}
+
+func (r *RefreshTokenRepository) Create(tx *gorm.DB, refreshToken *models.RefreshToken) error {
+	if err := tx.Model(models.RefreshToken{}).Create(refreshToken).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *RefreshTokenRepository) FindOneTx(tx *gorm.DB, selectField, query string, args ...any) (models.RefreshToken, error) {
+	var refreshToken models.RefreshToken
+	dbCon := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Model(models.RefreshToken{})
+
+	if selectField != "*" {
+		dbCon = dbCon.Select(selectField)
+	}
+
+	if err := dbCon.Where(query, args...).Take(&refreshToken).Error; err != nil {
+		return models.RefreshToken{}, err
+	}
+
+	return refreshToken, nil
+}
+
+func (r *RefreshTokenRepository) FindTx(tx *gorm.DB, selectField, query string, args ...any) ([]models.RefreshToken, error) {
+	var refreshTokens []models.RefreshToken
+	dbCon := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Model(models.RefreshToken{})
+
+	if selectField != "*" {
+		dbCon = dbCon.Select(selectField)
+	}
+
+	if err := dbCon.Where(query, args...).Find(&refreshTokens).Error; err != nil {
+		return []models.RefreshToken{}, err
+	}
+
+	return refreshTokens, nil
+}
+
+func (r *RefreshTokenRepository) UpdateTx(tx *gorm.DB, updatedField *models.RefreshToken, selectFields, query string, args ...any) error {
+	dbConn := tx.Model(models.RefreshToken{})
+
+	if selectFields != "*" {
+		dbConn = dbConn.Select(strings.Split(selectFields, ","))
+	}
+
+	if err := dbConn.Where(query, args...).Updates(updatedField).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *RefreshTokenRepository) Delete(ctx context.Context, query string, args ...any) error {
+	if err := r.Database.WithContext(ctx).Where(query, args...).Delete(&models.RefreshToken{}).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
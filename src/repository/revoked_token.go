@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"test-edot/src/models"
+)
+
+type RevokedTokenRepositoryInterface interface {
+	Create(ctx context.Context, revokedToken *models.RevokedToken) error
+	CreateTx(tx *gorm.DB, revokedToken *models.RevokedToken) error
+	FindOne(ctx context.Context, selectField, query string, args ...any) (models.RevokedToken, error)
+	Delete(ctx context.Context, query string, args ...any) error
+}
+
+type RevokedTokenRepository struct {
+	Database *gorm.DB
+	Tx       *gorm.DB
+}
+
+func NewRevokedTokenRepository(db *gorm.DB) *RevokedTokenRepository {
+	return &RevokedTokenRepository{
+		Database: db,
+	}
+}
+
+func (r *RevokedTokenRepository) Create(ctx context.Context, revokedToken *models.RevokedToken) error {
+	return r.CreateTx(r.Database.WithContext(ctx), revokedToken)
+}
+
+// CreateTx ignores a jti that is already revoked
+func (r *RevokedTokenRepository) CreateTx(tx *gorm.DB, revokedToken *models.RevokedToken) error {
+	if err := tx.Model(models.RevokedToken{}).Clauses(clause.OnConflict{DoNothing: true}).Create(revokedToken).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *RevokedTokenRepository) FindOne(ctx context.Context, selectField, query string, args ...any) (models.RevokedToken, error) {
+	var revokedToken models.RevokedToken
+	dbCon := r.Database.WithContext(ctx).Model(models.RevokedToken{})
+
+	if selectField != "*" {
+		dbCon = dbCon.Select(selectField)
+	}
+
+	if err := dbCon.Where(query, args...).Take(&revokedToken).Error; err != nil {
+		return models.RevokedToken{}, err
+	}
+
+	return revokedToken, nil
+}
+
+func (r *RevokedTokenRepository) Delete(ctx context.Context, query string, args ...any) error {
+	if err := r.Database.WithContext(ctx).Where(query, args...).Delete(&models.RevokedToken{}).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
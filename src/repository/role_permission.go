@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"test-edot/src/models"
+)
+
+type RolePermissionRepositoryInterface interface {
+	Find(ctx context.Context, selectField, query string, args ...any) ([]models.RolePermission, error)
+}
+
+type RolePermissionRepository struct {
+	Database *gorm.DB
+	Tx       *gorm.DB
+}
+
+func NewRolePermissionRepository(db *gorm.DB) *RolePermissionRepository {
+	return &RolePermissionRepository{
+		Database: db,
+	}
+}
+
+func (r *RolePermissionRepository) Find(ctx context.Context, selectField, query string, args ...any) ([]models.RolePermission, error) {
+	var rolePermissions []models.RolePermission
+	dbCon := r.Database.WithContext(ctx).Model(models.RolePermission{})
+
+	if selectField != "*" {
+		dbCon = dbCon.Select(selectField)
+	}
+
+	if err := dbCon.Where(query, args...).Find(&rolePermissions).Error; err != nil {
+		return []models.RolePermission{}, err
+	}
+
+	return rolePermissions, nil
+}
@@ -3,12 +3,17 @@ package repository
 import (
 	"context"
 	"gorm.io/gorm"
+	"strings"
 	"test-edot/src/models"
 )
 
 type ShopRepositoryInterface interface {
+	Begin() *gorm.DB
 	Create(ctx context.Context, Shop *models.Shop) error
+	CreateTx(tx *gorm.DB, Shop *models.Shop) error
 	FindOne(ctx context.Context, selectField, query string, args ...any) (models.Shop, error)
+	Find(ctx context.Context, selectField, query string, args ...any) ([]models.Shop, error)
+	Update(ctx context.Context, updatedField models.Shop, selectFields, query string, args ...any) error
 }
 
 type ShopRepository struct {
@@ -22,8 +27,16 @@ func NewShopRepository(db *gorm.DB) *ShopRepository {
 	}
 }
 
+func (r *ShopRepository) Begin() *gorm.DB {
+	return r.Database.Begin()
+}
+
 func (r *ShopRepository) Create(ctx context.Context, Shop *models.Shop) error {
-	if err := r.Database.WithContext(ctx).Model(models.Shop{}).Create(Shop).Error; err != nil {
+	return r.CreateTx(r.Database.WithContext(ctx), Shop)
+}
+
+func (r *ShopRepository) CreateTx(tx *gorm.DB, Shop *models.Shop) error {
+	if err := tx.Model(models.Shop{}).Create(Shop).Error; err != nil {
 		return err
 	}
 
@@ -44,3 +57,32 @@ func (r *ShopRepository) FindOne(ctx context.Context, selectField, query string,
 
 	return Shop, nil
 }
+
+func (r *ShopRepository) Find(ctx context.Context, selectField, query string, args ...any) ([]models.Shop, error) {
+	var shops []models.Shop
+	dbCon := r.Database.WithContext(ctx).Model(models.Shop{})
+
+	if selectField != "*" {
+		dbCon = dbCon.Select(selectField)
+	}
+
+	if err := dbCon.Where(query, args...).Find(&shops).Error; err != nil {
+		return []models.Shop{}, err
+	}
+
+	return shops, nil
+}
+
+func (r *ShopRepository) Update(ctx context.Context, updatedField models.Shop, selectFields, query string, args ...any) error {
+	dbConn := r.Database.WithContext(ctx).Model(models.Shop{})
+
+	if selectFields != "*" {
+		dbConn = dbConn.Select(strings.Split(selectFields, ","))
+	}
+
+	if err := dbConn.Where(query, args...).Updates(&updatedField).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"strings"
+	"test-edot/src/models"
+)
+
+type ShopMemberRepositoryInterface interface {
+	Create(ctx context.Context, shopMember *models.ShopMember) error
+	CreateTx(tx *gorm.DB, shopMember *models.ShopMember) error
+	FindOne(ctx context.Context, selectField, query string, args ...any) (models.ShopMember, error)
+	Find(ctx context.Context, selectField, query string, args ...any) ([]models.ShopMember, error)
+	FindUser(ctx context.Context, query string, args ...any) ([]models.ShopMemberUser, error)
+	Update(ctx context.Context, updatedField models.ShopMember, selectFields, query string, args ...any) error
+	Delete(ctx context.Context, query string, args ...any) error
+}
+
+type ShopMemberRepository struct {
+	Database *gorm.DB
+	Tx       *gorm.DB
+}
+
+func NewShopMemberRepository(db *gorm.DB) *ShopMemberRepository {
+	return &ShopMemberRepository{
+		Database: db,
+	}
+}
+
+func (r *ShopMemberRepository) Create(ctx context.Context, shopMember *models.ShopMember) error {
+	return r.CreateTx(r.Database.WithContext(ctx), shopMember)
+}
+
+func (r *ShopMemberRepository) CreateTx(tx *gorm.DB, shopMember *models.ShopMember) error {
+	if err := tx.Model(models.ShopMember{}).Create(shopMember).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *ShopMemberRepository) FindOne(ctx context.Context, selectField, query string, args ...any) (models.ShopMember, error) {
+	var shopMember models.ShopMember
+	dbCon := r.Database.WithContext(ctx).Model(models.ShopMember{})
+
+	if selectField != "*" {
+		dbCon = dbCon.Select(selectField)
+	}
+
+	if err := dbCon.Where(query, args...).Take(&shopMember).Error; err != nil {
+		return models.ShopMember{}, err
+	}
+
+	return shopMember, nil
+}
+
+func (r *ShopMemberRepository) Find(ctx context.Context, selectField, query string, args ...any) ([]models.ShopMember, error) {
+	var shopMembers []models.ShopMember
+	dbCon := r.Database.WithContext(ctx).Model(models.ShopMember{})
+
+	if selectField != "*" {
+		dbCon = dbCon.Select(selectField)
+	}
+
+	if err := dbCon.Where(query, args...).Find(&shopMembers).Error; err != nil {
+		return []models.ShopMember{}, err
+	}
+
+	return shopMembers, nil
+}
+
+func (r *ShopMemberRepository) FindUser(ctx context.Context, query string, args ...any) ([]models.ShopMemberUser, error) {
+	var shopMembers []models.ShopMemberUser
+
+	if err := r.Database.WithContext(ctx).Model(models.ShopMember{}).
+		Select("shop_members.*, users.full_name, users.email").
+		Joins("join users on users.id = shop_members.user_id").
+		Where(query, args...).Order("shop_members.id asc").Find(&shopMembers).Error; err != nil {
+		return []models.ShopMemberUser{}, err
+	}
+
+	return shopMembers, nil
+}
+
+func (r *ShopMemberRepository) Update(ctx context.Context, updatedField models.ShopMember, selectFields, query string, args ...any) error {
+	dbConn := r.Database.WithContext(ctx).Model(models.ShopMember{})
+
+	if selectFields != "*" {
+		dbConn = dbConn.Select(strings.Split(selectFields, ","))
+	}
+
+	if err := dbConn.Where(query, args...).Updates(&updatedField).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *ShopMemberRepository) Delete(ctx context.Context, query string, args ...any) error {
+	if err := r.Database.WithContext(ctx).Where(query, args...).Delete(&models.ShopMember{}).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -12,10 +12,15 @@ type StockLevelRepositoryInterface interface {
 	Begin() *gorm.DB
 	Create(tx *gorm.DB, stockLevel *models.StockLevel) error
 	FindOne(ctx context.Context, selectField, query string, args ...any) (models.StockLevel, error)
+	Find(ctx context.Context, selectField, query string, args ...any) ([]models.StockLevel, error)
 	FindOneTx(tx *gorm.DB, order, query string, args ...interface{}) (models.StockLevelProduct, error)
 	FindTx(tx *gorm.DB, order, query string, args ...interface{}) ([]models.StockLevelProduct, error)
 	UpdateOneTx(tx *gorm.DB, updateStockLevel *models.StockLevel, selectFields, query string, args ...interface{}) error
 	SumStockWarehouse(ctx context.Context, query string, args ...any) (models.StockWarehouse, error)
+	SumStockWarehouseTx(tx *gorm.DB, query string, args ...any) (models.StockWarehouse, error)
+	SumStockGroupWarehouse(ctx context.Context, query string, args ...any) ([]models.StockWarehouse, error)
+	FindLow(ctx context.Context, query string, args ...any) ([]models.StockLevelLow, error)
+	FindProduct(ctx context.Context, query string, args ...any) ([]models.StockLevelProduct, error)
 }
 
 type StockLevelRepository struct {
@@ -56,6 +61,21 @@ func (r *StockLevelRepository) FindOne(ctx context.Context, selectField, query s
 	return stockLevel, nil
 }
 
+func (r *StockLevelRepository) Find(ctx context.Context, selectField, query string, args ...any) ([]models.StockLevel, error) {
+	var stockLevels []models.StockLevel
+	dbCon := r.Database.WithContext(ctx).Model(models.StockLevel{})
+
+	if selectField != "*" {
+		dbCon = dbCon.Select(selectField)
+	}
+
+	if err := dbCon.Where(query, args...).Find(&stockLevels).Error; err != nil {
+		return []models.StockLevel{}, err
+	}
+
+	return stockLevels, nil
+}
+
 func (r *StockLevelRepository) FindOneTx(tx *gorm.DB, order, query string, args ...interface{}) (models.StockLevelProduct, error) {
 	var transaction models.StockLevelProduct
 	db := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Model(models.StockLevelProduct{})
@@ -103,13 +123,66 @@ func (r *StockLevelRepository) UpdateOneTx(tx *gorm.DB, updateStockLevel *models
 }
 
 func (r *StockLevelRepository) SumStockWarehouse(ctx context.Context, query string, args ...any) (models.StockWarehouse, error) {
+	return r.sumStockWarehouse(r.Database.WithContext(ctx), query, args...)
+}
+
+func (r *StockLevelRepository) SumStockWarehouseTx(tx *gorm.DB, query string, args ...any) (models.StockWarehouse, error) {
+	return r.sumStockWarehouse(tx, query, args...)
+}
+
+func (r *StockLevelRepository) sumStockWarehouse(db *gorm.DB, query string, args ...any) (models.StockWarehouse, error) {
 	var res models.StockWarehouse
 
-	if err := r.Database.WithContext(ctx).Model(models.StockLevel{}).
-		Select("sum(stock) as stock_count, sum(reserved_stock) as reserved_stock_count").Where(query, args...).
+	if err := db.Model(models.StockLevel{}).
+		Select("coalesce(sum(stock), 0) as stock_count, coalesce(sum(reserved_stock), 0) as reserved_stock_count").Where(query, args...).
 		Take(&res).Error; err != nil {
 		return models.StockWarehouse{}, err
 	}
 
 	return res, nil
 }
+
+// SumStockGroupWarehouse sums stock and reserved stock grouped by warehouse id
+func (r *StockLevelRepository) SumStockGroupWarehouse(ctx context.Context, query string, args ...any) ([]models.StockWarehouse, error) {
+	var res []models.StockWarehouse
+
+	if err := r.Database.WithContext(ctx).Model(models.StockLevel{}).
+		Select("warehouse_id, coalesce(sum(stock), 0) as stock_count, coalesce(sum(reserved_stock), 0) as reserved_stock_count").
+		Where(query, args...).Group("warehouse_id").Find(&res).Error; err != nil {
+		return []models.StockWarehouse{}, err
+	}
+
+	return res, nil
+}
+
+func (r *StockLevelRepository) FindLow(ctx context.Context, query string, args ...any) ([]models.StockLevelLow, error) {
+	var stocks []models.StockLevelLow
+
+	if err := r.Database.WithContext(ctx).Model(models.StockLevelLow{}).
+		Preload("Product", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id,name,sku")
+		}).
+		Preload("Warehouse", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id,name")
+		}).
+		Where("reorder_threshold > 0 and stock < reorder_threshold").
+		Where(query, args...).Order("warehouse_id asc, product_id asc").Find(&stocks).Error; err != nil {
+		return []models.StockLevelLow{}, err
+	}
+
+	return stocks, nil
+}
+
+func (r *StockLevelRepository) FindProduct(ctx context.Context, query string, args ...any) ([]models.StockLevelProduct, error) {
+	var stocks []models.StockLevelProduct
+
+	if err := r.Database.WithContext(ctx).Model(models.StockLevelProduct{}).
+		Preload("Product", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id,name,sku,price")
+		}).
+		Where(query, args...).Order("product_id asc").Find(&stocks).Error; err != nil {
+		return []models.StockLevelProduct{}, err
+	}
+
+	return stocks, nil
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"strings"
+	"test-edot/src/models"
+)
+
+type StockReservationRepositoryInterface interface {
+	Create(tx *gorm.DB, stockReservation *models.StockReservation) error
+	FindTx(tx *gorm.DB, selectField, query string, args ...any) ([]models.StockReservation, error)
+	UpdateOneTx(tx *gorm.DB, updateStockReservation *models.StockReservation, selectFields, query string, args ...interface{}) error
+	SumQty(ctx context.Context, query string, args ...any) ([]models.StockReserved, error)
+	SumQtyTx(tx *gorm.DB, query string, args ...any) ([]models.StockReserved, error)
+}
+
+type StockReservationRepository struct {
+	Database *gorm.DB
+	Tx       *gorm.DB
+}
+
+func NewStockReservationRepository(db *gorm.DB) *StockReservationRepository {
+	return &StockReservationRepository{
+		Database: db,
+	}
+}
+
+func (r *StockReservationRepository) Create(tx *gorm.DB, stockReservation *models.StockReservation) error {
+	if err := tx.Model(models.StockReservation{}).Create(stockReservation).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *StockReservationRepository) FindTx(tx *gorm.DB, selectField, query string, args ...any) ([]models.StockReservation, error) {
+	var stockReservations []models.StockReservation
+	db := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Model(models.StockReservation{})
+
+	if selectField != "*" {
+		db = db.Select(selectField)
+	}
+
+	if err := db.Where(query, args...).Order("id asc").Find(&stockReservations).Error; err != nil {
+		return []models.StockReservation{}, err
+	}
+
+	return stockReservations, nil
+}
+
+func (r *StockReservationRepository) UpdateOneTx(tx *gorm.DB, updateStockReservation *models.StockReservation, selectFields, query string, args ...interface{}) error {
+	dbConn := tx.Model(models.StockReservation{})
+
+	if selectFields != "*" {
+		dbConn = dbConn.Select(strings.Split(selectFields, ","))
+	}
+
+	if err := dbConn.Where(query, args...).Updates(updateStockReservation).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SumQty sums qty of the reservations matching the query grouped by stock id
+func (r *StockReservationRepository) SumQty(ctx context.Context, query string, args ...any) ([]models.StockReserved, error) {
+	return r.sumQty(r.Database.WithContext(ctx), query, args...)
+}
+
+func (r *StockReservationRepository) SumQtyTx(tx *gorm.DB, query string, args ...any) ([]models.StockReserved, error) {
+	return r.sumQty(tx, query, args...)
+}
+
+func (r *StockReservationRepository) sumQty(db *gorm.DB, query string, args ...any) ([]models.StockReserved, error) {
+	var res []models.StockReserved
+
+	db = db.Model(models.StockReservation{}).
+		Select("stock_id, sum(qty) as reserved_qty").
+		Where(query, args...)
+
+	if err := db.Group("stock_id").Find(&res).Error; err != nil {
+		return []models.StockReserved{}, err
+	}
+
+	return res, nil
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"strings"
+	"test-edot/src/models"
+)
+
+type StockTransferRepositoryInterface interface {
+	Begin() *gorm.DB
+	Create(tx *gorm.DB, stockTransfer *models.StockTransfer) error
+	FindOneTx(tx *gorm.DB, fields, query string, args ...interface{}) (models.StockTransfer, error)
+	Find(ctx context.Context, selectField, query string, args ...any) ([]models.StockTransfer, error)
+	FindTx(tx *gorm.DB, selectField, query string, args ...any) ([]models.StockTransfer, error)
+	GetDetail(ctx context.Context, query string, args ...any) (models.StockTransferDetail, error)
+	UpdateOneTx(tx *gorm.DB, updateStockTransfer *models.StockTransfer, selectFields, query string, args ...interface{}) error
+}
+
+type StockTransferRepository struct {
+	Database *gorm.DB
+	Tx       *gorm.DB
+}
+
+func NewStockTransferRepository(db *gorm.DB) *StockTransferRepository {
+	return &StockTransferRepository{
+		Database: db,
+	}
+}
+
+func (r *StockTransferRepository) Begin() *gorm.DB {
+	return r.Database.Begin()
+}
+
+func (r *StockTransferRepository) Create(tx *gorm.DB, stockTransfer *models.StockTransfer) error {
+	if err := tx.Model(models.StockTransfer{}).Create(stockTransfer).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *StockTransferRepository) FindOneTx(tx *gorm.DB, fields, query string, args ...interface{}) (models.StockTransfer, error) {
+	var stockTransfer models.StockTransfer
+	db := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Model(models.StockTransfer{})
+
+	if fields != "*" {
+		db = db.Select(fields)
+	}
+
+	if err := db.Where(query, args...).Take(&stockTransfer).Error; err != nil {
+		return models.StockTransfer{}, err
+	}
+
+	return stockTransfer, nil
+}
+
+func (r *StockTransferRepository) Find(ctx context.Context, selectField, query string, args ...any) ([]models.StockTransfer, error) {
+	var stockTransfers []models.StockTransfer
+	dbCon := r.Database.WithContext(ctx).Model(models.StockTransfer{})
+
+	if selectField != "*" {
+		dbCon = dbCon.Select(selectField)
+	}
+
+	if err := dbCon.Where(query, args...).Order("id desc").Find(&stockTransfers).Error; err != nil {
+		return []models.StockTransfer{}, err
+	}
+
+	return stockTransfers, nil
+}
+
+func (r *StockTransferRepository) FindTx(tx *gorm.DB, selectField, query string, args ...any) ([]models.StockTransfer, error) {
+	var stockTransfers []models.StockTransfer
+	dbCon := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Model(models.StockTransfer{})
+
+	if selectField != "*" {
+		dbCon = dbCon.Select(selectField)
+	}
+
+	if err := dbCon.Where(query, args...).Order("id desc").Find(&stockTransfers).Error; err != nil {
+		return []models.StockTransfer{}, err
+	}
+
+	return stockTransfers, nil
+}
+
+func (r *StockTransferRepository) GetDetail(ctx context.Context, query string, args ...any) (models.StockTransferDetail, error) {
+	var stockTransfer models.StockTransferDetail
+	err := r.Database.WithContext(ctx).Model(models.StockTransferDetail{}).
+		Preload("FromWarehouse", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id,name,location")
+		}).
+		Preload("ToWarehouse", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id,name,location")
+		}).
+		Preload("Items").
+		Where(query, args...).Take(&stockTransfer).Error
+	if err != nil {
+		return models.StockTransferDetail{}, err
+	}
+
+	return stockTransfer, nil
+}
+
+func (r *StockTransferRepository) UpdateOneTx(tx *gorm.DB, updateStockTransfer *models.StockTransfer, selectFields, query string, args ...interface{}) error {
+	dbConn := tx.Model(models.StockTransfer{})
+
+	if selectFields != "*" {
+		dbConn = dbConn.Select(strings.Split(selectFields, ","))
+	}
+
+	if err := dbConn.Where(query, args...).Updates(updateStockTransfer).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"strings"
+	"test-edot/src/models"
+)
+
+type StockTransferItemRepositoryInterface interface {
+	Create(tx *gorm.DB, stockTransferItem *models.StockTransferItem) error
+	FindTx(tx *gorm.DB, selectField, query string, args ...any) ([]models.StockTransferItem, error)
+	UpdateOneTx(tx *gorm.DB, updateStockTransferItem *models.StockTransferItem, selectFields, query string, args ...interface{}) error
+	SumIncoming(ctx context.Context, query string, args ...any) ([]models.StockIncoming, error)
+	SumIncomingTx(tx *gorm.DB, query string, args ...any) ([]models.StockIncoming, error)
+}
+
+type StockTransferItemRepository struct {
+	Database *gorm.DB
+	Tx       *gorm.DB
+}
+
+func NewStockTransferItemRepository(db *gorm.DB) *StockTransferItemRepository {
+	return &StockTransferItemRepository{
+		Database: db,
+	}
+}
+
+func (r *StockTransferItemRepository) Create(tx *gorm.DB, stockTransferItem *models.StockTransferItem) error {
+	if err := tx.Model(models.StockTransferItem{}).Create(stockTransferItem).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *StockTransferItemRepository) FindTx(tx *gorm.DB, selectField, query string, args ...any) ([]models.StockTransferItem, error) {
+	var stockTransferItems []models.StockTransferItem
+	db := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Model(models.StockTransferItem{})
+
+	if selectField != "*" {
+		db = db.Select(selectField)
+	}
+
+	if err := db.Where(query, args...).Find(&stockTransferItems).Error; err != nil {
+		return []models.StockTransferItem{}, err
+	}
+
+	return stockTransferItems, nil
+}
+
+func (r *StockTransferItemRepository) UpdateOneTx(tx *gorm.DB, updateStockTransferItem *models.StockTransferItem, selectFields, query string, args ...interface{}) error {
+	dbConn := tx.Model(models.StockTransferItem{})
+
+	if selectFields != "*" {
+		dbConn = dbConn.Select(strings.Split(selectFields, ","))
+	}
+
+	if err := dbConn.Where(query, args...).Updates(updateStockTransferItem).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SumIncoming sums the qty not received yet of the transfers matching the query grouped by destination warehouse,
+// the reserved qty of an item counts until the item is fully received, the query may filter on the
+// stock_transfers columns
+func (r *StockTransferItemRepository) SumIncoming(ctx context.Context, query string, args ...any) ([]models.StockIncoming, error) {
+	return r.sumIncoming(r.Database.WithContext(ctx), query, args...)
+}
+
+func (r *StockTransferItemRepository) SumIncomingTx(tx *gorm.DB, query string, args ...any) ([]models.StockIncoming, error) {
+	return r.sumIncoming(tx, query, args...)
+}
+
+func (r *StockTransferItemRepository) sumIncoming(db *gorm.DB, query string, args ...any) ([]models.StockIncoming, error) {
+	var res []models.StockIncoming
+
+	if err := db.Model(models.StockTransferItem{}).
+		Select("stock_transfers.to_warehouse_id as warehouse_id, coalesce(sum(stock_transfer_items.qty - stock_transfer_items.received_qty + case when stock_transfer_items.received_qty < stock_transfer_items.qty then stock_transfer_items.reserved_qty else 0 end), 0) as incoming_qty").
+		Joins("join stock_transfers on stock_transfers.id = stock_transfer_items.stock_transfer_id").
+		Where(query, args...).Group("stock_transfers.to_warehouse_id").Find(&res).Error; err != nil {
+		return []models.StockIncoming{}, err
+	}
+
+	return res, nil
+}
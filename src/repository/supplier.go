@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"strings"
+	"test-edot/src/models"
+)
+
+type SupplierRepositoryInterface interface {
+	Create(ctx context.Context, supplier *models.Supplier) error
+	FindOne(ctx context.Context, selectField, query string, args ...any) (models.Supplier, error)
+	Find(ctx context.Context, selectField, query string, args ...any) ([]models.Supplier, error)
+	Update(ctx context.Context, updatedField models.Supplier, selectFields, query string, args ...any) error
+}
+
+type SupplierRepository struct {
+	Database *gorm.DB
+	Tx       *gorm.DB
+}
+
+func NewSupplierRepository(db *gorm.DB) *SupplierRepository {
+	return &SupplierRepository{
+		Database: db,
+	}
+}
+
+func (r *SupplierRepository) Create(ctx context.Context, supplier *models.Supplier) error {
+	if err := r.Database.WithContext(ctx).Model(models.Supplier{}).Create(supplier).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *SupplierRepository) FindOne(ctx context.Context, selectField, query string, args ...any) (models.Supplier, error) {
+	var supplier models.Supplier
+	dbCon := r.Database.WithContext(ctx).Model(models.Supplier{})
+
+	if selectField != "*" {
+		dbCon = dbCon.Select(selectField)
+	}
+
+	if err := dbCon.Where(query, args...).Take(&supplier).Error; err != nil {
+		return models.Supplier{}, err
+	}
+
+	return supplier, nil
+}
+
+func (r *SupplierRepository) Find(ctx context.Context, selectField, query string, args ...any) ([]models.Supplier, error) {
+	var suppliers []models.Supplier
+	dbCon := r.Database.WithContext(ctx).Model(models.Supplier{})
+
+	if selectField != "*" {
+		dbCon = dbCon.Select(selectField)
+	}
+
+	if err := dbCon.Where(query, args...).Find(&suppliers).Error; err != nil {
+		return []models.Supplier{}, err
+	}
+
+	return suppliers, nil
+}
+
+func (r *SupplierRepository) Update(ctx context.Context, updatedField models.Supplier, selectFields, query string, args ...any) error {
+	dbConn := r.Database.WithContext(ctx).Model(models.Supplier{})
+
+	if selectFields != "*" {
+		dbConn = dbConn.Select(strings.Split(selectFields, ","))
+	}
+
+	if err := dbConn.Where(query, args...).Updates(&updatedField).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
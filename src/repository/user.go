@@ -3,12 +3,15 @@ package repository
 import (
 	"context"
 	"gorm.io/gorm"
+	"strings"
 	"test-edot/src/models"
 )
 
 type UserRepositoryInterface interface {
 	Create(ctx context.Context, user *models.User) error
 	FindOne(ctx context.Context, selectField, query string, args ...any) (models.User, error)
+	Update(ctx context.Context, updatedField *models.User, selectFields, query string, args ...any) error
+	UpdateTx(tx *gorm.DB, updatedField *models.User, selectFields, query string, args ...any) error
 }
 
 type UserRepository struct {
@@ -44,3 +47,31 @@ func (r UserRepository) FindOne(ctx context.Context, selectField, query string,
 
 	return user, nil
 }
+
+func (r UserRepository) Update(ctx context.Context, updatedField *models.User, selectFields, query string, args ...any) error {
+	dbConn := r.Database.WithContext(ctx).Model(models.User{})
+
+	if selectFields != "*" {
+		dbConn = dbConn.Select(strings.Split(selectFields, ","))
+	}
+
+	if err := dbConn.Where(query, args...).Updates(updatedField).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r UserRepository) UpdateTx(tx *gorm.DB, updatedField *models.User, selectFields, query string, args ...any) error {
+	dbConn := tx.Model(models.User{})
+
+	if selectFields != "*" {
+		dbConn = dbConn.Select(strings.Split(selectFields, ","))
+	}
+
+	if err := dbConn.Where(query, args...).Updates(updatedField).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
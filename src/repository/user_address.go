@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"strings"
+	"test-edot/src/models"
+)
+
+type UserAddressRepositoryInterface interface {
+	Begin() *gorm.DB
+	Create(tx *gorm.DB, address *models.UserAddress) error
+	FindOne(ctx context.Context, selectField, query string, args ...any) (models.UserAddress, error)
+	Find(ctx context.Context, selectField, query string, args ...any) ([]models.UserAddress, error)
+	FindTx(tx *gorm.DB, selectField, query string, args ...any) ([]models.UserAddress, error)
+	UpdateTx(tx *gorm.DB, updatedField *models.UserAddress, selectFields, query string, args ...any) error
+	DeleteTx(tx *gorm.DB, query string, args ...any) error
+}
+
+type UserAddressRepository struct {
+	Database *gorm.DB
+	Tx       *gorm.DB
+}
+
+func NewUserAddressRepository(db *gorm.DB) *UserAddressRepository {
+	return &UserAddressRepository{
+		Database: db,
+	}
+}
+
+func (r *UserAddressRepository) Begin() *gorm.DB {
+	return r.Database.Begin()
+}
+
+func (r *UserAddressRepository) Create(tx *gorm.DB, address *models.UserAddress) error {
+	if err := tx.Model(models.UserAddress{}).Create(address).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *UserAddressRepository) FindOne(ctx context.Context, selectField, query string, args ...any) (models.UserAddress, error) {
+	var address models.UserAddress
+	dbCon := r.Database.WithContext(ctx).Model(models.UserAddress{})
+
+	if selectField != "*" {
+		dbCon = dbCon.Select(selectField)
+	}
+
+	if err := dbCon.Where(query, args...).Take(&address).Error; err != nil {
+		return models.UserAddress{}, err
+	}
+
+	return address, nil
+}
+
+// Find the default address first, then the newest
+func (r *UserAddressRepository) Find(ctx context.Context, selectField, query string, args ...any) ([]models.UserAddress, error) {
+	var addresses []models.UserAddress
+	dbCon := r.Database.WithContext(ctx).Model(models.UserAddress{})
+
+	if selectField != "*" {
+		dbCon = dbCon.Select(selectField)
+	}
+
+	if err := dbCon.Where(query, args...).Order("is_default desc, id desc").Find(&addresses).Error; err != nil {
+		return []models.UserAddress{}, err
+	}
+
+	return addresses, nil
+}
+
+// FindTx locks the addresses of the query ordered by newest, so the default flag is changed by one request at a time
+func (r *UserAddressRepository) FindTx(tx *gorm.DB, selectField, query string, args ...any) ([]models.UserAddress, error) {
+	var addresses []models.UserAddress
+	dbCon := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Model(models.UserAddress{})
+
+	if selectField != "*" {
+		dbCon = dbCon.Select(selectField)
+	}
+
+	if err := dbCon.Where(query, args...).Order("id desc").Find(&addresses).Error; err != nil {
+		return []models.UserAddress{}, err
+	}
+
+	return addresses, nil
+}
+
+func (r *UserAddressRepository) UpdateTx(tx *gorm.DB, updatedField *models.UserAddress, selectFields, query string, args ...any) error {
+	dbConn := tx.Model(models.UserAddress{})
+
+	if selectFields != "*" {
+		dbConn = dbConn.Select(strings.Split(selectFields, ","))
+	}
+
+	if err := dbConn.Where(query, args...).Updates(updatedField).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *UserAddressRepository) DeleteTx(tx *gorm.DB, query string, args ...any) error {
+	if err := tx.Where(query, args...).Delete(&models.UserAddress{}).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"strings"
+	"test-edot/src/models"
+)
+
+type UserRecoveryCodeRepositoryInterface interface {
+	Begin() *gorm.DB
+	CreateTx(tx *gorm.DB, recoveryCodes []models.UserRecoveryCode) error
+	FindOneTx(tx *gorm.DB, selectField, query string, args ...any) (models.UserRecoveryCode, error)
+	UpdateTx(tx *gorm.DB, updatedField *models.UserRecoveryCode, selectFields, query string, args ...any) error
+	DeleteTx(tx *gorm.DB, query string, args ...any) error
+}
+
+type UserRecoveryCodeRepository struct {
+	Database *gorm.DB
+	Tx       *gorm.DB
+}
+
+func NewUserRecoveryCodeRepository(db *gorm.DB) *UserRecoveryCodeRepository {
+	return &UserRecoveryCodeRepository{
+		Database: db,
+	}
+}
+
+func (r *UserRecoveryCodeRepository) Begin() *gorm.DB {
+	return r.Database.Begin()
+}
+
+func (r *UserRecoveryCodeRepository) CreateTx(tx *gorm.DB, recoveryCodes []models.UserRecoveryCode) error {
+	if err := tx.Model(models.UserRecoveryCode{}).Create(&recoveryCodes).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *UserRecoveryCodeRepository) FindOneTx(tx *gorm.DB, selectField, query string, args ...any) (models.UserRecoveryCode, error) {
+	var recoveryCode models.UserRecoveryCode
+	dbCon := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Model(models.UserRecoveryCode{})
+
+	if selectField != "*" {
+		dbCon = dbCon.Select(selectField)
+	}
+
+	if err := dbCon.Where(query, args...).Take(&recoveryCode).Error; err != nil {
+		return models.UserRecoveryCode{}, err
+	}
+
+	return recoveryCode, nil
+}
+
+func (r *UserRecoveryCodeRepository) UpdateTx(tx *gorm.DB, updatedField *models.UserRecoveryCode, selectFields, query string, args ...any) error {
+	dbConn := tx.Model(models.UserRecoveryCode{})
+
+	if selectFields != "*" {
+		dbConn = dbConn.Select(strings.Split(selectFields, ","))
+	}
+
+	if err := dbConn.Where(query, args...).Updates(updatedField).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *UserRecoveryCodeRepository) DeleteTx(tx *gorm.DB, query string, args ...any) error {
+	if err := tx.Where(query, args...).Delete(&models.UserRecoveryCode{}).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
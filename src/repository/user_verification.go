@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"strings"
+	"test-edot/src/models"
+)
+
+type UserVerificationRepositoryInterface interface {
+	Begin() *gorm.DB
+	Create(tx *gorm.DB, verification *models.UserVerification) error
+	FindOneTx(tx *gorm.DB, selectField, query string, args ...any) (models.UserVerification, error)
+	UpdateTx(tx *gorm.DB, updatedField *models.UserVerification, selectFields, query string, args ...any) error
+	Delete(ctx context.Context, query string, args ...any) error
+}
+
+type UserVerificationRepository struct {
+	Database *gorm.DB
+	Tx       *gorm.DB
+}
+
+func NewUserVerificationRepository(db *gorm.DB) *UserVerificationRepository {
+	return &UserVerificationRepository{
+		Database: db,
+	}
+}
+
+func (r *UserVerificationRepository) Begin() *gorm.DB {
+	return r.Database.Begin()
+}
+
+func (r *UserVerificationRepository) Create(tx *gorm.DB, verification *models.UserVerification) error {
+	if err := tx.Model(models.UserVerification{}).Create(verification).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FindOneTx locks the latest verification matching the query
+func (r *UserVerificationRepository) FindOneTx(tx *gorm.DB, selectField, query string, args ...any) (models.UserVerification, error) {
+	var verification models.UserVerification
+	dbCon := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Model(models.UserVerification{})
+
+	if selectField != "*" {
+		dbCon = dbCon.Select(selectField)
+	}
+
+	if err := dbCon.Where(query, args...).Order("id desc").Take(&verification).Error; err != nil {
+		return models.UserVerification{}, err
+	}
+
+	return verification, nil
+}
+
+func (r *UserVerificationRepository) UpdateTx(tx *gorm.DB, updatedField *models.UserVerification, selectFields, query string, args ...any) error {
+	dbConn := tx.Model(models.UserVerification{})
+
+	if selectFields != "*" {
+		dbConn = dbConn.Select(strings.Split(selectFields, ","))
+	}
+
+	if err := dbConn.Where(query, args...).Updates(updatedField).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *UserVerificationRepository) Delete(ctx context.Context, query string, args ...any) error {
+	if err := r.Database.WithContext(ctx).Where(query, args...).Delete(&models.UserVerification{}).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"strings"
 	"test-edot/src/models"
 )
@@ -10,8 +11,11 @@ import (
 type WarehouseRepositoryInterface interface {
 	Create(ctx context.Context, Warehouse *models.Warehouse) error
 	FindOne(ctx context.Context, selectField, query string, args ...any) (models.Warehouse, error)
+	FindOneTx(tx *gorm.DB, selectField, query string, args ...any) (models.Warehouse, error)
+	FindTx(tx *gorm.DB, selectField, query string, args ...any) ([]models.Warehouse, error)
 	Find(ctx context.Context, selectField, query string, args ...any) ([]models.Warehouse, error)
 	Update(ctx context.Context, updatedField models.Warehouse, selectFields, query string, args ...any) error
+	DeleteTx(tx *gorm.DB, query string, args ...any) error
 }
 
 type WarehouseRepository struct {
@@ -48,6 +52,36 @@ func (r *WarehouseRepository) FindOne(ctx context.Context, selectField, query st
 	return Warehouse, nil
 }
 
+func (r *WarehouseRepository) FindOneTx(tx *gorm.DB, selectField, query string, args ...any) (models.Warehouse, error) {
+	var Warehouse models.Warehouse
+	dbCon := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Model(models.Warehouse{})
+
+	if selectField != "*" {
+		dbCon = dbCon.Select(selectField)
+	}
+
+	if err := dbCon.Where(query, args...).Take(&Warehouse).Error; err != nil {
+		return models.Warehouse{}, err
+	}
+
+	return Warehouse, nil
+}
+
+func (r *WarehouseRepository) FindTx(tx *gorm.DB, selectField, query string, args ...any) ([]models.Warehouse, error) {
+	var warehouses []models.Warehouse
+	dbCon := tx.Model(models.Warehouse{})
+
+	if selectField != "*" {
+		dbCon = dbCon.Select(selectField)
+	}
+
+	if err := dbCon.Where(query, args...).Find(&warehouses).Error; err != nil {
+		return []models.Warehouse{}, err
+	}
+
+	return warehouses, nil
+}
+
 func (r *WarehouseRepository) Find(ctx context.Context, selectField, query string, args ...any) ([]models.Warehouse, error) {
 	var warehouses []models.Warehouse
 	dbCon := r.Database.WithContext(ctx).Model(models.Warehouse{})
@@ -76,3 +110,11 @@ func (r *WarehouseRepository) Update(ctx context.Context, updatedField models.Wa
 
 	return nil
 }
+
+func (r *WarehouseRepository) DeleteTx(tx *gorm.DB, query string, args ...any) error {
+	if err := tx.Where(query, args...).Delete(&models.Warehouse{}).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -1,12 +1,19 @@
 package scheduler
 
 import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
 	"github.com/robfig/cron/v3"
 	"go.uber.org/zap"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"test-edot/metrics"
+	"test-edot/src/app/inventory"
 	"test-edot/src/app/order"
+	"test-edot/src/app/user"
 	"test-edot/src/factory"
 	"test-edot/util"
 	"time"
@@ -28,7 +35,26 @@ func RunScheduler(f *factory.Factory) {
 			return
 		}
 
+		_, err = c.AddFunc(util.GetEnv("STOCK_RECONCILE_CRON", "*/10 * * * *"), inventory.NewService(f).ReconcileStock)
+		if err != nil {
+			f.Log.Error("Error failed run stock reconciliation", zap.Error(err))
+			return
+		}
+
+		_, err = c.AddFunc(util.GetEnv("LOW_STOCK_CRON", "*/5 * * * *"), inventory.NewService(f).CheckLowStock)
+		if err != nil {
+			f.Log.Error("Error failed run low stock check", zap.Error(err))
+			return
+		}
+
+		_, err = c.AddFunc(util.GetEnv("TOKEN_CLEANUP_CRON", "0 * * * *"), user.NewService(f).CleanupTokens)
+		if err != nil {
+			f.Log.Error("Error failed run token cleanup", zap.Error(err))
+			return
+		}
+
 		c.Start()
+		metricsServer := serveMetrics(f)
 
 		// add any other syscalls that you want to be notified with
 		signal.Notify(s, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
@@ -36,6 +62,9 @@ func RunScheduler(f *factory.Factory) {
 
 		f.Log.Info("gracefully shutdown")
 		c.Stop()
+		if err := metricsServer.Shutdown(context.Background()); err != nil {
+			f.Log.Error("error shutdown metrics server", zap.Error(err))
+		}
 		time.Sleep(time.Second * 5)
 		f.Log.Info("shutdown done")
 
@@ -43,3 +72,19 @@ func RunScheduler(f *factory.Factory) {
 	}()
 	<-wait
 }
+
+// serveMetrics the jobs record their metrics in this process and not in the api, so the scheduler
+// serves them on its own METRICS_PORT
+func serveMetrics(f *factory.Factory) *http.Server {
+	g := gin.New()
+	g.GET("/test-edot-metrics", metrics.PrometheusHandler())
+
+	server := &http.Server{Addr: ":" + util.GetEnv("METRICS_PORT", "9091"), Handler: g}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			f.Log.Error("error serve metrics", zap.Error(err))
+		}
+	}()
+
+	return server
+}
@@ -0,0 +1,88 @@
+package tracing
+
+import (
+	"context"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+	"os"
+	"strconv"
+	"test-edot/util"
+)
+
+const TracerName = "test-edot"
+
+// tracer is taken from the global provider, spans started before Init are dropped and the ones after go to the exporter
+var tracer = otel.Tracer(TracerName)
+
+// Init selects the exporter from OTEL_TRACES_EXPORTER, "stdout" prints the spans, "file" appends them as json lines
+// to OTEL_TRACES_FILE, "otlp" sends them to the collector at OTEL_EXPORTER_OTLP_ENDPOINT and anything else keeps
+// tracing off. The returned shutdown flushes the spans still in the batch
+func Init(ctx context.Context) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var (
+		exporter sdktrace.SpanExporter
+		file     *os.File
+		err      error
+	)
+
+	switch util.GetEnv("OTEL_TRACES_EXPORTER", "none") {
+	case "stdout":
+		exporter, err = stdouttrace.New(stdouttrace.WithPrettyPrint())
+	case "file":
+		file, err = os.OpenFile(util.GetEnv("OTEL_TRACES_FILE", "traces.json"), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			return nil, err
+		}
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(file))
+	case "otlp":
+		exporter, err = otlptracehttp.New(ctx)
+	default:
+		return func(context.Context) error { return nil }, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ratio, err := strconv.ParseFloat(util.GetEnv("OTEL_TRACES_SAMPLER_RATIO", "1"), 64)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL,
+			semconv.ServiceName(util.GetEnv("OTEL_SERVICE_NAME", TracerName)),
+		)),
+		// a request that comes with a sampled parent is always traced
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if file != nil {
+			file.Close()
+		}
+
+		return err
+	}, nil
+}
+
+// Start starts the span of a service call, the handlers pass the gin context which reaches the span of the
+// request because the engine has ContextWithFallback on. With tracing off the span is the one already in ctx,
+// so ctx is returned as it is
+func Start(ctx context.Context, name string) (context.Context, trace.Span) {
+	spanCtx, span := tracer.Start(ctx, name)
+	if span.SpanContext().Equal(trace.SpanContextFromContext(ctx)) {
+		return ctx, span
+	}
+
+	return spanCtx, span
+}
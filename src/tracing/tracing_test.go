@@ -0,0 +1,73 @@
+package tracing
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestStart(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// the global provider can only be set once, so tracing off is checked first
+	t.Run("test tracing off keeps the context", func(t *testing.T) {
+		ctx := context.Background()
+		spanCtx, span := Start(ctx, "service")
+		defer span.End()
+
+		assert.Equal(t, ctx, spanCtx)
+		assert.False(t, span.IsRecording())
+	})
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	defer provider.Shutdown(context.Background())
+
+	t.Run("test service span under the request span of the gin context", func(t *testing.T) {
+		requestCtx, requestSpan := tracer.Start(context.Background(), "request")
+
+		c, engine := gin.CreateTestContext(httptest.NewRecorder())
+		engine.ContextWithFallback = true
+		c.Request = httptest.NewRequest(http.MethodGet, "/", nil).WithContext(requestCtx)
+
+		_, span := Start(c, "service")
+		span.End()
+		requestSpan.End()
+
+		assert.Equal(t, requestSpan.SpanContext().TraceID(), span.SpanContext().TraceID())
+		assert.Equal(t, requestSpan.SpanContext().SpanID(), recorder.Ended()[0].Parent().SpanID())
+	})
+
+	t.Run("test goroutine spans under the span of the call", func(t *testing.T) {
+		ctx, span := Start(context.Background(), "call")
+
+		var wg sync.WaitGroup
+		for _, name := range []string{"warehouse", "stock"} {
+			wg.Add(1)
+			go func(name string) {
+				defer wg.Done()
+
+				_, child := Start(ctx, "call."+name)
+				child.End()
+			}(name)
+		}
+		wg.Wait()
+		span.End()
+
+		children := 0
+		for _, ended := range recorder.Ended() {
+			if ended.Parent().SpanID() == span.SpanContext().SpanID() {
+				children++
+			}
+		}
+		assert.Equal(t, 2, children)
+	})
+}
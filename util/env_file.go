@@ -3,6 +3,7 @@ package util
 import (
 	"github.com/joho/godotenv"
 	"os"
+	"strings"
 )
 
 func GetEnv(key string, fallback string) string {
@@ -24,6 +25,19 @@ func GetEnv(key string, fallback string) string {
 	return val
 }
 
+// TrustedProxies is the TRUSTED_PROXIES comma separated list of proxy ips or cidrs whose X-Forwarded-For is
+// believed, nil when it is empty so the client ip is always the address of the connection
+func TrustedProxies() []string {
+	var proxies []string
+	for _, proxy := range strings.Split(GetEnv("TRUSTED_PROXIES", ""), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+
+	return proxies
+}
+
 func GetEnvTest(key string, fallback string) string {
 	// Godotenv read the .env file on the root folder
 	a, _ := godotenv.Read("../.env")
@@ -1,10 +1,14 @@
 package util
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"github.com/golang-jwt/jwt/v5"
 	"golang.org/x/crypto/bcrypt"
+	"math/big"
 	"test-edot/constants"
 	"test-edot/src/dto"
 	"test-edot/src/models"
@@ -26,8 +30,12 @@ func GetClaim(userClaim map[string]interface{}) dto.UserClaimJwt {
 	}
 }
 
-func GenerateJWT(user models.User) (string, error) {
-	secretKey := []byte(GetEnv("JWT_SECRET_KEY", ""))
+// GenerateJWT issues a short lived access token, the jti identifies the token in the revocation list
+func GenerateJWT(user models.User, jti string) (string, error) {
+	keySet, err := getJWTKeys()
+	if err != nil {
+		return "", err
+	}
 
 	userClaims := dto.UserClaimJwt{
 		UserId: user.Id,
@@ -37,14 +45,21 @@ func GenerateJWT(user models.User) (string, error) {
 	claims := jwt.MapClaims{
 		"authorized": true,
 		"userClaim":  userClaims,
-		"exp":        time.Now().Add(time.Hour * 3).Unix(), // Token berlaku selama 1 jam
+		"jti":        jti,
+		"exp":        time.Now().Add(constants.ACCESS_TOKEN_TTL).Unix(),
 	}
 
-	// Membuat token dengan algoritma signing HMAC SHA256 dan klaim yang sudah diset
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	// without a key set the token is signed with HMAC SHA256 and the shared secret
+	if keySet == nil {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		return token.SignedString([]byte(GetEnv("JWT_SECRET_KEY", "")))
+	}
 
-	// Menandatangani token dengan kunci rahasia
-	tokenString, err := token.SignedString(secretKey)
+	// kid names the public key that verifies the token, see /.well-known/jwks.json
+	token := jwt.NewWithClaims(keySet.Signing.Method, claims)
+	token.Header["kid"] = keySet.Signing.Kid
+
+	tokenString, err := token.SignedString(keySet.Signing.Private)
 	if err != nil {
 		return "", err
 	}
@@ -52,14 +67,31 @@ func GenerateJWT(user models.User) (string, error) {
 	return tokenString, nil
 }
 
+// ValidateJWT verifies the token with the key of its kid, the shared secret is only accepted when no key set is configured
 func ValidateJWT(tokenString string) (jwt.MapClaims, error) {
-	secretKey := []byte(GetEnv("JWT_SECRET_KEY", ""))
+	keySet, err := getJWTKeys()
+	if err != nil {
+		return nil, err
+	}
 
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		if keySet == nil {
+			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+			}
+			return []byte(GetEnv("JWT_SECRET_KEY", "")), nil
+		}
+
+		kid, _ := token.Header["kid"].(string)
+		key, ok := keySet.Keys[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown key id: %v", token.Header["kid"])
+		}
+
+		if token.Method.Alg() != key.Method.Alg() {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
-		return secretKey, nil
+		return key.Public, nil
 	})
 
 	if err != nil {
@@ -77,3 +109,34 @@ func ValidateJWT(tokenString string) (jwt.MapClaims, error) {
 		return nil, fmt.Errorf("invalid token")
 	}
 }
+
+// GenerateRandomToken returns a random hex string of n bytes, used for jti and refresh tokens
+func GenerateRandomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+// GenerateOTP returns a random numeric code of n digits
+func GenerateOTP(n int) (string, error) {
+	code := make([]byte, n)
+	for i := range code {
+		digit, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+
+		code[i] = byte('0' + digit.Int64())
+	}
+
+	return string(code), nil
+}
+
+// HashToken is the stored form of a refresh token, the raw token is only known by the client
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
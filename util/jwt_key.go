@@ -0,0 +1,219 @@
+package util
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+type (
+	// JWTKey is a key of the key set, Private is nil for a retired key that only verifies tokens
+	JWTKey struct {
+		Kid     string
+		Method  jwt.SigningMethod
+		Private crypto.Signer
+		Public  crypto.PublicKey
+	}
+
+	// JWTKeySet holds every key able to verify tokens and the key used to sign new tokens,
+	// rotating a key is adding a new signing key and keeping the old one until its tokens expire
+	JWTKeySet struct {
+		Keys    map[string]*JWTKey
+		Signing *JWTKey
+	}
+
+	JWK struct {
+		Kty string `json:"kty"`
+		Kid string `json:"kid"`
+		Use string `json:"use"`
+		Alg string `json:"alg"`
+		N   string `json:"n,omitempty"`
+		E   string `json:"e,omitempty"`
+		Crv string `json:"crv,omitempty"`
+		X   string `json:"x,omitempty"`
+	}
+
+	JWKS struct {
+		Keys []JWK `json:"keys"`
+	}
+)
+
+var (
+	jwtKeySet     *JWTKeySet
+	jwtKeySetErr  error
+	jwtKeySetOnce sync.Once
+)
+
+// InitJWTKeys loads the key set from JWT_KEYS_DIR, without the directory tokens are signed with JWT_SECRET_KEY
+func InitJWTKeys() error {
+	jwtKeySetOnce.Do(func() {
+		dir := GetEnv("JWT_KEYS_DIR", "")
+		if dir == "" {
+			return
+		}
+
+		jwtKeySet, jwtKeySetErr = LoadJWTKeys(dir, GetEnv("JWT_SIGNING_KID", ""))
+	})
+
+	return jwtKeySetErr
+}
+
+// UseJWTKeys replaces the key set used to sign and verify tokens
+func UseJWTKeys(keySet *JWTKeySet) {
+	jwtKeySetOnce.Do(func() {})
+	jwtKeySet, jwtKeySetErr = keySet, nil
+}
+
+func getJWTKeys() (*JWTKeySet, error) {
+	if err := InitJWTKeys(); err != nil {
+		return nil, err
+	}
+
+	return jwtKeySet, nil
+}
+
+// LoadJWTKeys reads every <kid>.pem private key and <kid>.pub.pem public key of the directory,
+// a <kid>.pub.pem next to its private key is only checked to match it,
+// the signing key is signingKid or the last private key by name when it is empty
+func LoadJWTKeys(dir, signingKid string) (*JWTKeySet, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(files)
+	keySet := &JWTKeySet{Keys: make(map[string]*JWTKey)}
+	for _, file := range files {
+		key, err := loadJWTKey(file)
+		if err != nil {
+			return nil, fmt.Errorf("load jwt key %s: %w", file, err)
+		}
+
+		if loaded, ok := keySet.Keys[key.Kid]; ok {
+			if (loaded.Private == nil) == (key.Private == nil) {
+				return nil, fmt.Errorf("duplicate jwt key id %s", key.Kid)
+			}
+			if !samePublicKey(loaded.Public, key.Public) {
+				return nil, fmt.Errorf("jwt public key %s does not match its private key", key.Kid)
+			}
+			if loaded.Private != nil {
+				continue
+			}
+		}
+
+		keySet.Keys[key.Kid] = key
+		if key.Private != nil && signingKid == "" {
+			keySet.Signing = key
+		}
+	}
+
+	if signingKid != "" {
+		keySet.Signing = keySet.Keys[signingKid]
+	}
+
+	if keySet.Signing == nil || keySet.Signing.Private == nil {
+		return nil, errors.New("no private jwt key to sign tokens")
+	}
+
+	return keySet, nil
+}
+
+func samePublicKey(a, b crypto.PublicKey) bool {
+	key, ok := a.(interface{ Equal(crypto.PublicKey) bool })
+	return ok && key.Equal(b)
+}
+
+func loadJWTKey(file string) (*JWTKey, error) {
+	raw, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, errors.New("invalid pem")
+	}
+
+	name := filepath.Base(file)
+	key := &JWTKey{Kid: strings.TrimSuffix(strings.TrimSuffix(name, ".pem"), ".pub")}
+
+	var parsed any
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported pem type %s", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.Method, key.Private, key.Public = jwt.SigningMethodRS256, k, &k.PublicKey
+	case ed25519.PrivateKey:
+		key.Method, key.Private, key.Public = jwt.SigningMethodEdDSA, k, k.Public()
+	case *rsa.PublicKey:
+		key.Method, key.Public = jwt.SigningMethodRS256, k
+	case ed25519.PublicKey:
+		key.Method, key.Public = jwt.SigningMethodEdDSA, k
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", parsed)
+	}
+
+	return key, nil
+}
+
+// PublicJWKS is the public part of the key set, empty when tokens are signed with the shared secret
+func PublicJWKS() (JWKS, error) {
+	keySet, err := getJWTKeys()
+	if err != nil {
+		return JWKS{}, err
+	}
+
+	jwks := JWKS{Keys: []JWK{}}
+	if keySet == nil {
+		return jwks, nil
+	}
+
+	kids := make([]string, 0, len(keySet.Keys))
+	for kid := range keySet.Keys {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+
+	for _, kid := range kids {
+		key := keySet.Keys[kid]
+		jwk := JWK{Kid: key.Kid, Use: "sig", Alg: key.Method.Alg()}
+		switch pub := key.Public.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		}
+
+		jwks.Keys = append(jwks.Keys, jwk)
+	}
+
+	return jwks, nil
+}
@@ -0,0 +1,175 @@
+package util
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"test-edot/src/models"
+	"testing"
+)
+
+func writeKey(t *testing.T, dir, name, pemType string, der []byte) {
+	err := os.WriteFile(filepath.Join(dir, name), pem.EncodeToMemory(&pem.Block{Type: pemType, Bytes: der}), 0600)
+	assert.NoError(t, err)
+}
+
+type (
+	TestJWTKeyRotationData struct {
+		name       string
+		signingKid string
+		verifyKid  string
+		expectAlg  string
+		expectErr  bool
+	}
+)
+
+func TestJWTKeyRotation(t *testing.T) {
+	dir := t.TempDir()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	writeKey(t, dir, "key-a.pem", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey))
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(edKey)
+	assert.NoError(t, err)
+	writeKey(t, dir, "key-b.pem", "PRIVATE KEY", der)
+
+	retiredKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	der, err = x509.MarshalPKIXPublicKey(&retiredKey.PublicKey)
+	assert.NoError(t, err)
+	writeKey(t, dir, "key-old.pub.pem", "PUBLIC KEY", der)
+
+	tableTests := []TestJWTKeyRotationData{
+		{
+			name:      "test default signing key is last private key",
+			verifyKid: "key-b",
+			expectAlg: "EdDSA",
+		},
+		{
+			name:       "test token signed by rotated key still valid",
+			signingKid: "key-a",
+			verifyKid:  "key-b",
+			expectAlg:  "RS256",
+		},
+		{
+			name:       "test public key can not sign",
+			signingKid: "key-old",
+			expectErr:  true,
+		},
+		{
+			name:       "test unknown signing key",
+			signingKid: "key-c",
+			expectErr:  true,
+		},
+	}
+
+	defer UseJWTKeys(nil)
+	for _, test := range tableTests {
+		t.Run(test.name, func(t *testing.T) {
+			keySet, err := LoadJWTKeys(dir, test.signingKid)
+			if test.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, keySet.Keys, 3)
+
+			UseJWTKeys(keySet)
+			token, err := GenerateJWT(models.User{Id: 1, Role: "user"}, "jti-test")
+			assert.NoError(t, err)
+
+			parsed, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectAlg, parsed.Method.Alg())
+			assert.Equal(t, keySet.Signing.Kid, parsed.Header["kid"])
+
+			// the next signing key is rolled out, tokens of the previous key keep working
+			next, err := LoadJWTKeys(dir, test.verifyKid)
+			assert.NoError(t, err)
+			UseJWTKeys(next)
+
+			claims, err := ValidateJWT(token)
+			assert.NoError(t, err)
+			assert.Equal(t, "jti-test", claims["jti"])
+
+			jwks, err := PublicJWKS()
+			assert.NoError(t, err)
+			assert.Len(t, jwks.Keys, 3)
+		})
+	}
+}
+
+func TestValidateJWTRejectsUnknownKey(t *testing.T) {
+	t.Setenv("JWT_SECRET_KEY", "secret-test")
+	UseJWTKeys(nil)
+
+	// token signed with the shared secret must not pass once a key set is configured
+	token, err := GenerateJWT(models.User{Id: 1, Role: "user"}, "jti-test")
+	assert.NoError(t, err)
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	UseJWTKeys(&JWTKeySet{
+		Keys: map[string]*JWTKey{"key-a": {Kid: "key-a", Method: jwt.SigningMethodEdDSA, Private: edKey, Public: edKey.Public()}},
+	})
+	defer UseJWTKeys(nil)
+
+	_, err = ValidateJWT(token)
+	assert.Error(t, err)
+}
+
+type (
+	TestLoadJWTKeysPairData struct {
+		name      string
+		publicKey *rsa.PublicKey
+		expectErr bool
+	}
+)
+
+func TestLoadJWTKeysPair(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	tableTests := []TestLoadJWTKeysPairData{
+		{
+			name:      "test public key next to its private key",
+			publicKey: &rsaKey.PublicKey,
+		},
+		{
+			name:      "test public key of another private key",
+			publicKey: &otherKey.PublicKey,
+			expectErr: true,
+		},
+	}
+
+	for _, test := range tableTests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeKey(t, dir, "key-a.pem", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey))
+			der, err := x509.MarshalPKIXPublicKey(test.publicKey)
+			assert.NoError(t, err)
+			writeKey(t, dir, "key-a.pub.pem", "PUBLIC KEY", der)
+
+			keySet, err := LoadJWTKeys(dir, "")
+			if test.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, keySet.Keys, 1)
+			assert.Equal(t, "key-a", keySet.Signing.Kid)
+			assert.NotNil(t, keySet.Signing.Private)
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package util
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type loggerKey struct{}
+
+// ContextWithLogger carries the logger of the request, the logger has the request id as a field
+func ContextWithLogger(ctx context.Context, log *zap.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, log)
+}
+
+// Logger is the logger carried by ctx, calls outside of a request like the scheduler get the fallback
+func Logger(ctx context.Context, fallback *zap.Logger) *zap.Logger {
+	if ctx == nil {
+		return fallback
+	}
+
+	if log, ok := ctx.Value(loggerKey{}).(*zap.Logger); ok {
+		return log
+	}
+
+	// handlers pass the gin context, its values do not reach the context of the request
+	if g, ok := ctx.Value(gin.ContextKey).(*gin.Context); ok && g.Request != nil {
+		if log, ok := g.Request.Context().Value(loggerKey{}).(*zap.Logger); ok {
+			return log
+		}
+	}
+
+	return fallback
+}
+
+// TxContext is the context the transaction was begun with, for the calls that only get the transaction
+func TxContext(tx *gorm.DB) context.Context {
+	if tx == nil || tx.Statement == nil || tx.Statement.Context == nil {
+		return context.Background()
+	}
+
+	return tx.Statement.Context
+}
@@ -11,3 +11,15 @@ func CreateOrderNo() string {
 	orderNo := fmt.Sprintf("TEDT-%s%s", now.Format("20060102"), strconv.Itoa(int(now.Unix())))
 	return orderNo
 }
+
+func CreatePurchaseOrderNo() string {
+	now := time.Now()
+	poNo := fmt.Sprintf("TEDT-PO-%s%s", now.Format("20060102"), strconv.Itoa(int(now.Unix())))
+	return poNo
+}
+
+func CreateTransferNo() string {
+	now := time.Now()
+	transferNo := fmt.Sprintf("TEDT-TR-%s%s", now.Format("20060102"), strconv.Itoa(int(now.Unix())))
+	return transferNo
+}
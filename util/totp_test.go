@@ -0,0 +1,66 @@
+package util
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+type (
+	TestValidateTOTPData struct {
+		name     string
+		at       time.Time
+		lastStep int64
+		expectOk bool
+	}
+)
+
+func TestTOTPCode(t *testing.T) {
+	// RFC 6238 appendix B, secret "12345678901234567890", the 6 digits are the tail of the 8 digits vectors
+	secret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+	for unix, expect := range map[int64]string{59: "287082", 1111111109: "081804", 1234567890: "005924", 2000000000: "279037"} {
+		code, err := TOTPCode(secret, TOTPStep(time.Unix(unix, 0)))
+		assert.NoError(t, err)
+		assert.Equal(t, expect, code)
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	secret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+	issuedAt := time.Unix(1111111109, 0)
+	code, _ := TOTPCode(secret, TOTPStep(issuedAt))
+
+	tableTests := []TestValidateTOTPData{
+		{
+			name:     "test current step",
+			at:       issuedAt,
+			expectOk: true,
+		},
+		{
+			name:     "test previous step for clock drift",
+			at:       issuedAt.Add(30 * time.Second),
+			expectOk: true,
+		},
+		{
+			name: "test expired code",
+			at:   issuedAt.Add(90 * time.Second),
+		},
+		{
+			name:     "test replayed code",
+			at:       issuedAt,
+			lastStep: TOTPStep(issuedAt),
+		},
+	}
+
+	for _, test := range tableTests {
+		t.Run(test.name, func(t *testing.T) {
+			step, ok := ValidateTOTP(secret, code, test.at, test.lastStep)
+
+			assert.Equal(t, test.expectOk, ok)
+			if test.expectOk {
+				assert.Equal(t, TOTPStep(issuedAt), step)
+			}
+		})
+	}
+}